@@ -1,5 +1,17 @@
 package ahocorasick
 
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/notJoon/searcher/byteclass"
+)
+
 // ACMatch represents pattern matching information found in text
 type ACMatch struct {
 	PatternIndex int // which pattern in keywords
@@ -7,43 +19,190 @@ type ACMatch struct {
 	End          int // end index of the match (inclusive)
 }
 
+// MatchKind selects which matches FindAll and its relatives report
+type MatchKind int
+
+const (
+	// Standard reports every match of every pattern, including overlapping
+	// and nested matches, as soon as it ends.
+	Standard MatchKind = iota
+	// LeftmostLongest reports non-overlapping matches: among the matches starting
+	// at the leftmost position the longest one wins, and the scan resumes after it.
+	LeftmostLongest
+	// LeftmostFirst reports non-overlapping matches: among the matches starting
+	// at the leftmost position the pattern given first to New wins, and the scan
+	// resumes after it.
+	LeftmostFirst
+)
+
+// Options configures an AhoCorasick automaton created by NewWithOptions
+type Options struct {
+	IgnoreCase bool      // case-insensitive matching: ASCII only, or Unicode with Runes
+	MatchKind  MatchKind // match semantics, Standard by default
+	Backend    Backend   // trie transition storage, ArrayOfArrays by default
+	// Runes makes Start and End rune indices instead of byte offsets, and makes IgnoreCase
+	// fold every rune with unicode.ToLower instead of only ASCII letters. Invalid UTF-8
+	// bytes count as one rune each. The automaton still runs over bytes, so each search
+	// first decodes and lowercases the text into a copy and records two offset mappings
+	// per byte. That costs about 17 extra bytes of allocation per byte of text and makes a
+	// scan with few matches around four times slower (see BenchmarkFindAllRunes); prefer
+	// byte mode when byte offsets will do.
+	Runes bool
+	// Prefilter makes the search skip over text that cannot start a match eight bytes at a
+	// time, comparing whole words of the text against the bytes that start a keyword, when
+	// there are at most maxPrefilterBytes of them (counting both cases under IgnoreCase).
+	// It pays off when those bytes are rare in the text, as for keywords starting with
+	// punctuation or an uncommon letter, and costs a little when they are common, since
+	// every word holding one falls back to the byte-by-byte skip (see BenchmarkPrefilter).
+	// With more start bytes it has no effect.
+	Prefilter bool
+	// Normalize, if set, maps every rune of the patterns and the text to its normalized form
+	// before IgnoreCase lowercases it, so that equivalent spellings match: with a function
+	// returning the NFKD decomposition of a rune, "ﬁ" matches "fi" and a precomposed "é"
+	// matches "e" followed by a combining accent. With golang.org/x/text/unicode/norm,
+	// which this module does not depend on, that function is
+	// func(r rune) string { return norm.NFKD.String(string(r)) }.
+	// Runes are normalized one at a time, so only decompositions are possible: NFC or NFKC
+	// composition, which merges a combining sequence of the text such as "e" plus U+0301
+	// into one "é", needs the neighbouring runes and cannot be expressed; decompose instead,
+	// and precomposed and combining spellings meet in decomposed form. Normalize implies
+	// Runes, and Start and End still refer to runes of the original text; a keyword matching only
+	// part of a rune's normalized form, such as "f" in "ﬁ", covers that whole rune.
+	// An automaton with a Normalize function cannot be serialized.
+	Normalize func(rune) string
+	// Wildcards maps sentinel bytes to the sets of bytes they stand for: wherever a pattern
+	// contains a key of the map, that position matches any one byte of its set. With
+	// Wildcards: map[byte]byteclass.Set{'#': byteclass.Range('0', '9')}, "error:###" matches
+	// "error:404" but not "error:4x4". The trie branches on every byte of the set, so failure
+	// links need no special handling, but a pattern costs up to the product of its set sizes
+	// in trie nodes: keep wide sets such as byteclass.Any() few. Sentinels are looked up in
+	// the folded pattern, so under IgnoreCase they should not be letters; the bytes of a set
+	// are folded like text bytes. A sentinel byte only matches itself if its set contains it.
+	Wildcards map[byte]byteclass.Set
+	// TokenBoundary, if set, makes the automaton report only matches standing as tokens of
+	// their own: the byte just before Start and the byte just after End must each be a text
+	// edge or satisfy TokenBoundary. Every search honours it, including Contains, Count,
+	// FindAllFunc, MatchPrefix and the Scanner, and under the leftmost match kinds only token
+	// matches compete, so a match inside a longer word never hides one standing alone. In rune
+	// mode the bytes tested are those around the matched runes in the original text. With
+	// func(c byte) bool { return !IsWordByte(c) } the automaton finds whole words, like
+	// grep -w. An automaton with a TokenBoundary function cannot be serialized.
+	TokenBoundary func(byte) bool
+}
+
 // AhoCorasick is a struct that contains Aho-Corasick automaton for multiple pattern search
 type AhoCorasick struct {
+	patterns   []string // patterns as given, kept so that Rebuild can fold them differently
 	keywords   [][]byte // patterns (may already be converted to lowercase)
 	ignoreCase bool
+	runes      bool // rune offsets and Unicode case folding
+	kind       MatchKind
+	backend    Backend
+	prefilter  bool                   // Options.Prefilter
+	normalize  func(rune) string      // Options.Normalize, applied in rune mode
+	wildcards  map[byte]byteclass.Set // Options.Wildcards
+	// Options.TokenBoundary, checked through the accept function of every scan
+	tokenBoundary func(byte) bool
 
 	// trie nodes. node 0 is root.
-	// ex: next[node][c] = transition
+	// ex: next[node][c] = transition (ArrayOfArrays backend)
+	// edges[node][c] = trie edge (SparseMap backend)
 	// fail[node] = failure link
 	// out[node] = list of pattern indices that end at this node
-	next [][256]int
-	fail []int
-	out  [][]int
+	// depth[node] = length of the string spelled from root to node
+	next  [][256]int
+	edges []map[byte]int
+	fail  []int
+	out   [][]int
+	depth []int
+
+	// DoubleArray backend: the edge node -c-> nx is in slot base[node]+c, which has
+	// check = node+1 (0 marks a free slot) and target = nx. The free slots below len(check)
+	// form a doubly linked list through freeNext and freePrev starting at freeHead; links
+	// hold a slot index plus one, so that 0 ends the list. Slots past the end are free too.
+	base     []int32
+	check    []int32
+	target   []int32
+	freeNext []int32
+	freePrev []int32
+	freeHead int
+
+	// leavesRoot[c] is true if text byte c moves the automaton out of the root, i.e. can begin
+	// a match; the search loops skip over the other bytes while at the root
+	leavesRoot [256]bool
+	// startWords holds each byte that leaves the root repeated eight times, for the prefilter;
+	// numStartBytes is how many there are, or 0 if the prefilter is off or there are too many
+	startWords    [maxPrefilterBytes]uint64
+	numStartBytes int
+
+	// minLen and maxLen are the lengths of the shortest and longest non-empty keywords,
+	// in the units of Start and End; both are 0 if there is none
+	minLen, maxLen int
 }
 
-// New creates and returns an AhoCorasick struct with multiple patterns
+// Errors returned by NewWithError.
+var (
+	ErrEmptyPattern     = errors.New("ahocorasick: empty pattern")
+	ErrDuplicatePattern = errors.New("ahocorasick: duplicate pattern")
+)
+
+// New creates and returns an AhoCorasick struct with multiple patterns.
+// Patterns are taken as given: a duplicate pattern reports each match once per copy,
+// and an empty pattern is accepted but never matches. Use NewWithError to have them
+// reported instead, or NewDedup to merge duplicates.
 func New(patterns []string, ignoreCase bool) *AhoCorasick {
-	// Store keywords: if ignoreCase option is true, convert all to lowercase internally
-	var kw [][]byte
-	for _, p := range patterns {
-		b := []byte(p)
+	return NewWithOptions(patterns, Options{IgnoreCase: ignoreCase})
+}
+
+// NewWithError is like New but validates the patterns first. It returns an error wrapping
+// ErrEmptyPattern for an empty pattern, or ErrDuplicatePattern for a pattern equal to an
+// earlier one (after case folding if ignoreCase is true).
+func NewWithError(patterns []string, ignoreCase bool) (*AhoCorasick, error) {
+	seen := make(map[string]int, len(patterns))
+	for idx, p := range patterns {
+		if p == "" {
+			return nil, fmt.Errorf("%w at index %d", ErrEmptyPattern, idx)
+		}
+		k := p
 		if ignoreCase {
-			for i := range b {
-				if b[i] >= 'A' && b[i] <= 'Z' {
-					b[i] = b[i] + ('a' - 'A')
-				}
-			}
+			k = string(foldASCII([]byte(p)))
+		}
+		if prev, ok := seen[k]; ok {
+			return nil, fmt.Errorf("%w: %q at index %d repeats index %d", ErrDuplicatePattern, p, idx, prev)
 		}
-		kw = append(kw, b)
+		seen[k] = idx
 	}
+	return New(patterns, ignoreCase), nil
+}
 
-	ac := &AhoCorasick{
-		keywords:   kw,
-		ignoreCase: ignoreCase,
-		// initially trie is empty, so allocate 1 node (root)
-		next: make([][256]int, 1),
-		fail: make([]int, 1),
-		out:  make([][]int, 1),
+// NewFromBytes is like New but takes the patterns as byte slices. It takes ownership of
+// them: they become the automaton's keywords, lowercased in place if ignoreCase is true,
+// so the caller must not use or modify them afterwards. The patterns as given are still
+// kept as strings for Rebuild and MarshalBinary, so this saves one of the two copies
+// New makes of each pattern.
+func NewFromBytes(patterns [][]byte, ignoreCase bool) *AhoCorasick {
+	opts := Options{IgnoreCase: ignoreCase}
+	ac := newEmpty(opts)
+	ac.patterns = make([]string, len(patterns))
+	ac.keywords = make([][]byte, len(patterns))
+	for i, p := range patterns {
+		ac.patterns[i] = string(p)
+		ac.keywords[i] = foldPatternBytes(p, opts)
+	}
+
+	ac.buildTrie()
+	ac.buildFailureLinks()
+	return ac
+}
+
+// NewWithOptions creates and returns an AhoCorasick struct with multiple patterns configured by opts
+func NewWithOptions(patterns []string, opts Options) *AhoCorasick {
+	ac := newEmpty(opts)
+
+	// Store keywords: if ignoreCase option is true, convert all to lowercase internally
+	ac.patterns = append([]string(nil), patterns...)
+	for _, p := range patterns {
+		ac.keywords = append(ac.keywords, foldPattern(p, opts))
 	}
 
 	ac.buildTrie()
@@ -51,26 +210,258 @@ func New(patterns []string, ignoreCase bool) *AhoCorasick {
 	return ac
 }
 
-// FindAll finds all pattern matches (ACMatch) in text using Aho-Corasick
+// newEmpty returns an automaton configured by opts whose trie has only the root
+func newEmpty(opts Options) *AhoCorasick {
+	ac := &AhoCorasick{
+		ignoreCase:    opts.IgnoreCase,
+		runes:         opts.Runes || opts.Normalize != nil,
+		kind:          opts.MatchKind,
+		backend:       opts.Backend,
+		prefilter:     opts.Prefilter,
+		normalize:     opts.Normalize,
+		wildcards:     opts.Wildcards,
+		tokenBoundary: opts.TokenBoundary,
+		// initially trie is empty, so allocate 1 node (root)
+		fail:  make([]int, 1),
+		out:   make([][]int, 1),
+		depth: make([]int, 1),
+	}
+	switch ac.backend {
+	case SparseMap:
+		ac.edges = make([]map[byte]int, 1)
+	case DoubleArray:
+		ac.base = []int32{-1}
+	default:
+		ac.next = make([][256]int, 1)
+	}
+	return ac
+}
+
+// NewDedup is like NewWithOptions but keeps only the first copy of each distinct pattern
+// (compared after case folding if opts.IgnoreCase is set), so every match is reported once.
+// index maps the caller's pattern positions to PatternIndex values: index[i] is the
+// PatternIndex reported for patterns[i], and duplicates share the index of their first copy.
+// Distinct patterns keep their relative order.
+func NewDedup(patterns []string, opts Options) (ac *AhoCorasick, index []int) {
+	index = make([]int, len(patterns))
+	seen := make(map[string]int, len(patterns))
+	var unique []string
+	for i, p := range patterns {
+		k := string(foldPattern(p, opts))
+		idx, ok := seen[k]
+		if !ok {
+			idx = len(unique)
+			seen[k] = idx
+			unique = append(unique, p)
+		}
+		index[i] = idx
+	}
+	return NewWithOptions(unique, opts), index
+}
+
+// FindAll finds all pattern matches (ACMatch) in text using Aho-Corasick,
+// following the automaton's MatchKind.
+//
+// The order of the matches is part of the contract. Under Standard semantics they are
+// ordered by End; matches ending at the same position by Start, i.e. longest first, which is
+// the order the automaton's failure links inherit them in; and matches of identical patterns
+// by PatternIndex. For "he", "she", "hers" in "ushers" that is she [1..3], he [2..3],
+// hers [2..5]. The leftmost kinds never report overlapping matches, so their matches are
+// ordered by Start and End alike. FindAllSorted orders by Start first instead.
 func (ac *AhoCorasick) FindAll(text string) []ACMatch {
-	return ac._findAll([]byte(text))
+	return ac._findAll([]byte(text), ac.kind, nil)
 }
 
-// FindAllBytes finds all pattern matches (ACMatch) in byte slice using Aho-Corasick
+// FindAllBytes finds all pattern matches (ACMatch) in byte slice using Aho-Corasick,
+// following the automaton's MatchKind, in the same order as FindAll
 func (ac *AhoCorasick) FindAllBytes(data []byte) []ACMatch {
-	return ac._findAll(data)
+	return ac._findAll(data, ac.kind, nil)
+}
+
+// FindAllInto appends the matches FindAll would return to dst, in the same order, and returns
+// the extended slice, like append. Passing dst[:0] reuses dst's storage, so a buffer kept
+// across calls (or taken from a MatchPool) lets many texts be searched without allocating
+// once it is large enough, except in rune mode, where the folded haystack is still built.
+func (ac *AhoCorasick) FindAllInto(text string, dst []ACMatch) []ACMatch {
+	return ac._findAllInto([]byte(text), ac.kind, nil, dst)
+}
+
+// FindAllIntoBytes is FindAllInto for a byte slice
+func (ac *AhoCorasick) FindAllIntoBytes(data []byte, dst []ACMatch) []ACMatch {
+	return ac._findAllInto(data, ac.kind, nil, dst)
+}
+
+// FindAllSorted is FindAll with the matches sorted by Start, then End, then PatternIndex,
+// which suits processing them left to right. FindAll itself reports Standard matches in the
+// order they end in the text (see FindAll).
+func (ac *AhoCorasick) FindAllSorted(text string) []ACMatch {
+	return sortMatches(ac._findAll([]byte(text), ac.kind, nil))
+}
+
+// FindAllSortedBytes is FindAllBytes with the matches sorted by Start, then End, then PatternIndex
+func (ac *AhoCorasick) FindAllSortedBytes(data []byte) []ACMatch {
+	return sortMatches(ac._findAll(data, ac.kind, nil))
+}
+
+// sortMatches sorts ms in place by Start, then End, then PatternIndex and returns it
+func sortMatches(ms []ACMatch) []ACMatch {
+	slices.SortFunc(ms, func(a, b ACMatch) int {
+		return cmp.Or(cmp.Compare(a.Start, b.Start), cmp.Compare(a.End, b.End), cmp.Compare(a.PatternIndex, b.PatternIndex))
+	})
+	return ms
+}
+
+// FindAllFunc calls fn for each pattern match in text, in the order FindAll would return them,
+// without collecting them into a slice. The search stops early if fn returns false.
+func (ac *AhoCorasick) FindAllFunc(text string, fn func(ACMatch) bool) {
+	ac._findAllFunc([]byte(text), ac.kind, nil, fn)
+}
+
+// FindAllFuncBytes calls fn for each pattern match in the byte slice, in the order FindAllBytes
+// would return them. The search stops early if fn returns false.
+func (ac *AhoCorasick) FindAllFuncBytes(data []byte, fn func(ACMatch) bool) {
+	ac._findAllFunc(data, ac.kind, nil, fn)
+}
+
+// FindAllCapped is FindAll keeping only the first capPerPattern matches of each pattern, in
+// FindAll's order, which bounds the result on inputs where a short keyword matches everywhere.
+// Capped patterns are still tracked by the automaton, so under the leftmost kinds the other
+// patterns' matches are exactly those FindAll reports; the scan ends early once every
+// pattern has reached its cap. As with strings.SplitN, a negative capPerPattern means no
+// limit and zero returns nil.
+func (ac *AhoCorasick) FindAllCapped(text string, capPerPattern int) []ACMatch {
+	return ac._findAllCapped([]byte(text), capPerPattern)
+}
+
+// FindAllCappedBytes is FindAllCapped for a byte slice
+func (ac *AhoCorasick) FindAllCappedBytes(data []byte, capPerPattern int) []ACMatch {
+	return ac._findAllCapped(data, capPerPattern)
+}
+
+// _findAllCapped drops the matches of patterns that are over the cap as they are reported
+func (ac *AhoCorasick) _findAllCapped(data []byte, capPerPattern int) []ACMatch {
+	if capPerPattern < 0 {
+		return ac._findAll(data, ac.kind, nil)
+	}
+	if capPerPattern == 0 {
+		return nil
+	}
+
+	counts := make([]int, len(ac.keywords))
+	open := 0 // patterns that can match and are below the cap
+	for _, kw := range ac.keywords {
+		if len(kw) > 0 {
+			open++
+		}
+	}
+	var matches []ACMatch
+	ac._findAllFunc(data, ac.kind, nil, func(m ACMatch) bool {
+		if counts[m.PatternIndex] == capPerPattern {
+			return true
+		}
+		matches = append(matches, m)
+		counts[m.PatternIndex]++
+		if counts[m.PatternIndex] == capPerPattern {
+			open--
+		}
+		return open > 0
+	})
+	return matches
+}
+
+// FindAllMinLen is FindAll ignoring matches shorter than minLen, measured like End-Start+1:
+// in bytes, or in runes in rune mode. Short matches are dropped during the scan rather than
+// collected and filtered, and under the leftmost kinds they do not compete, so a longer match
+// they would have hidden is reported instead, as if the short keywords were not registered.
+func (ac *AhoCorasick) FindAllMinLen(text string, minLen int) []ACMatch {
+	return ac._findAllMinLen([]byte(text), minLen)
+}
+
+// FindAllMinLenBytes is FindAllMinLen for a byte slice
+func (ac *AhoCorasick) FindAllMinLenBytes(data []byte, minLen int) []ACMatch {
+	return ac._findAllMinLen(data, minLen)
+}
+
+// _findAllMinLen rejects short matches through accept, which sees byte offsets into data
+func (ac *AhoCorasick) _findAllMinLen(data []byte, minLen int) []ACMatch {
+	if minLen <= 1 {
+		return ac._findAll(data, ac.kind, nil)
+	}
+	return ac._findAll(data, ac.kind, func(m ACMatch) bool {
+		n := m.End - m.Start + 1
+		if ac.runes && n >= minLen {
+			n = utf8.RuneCount(data[m.Start : m.End+1])
+		}
+		return n >= minLen
+	})
+}
+
+// FindAllNonOverlapping finds non-overlapping pattern matches in text, whatever the automaton's MatchKind.
+// Each match starts strictly after the End of the previous one. Ties are decided in favor of
+// the earliest-starting match, then the longest one (LeftmostLongest semantics).
+func (ac *AhoCorasick) FindAllNonOverlapping(text string) []ACMatch {
+	return ac._findAll([]byte(text), LeftmostLongest, nil)
+}
+
+// FindAllNonOverlappingBytes finds non-overlapping pattern matches in the byte slice,
+// preferring the earliest-starting, then longest match
+func (ac *AhoCorasick) FindAllNonOverlappingBytes(data []byte) []ACMatch {
+	return ac._findAll(data, LeftmostLongest, nil)
+}
+
+// FindAllLongest reports, for every text position where some pattern ends, only the longest
+// pattern ending there (e.g. "she" but not "he" in "she"), whatever the automaton's MatchKind.
+// Unlike FindAllNonOverlapping, matches ending at different positions may overlap.
+// When several patterns are the same keyword, the one with the lowest PatternIndex is reported.
+func (ac *AhoCorasick) FindAllLongest(text string) []ACMatch {
+	return ac._findAllLongest([]byte(text))
+}
+
+// FindAllLongestBytes reports the longest pattern ending at each position of the byte slice
+func (ac *AhoCorasick) FindAllLongestBytes(data []byte) []ACMatch {
+	return ac._findAllLongest(data)
+}
+
+// _findAllLongest runs the standard automaton and keeps the first entry of each out list:
+// a node's own patterns come first and are as long as the node is deep, and inherited
+// patterns follow in the order of the failure chain, i.e. by decreasing length.
+// Under Options.TokenBoundary the longest token is kept instead: Standard matches ending
+// at the same position come longest first, so it is the first one accepted.
+func (ac *AhoCorasick) _findAllLongest(data []byte) []ACMatch {
+	var matches []ACMatch
+	if ac.tokenBoundary != nil {
+		ac._findAllFunc(data, Standard, nil, func(m ACMatch) bool {
+			if n := len(matches); n == 0 || matches[n-1].End != m.End {
+				matches = append(matches, m)
+			}
+			return true
+		})
+		return matches
+	}
+	h := ac.prepare(data)
+	node := 0
+	for i, c := range h.data {
+		node = ac.step(node, ac.normChar(c))
+		if out := ac.out[node]; len(out) > 0 {
+			matches = append(matches, h.runeMatch(ACMatch{
+				PatternIndex: out[0],
+				Start:        i - len(ac.keywords[out[0]]) + 1,
+				End:          i,
+			}))
+		}
+	}
+	return matches
 }
 
-// Contains returns whether any registered pattern matches in the text
+// Contains returns whether any registered pattern matches in the text.
+// It stops at the first match and does not allocate.
 func (ac *AhoCorasick) Contains(text string) bool {
-	ms := ac.FindAll(text)
-	return len(ms) > 0
+	return ac._contains([]byte(text))
 }
 
 // ContainsBytes returns whether any pattern matches in the byte slice
 func (ac *AhoCorasick) ContainsBytes(data []byte) bool {
-	ms := ac.FindAllBytes(data)
-	return len(ms) > 0
+	return ac._contains(data)
 }
 
 // Count returns the number of **all** matches found in the text
@@ -85,23 +476,294 @@ func (ac *AhoCorasick) CountBytes(data []byte) int {
 	return len(ms)
 }
 
+// CountByPattern returns the number of matches of each pattern in the text, indexed by PatternIndex.
+// The counts follow the automaton's MatchKind and add up to Count(text).
+func (ac *AhoCorasick) CountByPattern(text string) []int {
+	return ac._countByPattern([]byte(text))
+}
+
+// CountByPatternBytes returns the number of matches of each pattern in the byte slice, indexed by PatternIndex
+func (ac *AhoCorasick) CountByPatternBytes(data []byte) []int {
+	return ac._countByPattern(data)
+}
+
+// _countByPattern tallies matches per pattern. Standard matches are counted straight from
+// the out lists without building ACMatch values, unless Options.TokenBoundary must vet them
+func (ac *AhoCorasick) _countByPattern(data []byte) []int {
+	counts := make([]int, len(ac.keywords))
+	if ac.kind != Standard || ac.tokenBoundary != nil {
+		for _, m := range ac._findAll(data, ac.kind, nil) {
+			counts[m.PatternIndex]++
+		}
+		return counts
+	}
+
+	node := 0
+	for _, c := range ac.prepare(data).data {
+		node = ac.step(node, ac.normChar(c))
+		for _, patIdx := range ac.out[node] {
+			counts[patIdx]++
+		}
+	}
+	return counts
+}
+
+// MatchedPatterns reports which patterns occur anywhere in the text, indexed by PatternIndex.
+// Every occurrence counts, as under Standard semantics, whatever the automaton's MatchKind.
+// The text is scanned once without building ACMatch values, and each trie node's out list
+// is read only the first time the node is reached, so heavily repeated keywords cost
+// nothing extra. The scan stops early once every pattern has been seen.
+func (ac *AhoCorasick) MatchedPatterns(text string) []bool {
+	return ac._matchedPatterns([]byte(text))
+}
+
+// MatchedPatternsBytes reports which patterns occur anywhere in the byte slice, indexed by PatternIndex
+func (ac *AhoCorasick) MatchedPatternsBytes(data []byte) []bool {
+	return ac._matchedPatterns(data)
+}
+
+// _matchedPatterns marks the patterns of every node reached while walking the automaton
+func (ac *AhoCorasick) _matchedPatterns(data []byte) []bool {
+	seen := make([]bool, len(ac.keywords))
+	if ac.tokenBoundary != nil {
+		// A node reached once may hold a token match only on a later visit
+		left := len(seen)
+		ac.findFunc(data, Standard, nil, false, func(m ACMatch) bool {
+			if !seen[m.PatternIndex] {
+				seen[m.PatternIndex] = true
+				left--
+			}
+			return left > 0
+		}, nil)
+		return seen
+	}
+	visited := make([]bool, ac.numNodes())
+	left := len(seen)
+	node := 0
+	for _, c := range ac.prepare(data).data {
+		if left == 0 {
+			break
+		}
+		node = ac.step(node, ac.normChar(c))
+		if visited[node] {
+			continue
+		}
+		visited[node] = true
+		for _, patIdx := range ac.out[node] {
+			if !seen[patIdx] {
+				seen[patIdx] = true
+				left--
+			}
+		}
+	}
+	return seen
+}
+
+// MatchedString returns the part of text covered by m, as it appears in text.
+// Under ignoreCase this keeps the original casing rather than the lowercased keyword.
+// In rune mode m's offsets are read as rune indices.
+// Returns an empty string if m does not lie within text.
+func (ac *AhoCorasick) MatchedString(text string, m ACMatch) string {
+	start, end, ok := ac.byteSpan([]byte(text), m)
+	if !ok {
+		return ""
+	}
+	return text[start:end]
+}
+
+// MatchedBytes returns the part of data covered by m, as it appears in data.
+// Returns nil if m does not lie within data.
+func (ac *AhoCorasick) MatchedBytes(data []byte, m ACMatch) []byte {
+	start, end, ok := ac.byteSpan(data, m)
+	if !ok {
+		return nil
+	}
+	return data[start:end]
+}
+
+// Add inserts a new pattern into the automaton and returns its pattern index.
+// The existing trie is kept and only the new keyword's path is added; failure links
+// and out information are then recomputed, which costs O(nodes × 256) rather than
+// rebuilding the whole automaton from the patterns.
+// Add must not be called concurrently with searches on the same automaton.
+func (ac *AhoCorasick) Add(pattern string) int {
+	ac.patterns = append(ac.patterns, pattern)
+	ac.keywords = append(ac.keywords, foldPattern(pattern, ac.options()))
+	idx := len(ac.keywords) - 1
+
+	ac.resetFailureLinks(idx)
+	ac.insert(idx)
+	ac.buildFailureLinks()
+	return idx
+}
+
+// Remove retires the pattern with the given index so that it no longer matches, and reports
+// whether there was such a pattern to retire: false if patternIndex is out of range or the
+// pattern was already removed (or is empty). The index stays reserved, so every other
+// pattern keeps its PatternIndex, and patterns added later get new indices; a removed
+// pattern behaves exactly like an empty one, including after MarshalBinary.
+// As with Add, failure links and out information are recomputed in O(nodes × 256). The trie
+// nodes only the removed pattern used are left in place and no longer report anything; call
+// Recompile to reclaim them after removing many patterns.
+// Remove must not be called concurrently with searches on the same automaton.
+func (ac *AhoCorasick) Remove(patternIndex int) bool {
+	if patternIndex < 0 || patternIndex >= len(ac.keywords) || len(ac.keywords[patternIndex]) == 0 {
+		return false
+	}
+	ac.patterns[patternIndex] = ""
+	ac.keywords[patternIndex] = nil
+
+	ac.resetFailureLinks(len(ac.keywords))
+	ac.buildFailureLinks()
+	return true
+}
+
+// Recompile rebuilds the automaton in place from its current patterns, dropping the trie
+// nodes left behind by Remove. Pattern indices and options are kept.
+// Recompile must not be called concurrently with searches on the same automaton.
+func (ac *AhoCorasick) Recompile() {
+	ac.Rebuild(ac.ignoreCase)
+}
+
+// Rebuild recompiles the automaton in place from the patterns it was created with,
+// switching case-insensitive matching on or off. Pattern indices, the MatchKind, the
+// backend and rune mode are kept. Patterns added with Add are included.
+// To make this possible every automaton retains the pattern strings as given, next to
+// the folded keywords it searches with: one string header per pattern plus, for
+// patterns not otherwise referenced by the caller, their bytes (see Stats).
+// Rebuild must not be called concurrently with searches on the same automaton.
+func (ac *AhoCorasick) Rebuild(ignoreCase bool) {
+	opts := ac.options()
+	opts.IgnoreCase = ignoreCase
+	*ac = *NewWithOptions(ac.patterns, opts)
+}
+
+// options returns the Options the automaton was configured with
+func (ac *AhoCorasick) options() Options {
+	return Options{
+		IgnoreCase:    ac.ignoreCase,
+		MatchKind:     ac.kind,
+		Backend:       ac.backend,
+		Runes:         ac.runes,
+		Prefilter:     ac.prefilter,
+		Normalize:     ac.normalize,
+		Wildcards:     ac.wildcards,
+		TokenBoundary: ac.tokenBoundary,
+	}
+}
+
+// Equal reports whether ac and other find the same matches in every text: they have the
+// same keywords at the same pattern indices (compared after case folding, so "HE" and "he"
+// are equal under IgnoreCase) and the same IgnoreCase, MatchKind, Runes and Wildcards
+// settings. The Backend and Prefilter are ignored because they do not change what is found.
+// The order of the patterns matters, since it decides PatternIndex; an automaton restored with
+// UnmarshalBinary is Equal to the one that was marshaled. An automaton with a Normalize or
+// TokenBoundary function is only Equal to itself, since functions cannot be compared.
+func (ac *AhoCorasick) Equal(other *AhoCorasick) bool {
+	if ac == nil || other == nil || ac.hasFuncs() || other.hasFuncs() {
+		return ac == other
+	}
+	return ac.ignoreCase == other.ignoreCase &&
+		ac.runes == other.runes &&
+		ac.kind == other.kind &&
+		maps.Equal(ac.wildcards, other.wildcards) &&
+		slices.EqualFunc(ac.keywords, other.keywords, bytes.Equal)
+}
+
+// hasFuncs reports whether the automaton was configured with an Options function,
+// which can be neither compared nor serialized
+func (ac *AhoCorasick) hasFuncs() bool {
+	return ac.normalize != nil || ac.tokenBoundary != nil
+}
+
+// MinPatternLen returns the length of the shortest pattern the automaton can match, ignoring
+// empty and removed patterns, which never match; 0 if there is none. Lengths are in bytes, or
+// in runes in rune mode, after case folding. Under Normalize they are those of the normalized
+// patterns, so a match may cover fewer runes of the text. Both this and MaxPatternLen are kept
+// up to date by Add and Remove and cost O(1).
+func (ac *AhoCorasick) MinPatternLen() int {
+	return ac.minLen
+}
+
+// MaxPatternLen returns the length of the longest pattern the automaton can match, in the
+// same units as MinPatternLen; 0 if there is none. No match is longer.
+func (ac *AhoCorasick) MaxPatternLen() int {
+	return ac.maxLen
+}
+
+// foldPattern converts a pattern to its internal keyword form: it normalizes every rune if
+// opts.Normalize is set, then if opts.IgnoreCase is set it lowercases ASCII letters, or every
+// rune in rune mode
+func foldPattern(p string, opts Options) []byte {
+	return foldPatternBytes([]byte(p), opts)
+}
+
+// foldPatternBytes is foldPattern for a pattern it may modify; ASCII folding happens in place
+func foldPatternBytes(b []byte, opts Options) []byte {
+	switch {
+	case opts.Normalize != nil:
+		return normalizeRunes(b, opts.Normalize, opts.IgnoreCase)
+	case opts.IgnoreCase && opts.Runes:
+		return foldRunes(b)
+	case opts.IgnoreCase:
+		return foldASCII(b)
+	}
+	return b
+}
+
+// foldASCII lowercases the ASCII letters of b in place and returns it
+func foldASCII(b []byte) []byte {
+	for i := range b {
+		if b[i] >= 'A' && b[i] <= 'Z' {
+			b[i] = b[i] + ('a' - 'A')
+		}
+	}
+	return b
+}
+
 // buildTrie inserts patterns from ac.keywords into the trie
 func (ac *AhoCorasick) buildTrie() {
-	for idx, k := range ac.keywords {
-		node := 0 // start from root
-		for _, c := range k {
-			cc := c // (byte)
-			if ac.next[node][cc] == 0 {
-				// Create new node
-				ac.next = append(ac.next, [256]int{})
-				ac.fail = append(ac.fail, 0)
-				ac.out = append(ac.out, []int{})
-				ac.next[node][cc] = len(ac.next) - 1
+	for idx := range ac.keywords {
+		ac.insert(idx)
+	}
+}
+
+// insert adds the paths of keyword idx to the trie, creating nodes as needed.
+// An empty keyword is not added: it would end at the root and, through the failure
+// links, be inherited by every node, reporting an empty match at every position.
+func (ac *AhoCorasick) insert(idx int) {
+	if len(ac.keywords[idx]) == 0 {
+		return
+	}
+	// Patterns ending at the node of each path: idx
+	ac.walkKeyword(0, ac.keywords[idx], true, func(end int) {
+		ac.out[end] = append(ac.out[end], idx)
+	})
+}
+
+// resetFailureLinks undoes buildFailureLinks for the first n keywords so it can run again:
+// transitions that are not trie edges are cleared and each out list is reduced to the
+// patterns ending exactly at its node. A transition is a trie edge exactly when it leads
+// one level deeper; transitions borrowed through failure links never do.
+func (ac *AhoCorasick) resetFailureLinks(n int) {
+	for node := 0; node < ac.numNodes(); node++ {
+		if ac.next != nil {
+			for c := 0; c < 256; c++ {
+				if ac.child(node, byte(c)) == 0 {
+					ac.next[node][c] = 0
+				}
 			}
-			node = ac.next[node][cc]
 		}
-		// Patterns ending at this node: idx
-		ac.out[node] = append(ac.out[node], idx)
+		ac.out[node] = ac.out[node][:0]
+		ac.fail[node] = 0
+	}
+	for idx, k := range ac.keywords[:n] {
+		if len(k) == 0 {
+			continue
+		}
+		ac.walkKeyword(0, k, false, func(end int) {
+			ac.out[end] = append(ac.out[end], idx)
+		})
 	}
 }
 
@@ -110,18 +772,11 @@ func (ac *AhoCorasick) buildTrie() {
 func (ac *AhoCorasick) buildFailureLinks() {
 	queue := []int{}
 
-	// 1) Set up root(0)'s child nodes
-	for c := 0; c < 256; c++ {
-		nx := ac.next[0][c]
-		if nx != 0 {
-			// Set child's fail to 0(root)
-			ac.fail[nx] = 0
-			queue = append(queue, nx)
-		} else {
-			// Maintain fail[next[0][c]] = 0 even when not present
-			ac.next[0][c] = 0
-		}
-	}
+	// 1) Set up root(0)'s child nodes: their fail is 0(root)
+	ac.forEachChild(0, func(c byte, nx int) {
+		ac.fail[nx] = 0
+		queue = append(queue, nx)
+	})
 
 	// 2) Set failure links while running BFS
 	for len(queue) > 0 {
@@ -129,47 +784,276 @@ func (ac *AhoCorasick) buildFailureLinks() {
 		queue = queue[1:]
 
 		// for all edges c of f node
-		for c := 0; c < 256; c++ {
-			nx := ac.next[f][c]
-			if nx != 0 {
-				queue = append(queue, nx)
-				// update failure link logic
-				failTo := ac.fail[f]
-				// follow c edge from failTo node
-				ac.fail[nx] = ac.next[failTo][c]
-				// inherit out information
-				ac.out[nx] = append(ac.out[nx], ac.out[ac.fail[nx]]...)
-			} else {
-				// if no edge, follow fail[f] of current f node to the node connected by c edge
-				ac.next[f][c] = ac.next[ac.fail[f]][c]
+		ac.forEachChild(f, func(c byte, nx int) {
+			queue = append(queue, nx)
+			// follow c edge from fail[f]; nodes above f are already complete
+			ac.fail[nx] = ac.step(ac.fail[f], c)
+			// inherit out information
+			ac.out[nx] = append(ac.out[nx], ac.out[ac.fail[nx]]...)
+		})
+
+		if ac.next != nil {
+			// if no edge, follow fail[f] of current f node to the node connected by c edge
+			for c := 0; c < 256; c++ {
+				if ac.next[f][c] == 0 {
+					ac.next[f][c] = ac.next[ac.fail[f]][c]
+				}
 			}
 		}
 	}
+	ac.buildLeavesRoot()
+	ac.buildPatternLens()
 }
 
-// _findAll finds all matching patterns (ACMatch) in the byte slice data
-func (ac *AhoCorasick) _findAll(data []byte) []ACMatch {
-	var matches []ACMatch
+// buildPatternLens sets minLen and maxLen from the keywords
+func (ac *AhoCorasick) buildPatternLens() {
+	ac.minLen, ac.maxLen = 0, 0
+	for _, k := range ac.keywords {
+		n := len(k)
+		if ac.runes {
+			n = utf8.RuneCount(k)
+		}
+		if n == 0 {
+			continue
+		}
+		if ac.minLen == 0 || n < ac.minLen {
+			ac.minLen = n
+		}
+		ac.maxLen = max(ac.maxLen, n)
+	}
+}
+
+// buildLeavesRoot fills leavesRoot from the root's trie edges, and the prefilter's start bytes
+// if it is enabled and there are few enough of them
+func (ac *AhoCorasick) buildLeavesRoot() {
+	var starts []byte
+	for c := 0; c < 256; c++ {
+		ac.leavesRoot[c] = ac.child(0, ac.normChar(byte(c))) != 0
+		if ac.leavesRoot[c] {
+			starts = append(starts, byte(c))
+		}
+	}
+	ac.numStartBytes = 0
+	if ac.prefilter && len(starts) > 0 && len(starts) <= maxPrefilterBytes {
+		ac.numStartBytes = len(starts)
+		for k := range ac.startWords {
+			ac.startWords[k] = lsbs * uint64(starts[min(k, len(starts)-1)]) // unused slots repeat the last byte
+		}
+	}
+}
+
+// skipRoot returns the index of the first byte of data[i:end] that leaves the root, or end if
+// there is none. The automaton stays at the root, where no pattern ends, on every byte skipped.
+// A table lookup per byte is cheaper than a step; bytes.IndexByte would be cheaper still for a
+// few start bytes, but handing it the text would make every string search copy its text (see
+// indexEither in the boyermoore package). The prefilter's word-at-a-time test is the
+// allocation-free alternative.
+func (ac *AhoCorasick) skipRoot(data []byte, i, end int) int {
+	if ac.numStartBytes > 0 {
+		i = ac.skipWords(data, i, end)
+	}
+	for i < end && !ac.leavesRoot[data[i]] {
+		i++
+	}
+	return i
+}
+
+// _findAll finds the matching patterns (ACMatch) in the byte slice data according to kind.
+// If accept is not nil, matches it rejects are ignored as if the pattern had not matched there.
+func (ac *AhoCorasick) _findAll(data []byte, kind MatchKind, accept func(ACMatch) bool) []ACMatch {
+	return ac._findAllInto(data, kind, accept, nil)
+}
+
+// _findAllInto is _findAll appending the matches to dst
+func (ac *AhoCorasick) _findAllInto(data []byte, kind MatchKind, accept func(ACMatch) bool, dst []ACMatch) []ACMatch {
+	ac._findAllFunc(data, kind, accept, func(m ACMatch) bool {
+		dst = append(dst, m)
+		return true
+	})
+	return dst
+}
+
+// _findAllFunc passes the matches _findAll would return to fn, one at a time,
+// and stops as soon as fn returns false. accept always sees byte offsets into data,
+// while fn sees rune indices in rune mode.
+func (ac *AhoCorasick) _findAllFunc(data []byte, kind MatchKind, accept, fn func(ACMatch) bool) {
+	ac.findFunc(data, kind, accept, ac.runes, fn, nil)
+}
+
+// findFunc is _findAllFunc with the choice of offsets fn sees made by runeOffsets:
+// rune indices if true, byte offsets into data otherwise.
+// If stop is not nil, the scan polls it every stopCheckInterval bytes and ends early once it returns true.
+func (ac *AhoCorasick) findFunc(data []byte, kind MatchKind, accept func(ACMatch) bool, runeOffsets bool, fn func(ACMatch) bool, stop func() bool) {
+	if ac.tokenBoundary != nil {
+		accept = ac.tokenAccept(data, accept)
+	}
+	if !ac.runes {
+		ac.scan(data, kind, accept, fn, stop)
+		return
+	}
+	h := ac.prepare(data)
+	hostAccept := accept
+	if accept != nil {
+		hostAccept = func(m ACMatch) bool { return accept(h.orig(m)) }
+	}
+	ac.scan(h.data, kind, hostAccept, func(m ACMatch) bool {
+		if runeOffsets {
+			return fn(h.runeMatch(m))
+		}
+		return fn(h.orig(m))
+	}, stop)
+}
+
+// tokenAccept returns accept restricted to the matches that are tokens of data under
+// Options.TokenBoundary, with m in byte offsets into data
+func (ac *AhoCorasick) tokenAccept(data []byte, accept func(ACMatch) bool) func(ACMatch) bool {
+	return func(m ACMatch) bool {
+		return isBounded(data, m, ac.tokenBoundary) && (accept == nil || accept(m))
+	}
+}
+
+// stopCheckInterval is the number of bytes scanned between two polls of a stop function
+const stopCheckInterval = 64 * 1024
+
+// scan runs the search over the haystack data and passes matches in haystack offsets to fn.
+// If stop is not nil, it is polled every stopCheckInterval bytes and the scan returns once it reports true.
+func (ac *AhoCorasick) scan(data []byte, kind MatchKind, accept func(ACMatch) bool, fn func(ACMatch) bool, stop func() bool) {
+	if kind != Standard {
+		ac.findLeftmost(data, kind, accept, fn, stop)
+		return
+	}
 	node := 0 // current node being searched in trie
 
-	for i, c := range data {
-		cc := c
-		if ac.ignoreCase && cc >= 'A' && cc <= 'Z' {
-			cc = cc + ('a' - 'A')
+	for from := 0; from < len(data); from += stopCheckInterval {
+		if stop != nil && stop() {
+			return
 		}
-		node = ac.next[node][cc]
+		end := min(from+stopCheckInterval, len(data))
+		for i := from; i < end; i++ {
+			if node == 0 {
+				if i = ac.skipRoot(data, i, end); i == end {
+					break
+				}
+			}
+			node = ac.step(node, ac.normChar(data[i]))
 
-		// Process all pattern indices in node(any node in trie)'s out
-		if len(ac.out[node]) > 0 {
+			// Process all pattern indices in node(any node in trie)'s out
 			for _, patIdx := range ac.out[node] {
 				patLen := len(ac.keywords[patIdx])
-				matches = append(matches, ACMatch{
+				m := ACMatch{
 					PatternIndex: patIdx,
 					Start:        i - patLen + 1,
 					End:          i,
-				})
+				}
+				if (accept == nil || accept(m)) && !fn(m) {
+					return
+				}
 			}
 		}
 	}
-	return matches
+}
+
+// findLeftmost finds non-overlapping matches with leftmost-longest or leftmost-first semantics
+// and passes them to fn until it returns false.
+// The standard automaton is run while remembering the best candidate seen so far.
+// Once the current node's depth shows that no partial match starting at or before the
+// candidate's Start is still alive, nothing can beat the candidate: it is reported and
+// the scan restarts from the root right after its End.
+// Matches rejected by accept (if not nil) never become candidates.
+// If stop is not nil, it is polled every stopCheckInterval bytes stepped or skipped at the root;
+// bytes scanned again after a restart count twice.
+func (ac *AhoCorasick) findLeftmost(data []byte, kind MatchKind, accept, fn func(ACMatch) bool, stop func() bool) {
+	budget := stopCheckInterval // bytes left until the next poll of stop
+	for pos := 0; pos < len(data); {
+		cand, found := ACMatch{}, false
+		node := 0
+		i := pos
+		for ; i < len(data); i++ {
+			if node == 0 {
+				// Skipped bytes count against the budget, leaving at least the step below
+				j := ac.skipRoot(data, i, min(i+budget-1, len(data)))
+				budget -= j - i
+				if i = j; i == len(data) {
+					break
+				}
+			}
+			if budget--; budget == 0 {
+				if stop != nil && stop() {
+					return
+				}
+				budget = stopCheckInterval
+			}
+			node = ac.step(node, ac.normChar(data[i]))
+			for _, patIdx := range ac.out[node] {
+				m := ACMatch{
+					PatternIndex: patIdx,
+					Start:        i - len(ac.keywords[patIdx]) + 1,
+					End:          i,
+				}
+				if accept != nil && !accept(m) {
+					continue
+				}
+				if !found || betterLeftmost(m, cand, kind) {
+					cand, found = m, true
+				}
+			}
+			if found && i-ac.depth[node]+1 > cand.Start {
+				break
+			}
+		}
+		if !found || !fn(cand) {
+			return
+		}
+		pos = cand.End + 1
+	}
+}
+
+// _contains walks the automaton and returns as soon as any pattern ends at the current node.
+// Some match exists under every MatchKind exactly when a Standard match exists.
+// Under Options.TokenBoundary an out list only holds candidates, so the search stops at
+// the first match that is a token instead.
+func (ac *AhoCorasick) _contains(data []byte) bool {
+	if ac.tokenBoundary != nil {
+		found := false
+		ac.findFunc(data, Standard, nil, false, func(ACMatch) bool {
+			found = true
+			return false
+		}, nil)
+		return found
+	}
+	if ac.runes {
+		return ac.containsRunes(data)
+	}
+	node := 0
+	for i := 0; i < len(data); i++ {
+		if node == 0 {
+			if i = ac.skipRoot(data, i, len(data)); i == len(data) {
+				break
+			}
+		}
+		node = ac.step(node, ac.normChar(data[i]))
+		if len(ac.out[node]) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// betterLeftmost reports whether m should replace cand under the given leftmost match kind
+func betterLeftmost(m, cand ACMatch, kind MatchKind) bool {
+	if m.Start != cand.Start {
+		return m.Start < cand.Start
+	}
+	if kind == LeftmostFirst {
+		return m.PatternIndex < cand.PatternIndex
+	}
+	return m.End > cand.End
+}
+
+// normChar normalizes a byte for case-insensitive comparison
+func (ac *AhoCorasick) normChar(c byte) byte {
+	if ac.ignoreCase && c >= 'A' && c <= 'Z' {
+		return c + ('a' - 'A')
+	}
+	return c
 }
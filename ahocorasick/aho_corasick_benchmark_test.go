@@ -0,0 +1,309 @@
+package ahocorasick
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+// generateDictionary returns n pseudo-English lowercase words of 3 to 12 letters,
+// drawn with rough English letter frequencies so prefixes are shared as in a real wordlist.
+func generateDictionary(n int) []string {
+	const letters = "eeeeeeeeeeeetttttttttaaaaaaaaooooooooiiiiiiinnnnnnnsssssshhhhhhrrrrrrddddllllcccuuummwwffggyyppbbvkjxqz"
+	rng := rand.New(rand.NewPCG(42, 42))
+	words := make([]string, n)
+	for i := range words {
+		b := make([]byte, 3+rng.IntN(10))
+		for j := range b {
+			b[j] = letters[rng.IntN(len(letters))]
+		}
+		words[i] = string(b)
+	}
+	return words
+}
+
+func BenchmarkNewDictionary(b *testing.B) {
+	words := generateDictionary(10000)
+	backends := []struct {
+		name    string
+		backend Backend
+	}{
+		{"ArrayOfArrays", ArrayOfArrays},
+		{"SparseMap", SparseMap},
+		{"DoubleArray", DoubleArray},
+	}
+
+	for _, bk := range backends {
+		b.Run(bk.name, func(b *testing.B) {
+			b.ReportAllocs()
+			var ac *AhoCorasick
+			for i := 0; i < b.N; i++ {
+				ac = NewWithOptions(words, Options{Backend: bk.backend})
+			}
+			b.ReportMetric(float64(ac.Stats().MemoryBytes), "bytes/automaton")
+		})
+	}
+}
+
+func BenchmarkFindAllDictionary(b *testing.B) {
+	words := generateDictionary(10000)
+	text := strings.Join(generateDictionary(2000), " ")
+	backends := []struct {
+		name    string
+		backend Backend
+	}{
+		{"ArrayOfArrays", ArrayOfArrays},
+		{"SparseMap", SparseMap},
+		{"DoubleArray", DoubleArray},
+	}
+
+	for _, bk := range backends {
+		b.Run(bk.name, func(b *testing.B) {
+			ac := NewWithOptions(words, Options{Backend: bk.backend})
+			b.SetBytes(int64(len(text)))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				ac.FindAll(text)
+			}
+		})
+	}
+}
+
+func BenchmarkFindAllSparseKeywords(b *testing.B) {
+	// Log-like lowercase text in which the uppercase keywords are rare
+	line := "2024-05-01 12:00:00 info request handled in 12ms path=/api/v1/items status=200\n"
+	text := strings.Repeat(line, 1<<13) + "ERROR disk full\n" + strings.Repeat(line, 1<<13)
+	patterns := []string{"ERROR", "FATAL", "PANIC", "WARN"}
+	for _, kind := range []MatchKind{Standard, LeftmostLongest} {
+		for _, ignoreCase := range []bool{false, true} {
+			name := map[MatchKind]string{Standard: "Standard", LeftmostLongest: "LeftmostLongest"}[kind]
+			if ignoreCase {
+				name += "/IgnoreCase"
+			}
+			b.Run(name, func(b *testing.B) {
+				ac := NewWithOptions(patterns, Options{MatchKind: kind, IgnoreCase: ignoreCase})
+				b.SetBytes(int64(len(text)))
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					ac.FindAll(text)
+				}
+			})
+		}
+	}
+	b.Run("Contains", func(b *testing.B) {
+		ac := New(patterns[1:], false)
+		b.SetBytes(int64(len(text)))
+		for i := 0; i < b.N; i++ {
+			ac.Contains(text)
+		}
+	})
+}
+
+// BenchmarkPrefilter compares the word-at-a-time start byte skip with the byte-by-byte one,
+// on keywords whose start bytes are rare in the text and on keywords starting with common letters
+func BenchmarkPrefilter(b *testing.B) {
+	line := "2024-05-01 12:00:00 info request handled in 12ms path=/api/v1/items status=200\n"
+	text := strings.Repeat(line, 1<<13) + "ERROR disk full\n" + strings.Repeat(line, 1<<13)
+	benchmarks := []struct {
+		name     string
+		patterns []string
+		opts     Options
+	}{
+		{"Rare", []string{"ERROR", "FATAL", "PANIC", "WARN"}, Options{}},
+		{"Rare/IgnoreCase", []string{"ZONE", "XML"}, Options{IgnoreCase: true}},
+		{"Rare/LeftmostLongest", []string{"ERROR", "FATAL", "PANIC", "WARN"}, Options{MatchKind: LeftmostLongest}},
+		{"Common", []string{"request", "status", "items"}, Options{}},
+	}
+	for _, bb := range benchmarks {
+		for _, prefilter := range []bool{false, true} {
+			opts := bb.opts
+			opts.Prefilter = prefilter
+			ac := NewWithOptions(bb.patterns, opts)
+			b.Run(fmt.Sprintf("%s/Prefilter=%v", bb.name, prefilter), func(b *testing.B) {
+				b.SetBytes(int64(len(text)))
+				for i := 0; i < b.N; i++ {
+					ac.FindAll(text)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkContainsDictionary(b *testing.B) {
+	words := generateDictionary(1000)
+	// Only the very first word of the text is a keyword
+	text := words[0] + " " + strings.Repeat("-", 1<<20)
+	ac := New(words, false)
+
+	b.Run("Contains", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			ac.Contains(text)
+		}
+	})
+	b.Run("FindAll", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = len(ac.FindAll(text)) > 0
+		}
+	})
+}
+
+func BenchmarkFindAllIntoShortTexts(b *testing.B) {
+	words := generateDictionary(1000)
+	texts := make([]string, 1000)
+	for i := range texts {
+		texts[i] = strings.Join(generateDictionary(i%5+1), " ")
+	}
+	ac := New(words, false)
+	b.Run("FindAll", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, text := range texts {
+				_ = len(ac.FindAll(text))
+			}
+		}
+	})
+	b.Run("FindAllInto", func(b *testing.B) {
+		b.ReportAllocs()
+		var buf []ACMatch
+		for i := 0; i < b.N; i++ {
+			for _, text := range texts {
+				buf = ac.FindAllInto(text, buf[:0])
+			}
+		}
+	})
+}
+
+func BenchmarkFindAllBatch(b *testing.B) {
+	words := generateDictionary(10000)
+	docs := make([]string, 256)
+	for i := range docs {
+		docs[i] = strings.Join(generateDictionary(200+i), " ")
+	}
+	ac := New(words, false)
+	for _, workers := range []int{1, 4} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				ac.FindAllBatch(docs, workers)
+			}
+		})
+	}
+}
+
+func BenchmarkContainsNoMatch(b *testing.B) {
+	words := generateDictionary(1000)
+	// Digits never occur in the generated words, so every byte of the text is scanned
+	text := strings.Repeat("0123456789 ", 1<<16)
+	for _, runes := range []bool{false, true} {
+		name := "Bytes"
+		if runes {
+			name = "Runes"
+		}
+		b.Run(name, func(b *testing.B) {
+			ac := NewWithOptions(words, Options{IgnoreCase: true, Runes: runes})
+			b.SetBytes(int64(len(text)))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				ac.Contains(text)
+			}
+		})
+	}
+}
+
+func BenchmarkUnmarshalDictionary(b *testing.B) {
+	words := generateDictionary(10000)
+	backends := []struct {
+		name    string
+		backend Backend
+	}{
+		{"ArrayOfArrays", ArrayOfArrays},
+		{"SparseMap", SparseMap},
+		{"DoubleArray", DoubleArray},
+	}
+
+	for _, bk := range backends {
+		data, err := NewWithOptions(words, Options{Backend: bk.backend}).MarshalBinary()
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(bk.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var ac AhoCorasick
+				if err := ac.UnmarshalBinary(data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkFindAllFuncDictionary(b *testing.B) {
+	words := generateDictionary(10000)
+	data := []byte(strings.Join(generateDictionary(2000), " "))
+	ac := New(words, false)
+
+	b.Run("FindAllBytes", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			ac.FindAllBytes(data)
+		}
+	})
+	b.Run("FindAllFuncBytes", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			n := 0
+			ac.FindAllFuncBytes(data, func(ACMatch) bool {
+				n++
+				return true
+			})
+		}
+	})
+}
+
+func BenchmarkFindAllRunes(b *testing.B) {
+	words := generateDictionary(10000)
+	text := strings.Join(generateDictionary(2000), " ")
+	for _, runes := range []bool{false, true} {
+		name := "Bytes"
+		if runes {
+			name = "Runes"
+		}
+		b.Run(name, func(b *testing.B) {
+			ac := NewWithOptions(words, Options{IgnoreCase: true, Runes: runes})
+			b.SetBytes(int64(len(text)))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				ac.FindAll(text)
+			}
+		})
+	}
+}
+
+func BenchmarkMatchedPatternsDictionary(b *testing.B) {
+	words := generateDictionary(500)
+	text := strings.Repeat(strings.Join(words[:50], " "), 100)
+	ac := New(words, false)
+
+	b.Run("FindAll", func(b *testing.B) {
+		b.SetBytes(int64(len(text)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			seen := make([]bool, len(words))
+			for _, m := range ac.FindAll(text) {
+				seen[m.PatternIndex] = true
+			}
+		}
+	})
+	b.Run("MatchedPatterns", func(b *testing.B) {
+		b.SetBytes(int64(len(text)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			ac.MatchedPatterns(text)
+		}
+	})
+}
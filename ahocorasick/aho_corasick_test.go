@@ -1,8 +1,16 @@
 package ahocorasick
 
 import (
+	"context"
+	"errors"
+	"math/rand/v2"
 	"reflect"
+	"slices"
+	"strings"
 	"testing"
+	"unicode/utf8"
+
+	"github.com/notJoon/searcher/byteclass"
 )
 
 func TestAhoCorasickStringSearch(t *testing.T) {
@@ -137,8 +145,8 @@ func TestAhoCorasickByteSearch(t *testing.T) {
 			data:       []byte("ushers"),
 			ignoreCase: false,
 			wantMatches: []ACMatch{
-				{PatternIndex: 0, Start: 3, End: 4}, // "he"
-				{PatternIndex: 1, Start: 2, End: 4}, // "she"
+				{PatternIndex: 1, Start: 1, End: 3}, // "she"
+				{PatternIndex: 0, Start: 2, End: 3}, // "he"
 				{PatternIndex: 3, Start: 2, End: 5}, // "hers"
 			},
 			wantContains: true,
@@ -159,8 +167,8 @@ func TestAhoCorasickByteSearch(t *testing.T) {
 			data:       []byte("USHERS"),
 			ignoreCase: true,
 			wantMatches: []ACMatch{
-				{PatternIndex: 0, Start: 3, End: 4},
-				{PatternIndex: 1, Start: 2, End: 4},
+				{PatternIndex: 1, Start: 1, End: 3},
+				{PatternIndex: 0, Start: 2, End: 3},
 				{PatternIndex: 2, Start: 2, End: 5},
 			},
 			wantContains: true,
@@ -189,3 +197,1575 @@ func TestAhoCorasickByteSearch(t *testing.T) {
 		})
 	}
 }
+
+func TestAhoCorasickMatchKind(t *testing.T) {
+	type testCase struct {
+		name        string
+		patterns    []string
+		text        string
+		kind        MatchKind
+		wantMatches []ACMatch
+	}
+	tests := []testCase{
+		{
+			name:     "Standard reports nested matches",
+			patterns: []string{"Samwise", "Sam"},
+			text:     "Samwise",
+			kind:     Standard,
+			wantMatches: []ACMatch{
+				{PatternIndex: 1, Start: 0, End: 2},
+				{PatternIndex: 0, Start: 0, End: 6},
+			},
+		},
+		{
+			name:     "LeftmostLongest prefers the longer match",
+			patterns: []string{"Sam", "Samwise"},
+			text:     "Samwise",
+			kind:     LeftmostLongest,
+			wantMatches: []ACMatch{
+				{PatternIndex: 1, Start: 0, End: 6},
+			},
+		},
+		{
+			name:     "LeftmostFirst prefers the earlier pattern",
+			patterns: []string{"Sam", "Samwise"},
+			text:     "Samwise",
+			kind:     LeftmostFirst,
+			wantMatches: []ACMatch{
+				{PatternIndex: 0, Start: 0, End: 2},
+			},
+		},
+		{
+			name:     "LeftmostFirst with longer pattern first",
+			patterns: []string{"Samwise", "Sam"},
+			text:     "Samwise",
+			kind:     LeftmostFirst,
+			wantMatches: []ACMatch{
+				{PatternIndex: 0, Start: 0, End: 6},
+			},
+		},
+		{
+			name:     "Leftmost start wins over an earlier ending match",
+			patterns: []string{"bc", "abcd"},
+			text:     "abcd",
+			kind:     LeftmostLongest,
+			wantMatches: []ACMatch{
+				{PatternIndex: 1, Start: 0, End: 3},
+			},
+		},
+		{
+			name:     "Scan resumes after the match",
+			patterns: []string{"he", "she", "hers"},
+			text:     "ushers",
+			kind:     LeftmostLongest,
+			wantMatches: []ACMatch{
+				{PatternIndex: 1, Start: 1, End: 3},
+			},
+		},
+		{
+			name:     "Failed longer candidate falls back",
+			patterns: []string{"abcd", "ab", "c"},
+			text:     "abcxc",
+			kind:     LeftmostLongest,
+			wantMatches: []ACMatch{
+				{PatternIndex: 1, Start: 0, End: 1},
+				{PatternIndex: 2, Start: 2, End: 2},
+				{PatternIndex: 2, Start: 4, End: 4},
+			},
+		},
+		{
+			name:     "Maximal munch tokenizing",
+			patterns: []string{"a", "ab", "abc", "b", "c"},
+			text:     "abcabab",
+			kind:     LeftmostLongest,
+			wantMatches: []ACMatch{
+				{PatternIndex: 2, Start: 0, End: 2},
+				{PatternIndex: 1, Start: 3, End: 4},
+				{PatternIndex: 1, Start: 5, End: 6},
+			},
+		},
+		{
+			name:        "No match",
+			patterns:    []string{"cat", "dog"},
+			text:        "mouse",
+			kind:        LeftmostLongest,
+			wantMatches: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ac := NewWithOptions(tc.patterns, Options{MatchKind: tc.kind})
+
+			gotMatches := ac.FindAll(tc.text)
+			if !reflect.DeepEqual(gotMatches, tc.wantMatches) {
+				t.Errorf("FindAll(%q) got %v, want %v", tc.text, gotMatches, tc.wantMatches)
+			}
+
+			gotCount := ac.Count(tc.text)
+			if gotCount != len(tc.wantMatches) {
+				t.Errorf("Count(%q) got %d, want %d", tc.text, gotCount, len(tc.wantMatches))
+			}
+		})
+	}
+}
+
+func TestAhoCorasickMatchedString(t *testing.T) {
+	type testCase struct {
+		name       string
+		patterns   []string
+		text       string
+		ignoreCase bool
+		want       []string
+	}
+	tests := []testCase{
+		{
+			name:     "Case-sensitive",
+			patterns: []string{"he", "she", "hers"},
+			text:     "ushers",
+			want:     []string{"she", "he", "hers"},
+		},
+		{
+			name:       "Ignore case keeps original casing",
+			patterns:   []string{"he", "She", "HERS"},
+			text:       "uSHeRs",
+			ignoreCase: true,
+			want:       []string{"SHe", "He", "HeRs"},
+		},
+		{
+			name:       "Mixed case occurrences",
+			patterns:   []string{"go"},
+			text:       "Go go GO",
+			ignoreCase: true,
+			want:       []string{"Go", "go", "GO"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ac := New(tc.patterns, tc.ignoreCase)
+
+			var got, gotBytes []string
+			for _, m := range ac.FindAll(tc.text) {
+				got = append(got, ac.MatchedString(tc.text, m))
+				gotBytes = append(gotBytes, string(ac.MatchedBytes([]byte(tc.text), m)))
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("MatchedString got %q, want %q", got, tc.want)
+			}
+			if !reflect.DeepEqual(gotBytes, tc.want) {
+				t.Errorf("MatchedBytes got %q, want %q", gotBytes, tc.want)
+			}
+		})
+	}
+}
+
+func TestAhoCorasickMatchedStringOutOfRange(t *testing.T) {
+	ac := New([]string{"abc"}, false)
+	for _, m := range []ACMatch{
+		{PatternIndex: 0, Start: -1, End: 1},
+		{PatternIndex: 0, Start: 1, End: 5},
+		{PatternIndex: 0, Start: 2, End: 1},
+	} {
+		if got := ac.MatchedString("abc", m); got != "" {
+			t.Errorf("MatchedString(%v) got %q, want empty string", m, got)
+		}
+		if got := ac.MatchedBytes([]byte("abc"), m); got != nil {
+			t.Errorf("MatchedBytes(%v) got %q, want nil", m, got)
+		}
+	}
+}
+
+func TestAhoCorasickAdd(t *testing.T) {
+	type testCase struct {
+		name       string
+		initial    []string
+		added      []string
+		text       string
+		ignoreCase bool
+	}
+	tests := []testCase{
+		{
+			name:    "Add to empty automaton",
+			initial: nil,
+			added:   []string{"he", "she", "his", "hers"},
+			text:    "ushers and his",
+		},
+		{
+			name:    "Added pattern is a suffix of an existing one",
+			initial: []string{"abc"},
+			added:   []string{"bc", "c"},
+			text:    "xabcabc",
+		},
+		{
+			name:    "Added pattern extends an existing one",
+			initial: []string{"ab"},
+			added:   []string{"abcd", "bd"},
+			text:    "abdabcd",
+		},
+		{
+			name:    "Added pattern reachable only through failure links",
+			initial: []string{"abc"},
+			added:   []string{"bd"},
+			text:    "abd",
+		},
+		{
+			name:    "Duplicate of an existing pattern",
+			initial: []string{"ab"},
+			added:   []string{"ab"},
+			text:    "abab",
+		},
+		{
+			name:       "Ignore case",
+			initial:    []string{"He"},
+			added:      []string{"SHE", "hErS"},
+			text:       "USHERS",
+			ignoreCase: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ac := New(tc.initial, tc.ignoreCase)
+			for i, p := range tc.added {
+				if got, want := ac.Add(p), len(tc.initial)+i; got != want {
+					t.Errorf("Add(%q) got index %d, want %d", p, got, want)
+				}
+			}
+
+			all := append(append([]string{}, tc.initial...), tc.added...)
+			want := New(all, tc.ignoreCase).FindAll(tc.text)
+			got := ac.FindAll(tc.text)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("FindAll(%q) after Add got %v, want %v", tc.text, got, want)
+			}
+		})
+	}
+}
+
+func TestAhoCorasickBackends(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	randString := func(n int, alphabet string) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = alphabet[rng.IntN(len(alphabet))]
+		}
+		return string(b)
+	}
+
+	for i := 0; i < 500; i++ {
+		alphabet := "abcAB"[:2+rng.IntN(4)]
+		var patterns []string
+		for j := 0; j < 1+rng.IntN(6); j++ {
+			patterns = append(patterns, randString(1+rng.IntN(4), alphabet))
+		}
+		text := randString(rng.IntN(40), alphabet)
+		ignoreCase := rng.IntN(2) == 0
+
+		for _, kind := range []MatchKind{Standard, LeftmostLongest, LeftmostFirst} {
+			want := NewWithOptions(patterns, Options{IgnoreCase: ignoreCase, MatchKind: kind}).FindAll(text)
+
+			sparse := NewWithOptions(patterns[:1], Options{IgnoreCase: ignoreCase, MatchKind: kind, Backend: SparseMap})
+			for _, p := range patterns[1:] {
+				sparse.Add(p)
+			}
+			if got := sparse.FindAll(text); !reflect.DeepEqual(got, want) {
+				t.Fatalf("SparseMap FindAll(%q) with patterns %q, kind %d got %v, want %v",
+					text, patterns, kind, got, want)
+			}
+
+			// DoubleArray both from New and through Add, which relocates nodes as edges are added
+			double := NewWithOptions(patterns, Options{IgnoreCase: ignoreCase, MatchKind: kind, Backend: DoubleArray})
+			if got := double.FindAll(text); !reflect.DeepEqual(got, want) {
+				t.Fatalf("DoubleArray FindAll(%q) with patterns %q, kind %d got %v, want %v",
+					text, patterns, kind, got, want)
+			}
+			double = NewWithOptions(patterns[:1], Options{IgnoreCase: ignoreCase, MatchKind: kind, Backend: DoubleArray})
+			for _, p := range patterns[1:] {
+				double.Add(p)
+			}
+			if got := double.FindAll(text); !reflect.DeepEqual(got, want) {
+				t.Fatalf("DoubleArray after Add FindAll(%q) with patterns %q, kind %d got %v, want %v",
+					text, patterns, kind, got, want)
+			}
+		}
+	}
+}
+
+func TestAhoCorasickNonOverlapping(t *testing.T) {
+	type testCase struct {
+		name        string
+		patterns    []string
+		text        string
+		kind        MatchKind
+		wantMatches []ACMatch
+	}
+	tests := []testCase{
+		{
+			name:     "Overlapping keywords",
+			patterns: []string{"he", "she", "his", "hers"},
+			text:     "ushers",
+			wantMatches: []ACMatch{
+				{PatternIndex: 1, Start: 1, End: 3},
+			},
+		},
+		{
+			name:     "Earliest start wins over longer later match",
+			patterns: []string{"ab", "bcdef"},
+			text:     "abcdef",
+			wantMatches: []ACMatch{
+				{PatternIndex: 0, Start: 0, End: 1},
+			},
+		},
+		{
+			name:     "Longest wins at the same start",
+			patterns: []string{"a", "aaa"},
+			text:     "aaaaa",
+			wantMatches: []ACMatch{
+				{PatternIndex: 1, Start: 0, End: 2},
+				{PatternIndex: 0, Start: 3, End: 3},
+				{PatternIndex: 0, Start: 4, End: 4},
+			},
+		},
+		{
+			name:     "Independent of the automaton's MatchKind",
+			patterns: []string{"Sam", "Samwise"},
+			text:     "Samwise",
+			kind:     LeftmostFirst,
+			wantMatches: []ACMatch{
+				{PatternIndex: 1, Start: 0, End: 6},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ac := NewWithOptions(tc.patterns, Options{MatchKind: tc.kind})
+
+			got := ac.FindAllNonOverlapping(tc.text)
+			if !reflect.DeepEqual(got, tc.wantMatches) {
+				t.Errorf("FindAllNonOverlapping(%q) got %v, want %v", tc.text, got, tc.wantMatches)
+			}
+
+			gotBytes := ac.FindAllNonOverlappingBytes([]byte(tc.text))
+			if !reflect.DeepEqual(gotBytes, tc.wantMatches) {
+				t.Errorf("FindAllNonOverlappingBytes(%q) got %v, want %v", tc.text, gotBytes, tc.wantMatches)
+			}
+		})
+	}
+}
+
+func TestAhoCorasickCountByPattern(t *testing.T) {
+	type testCase struct {
+		name     string
+		patterns []string
+		text     string
+		opts     Options
+		want     []int
+	}
+	tests := []testCase{
+		{
+			name:     "Frequency table",
+			patterns: []string{"a", "ab", "b", "zz"},
+			text:     "abab ba",
+			want:     []int{3, 2, 3, 0},
+		},
+		{
+			name:     "Overlapping occurrences are counted",
+			patterns: []string{"aa"},
+			text:     "aaaa",
+			want:     []int{3},
+		},
+		{
+			name:     "Ignore case",
+			patterns: []string{"he", "She"},
+			text:     "SHE she He",
+			opts:     Options{IgnoreCase: true},
+			want:     []int{3, 2},
+		},
+		{
+			name:     "Follows MatchKind",
+			patterns: []string{"he", "she"},
+			text:     "she he",
+			opts:     Options{MatchKind: LeftmostLongest},
+			want:     []int{1, 1},
+		},
+		{
+			name:     "No patterns",
+			patterns: []string{},
+			text:     "text",
+			want:     []int{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ac := NewWithOptions(tc.patterns, tc.opts)
+
+			got := ac.CountByPattern(tc.text)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("CountByPattern(%q) got %v, want %v", tc.text, got, tc.want)
+			}
+
+			gotBytes := ac.CountByPatternBytes([]byte(tc.text))
+			if !reflect.DeepEqual(gotBytes, tc.want) {
+				t.Errorf("CountByPatternBytes(%q) got %v, want %v", tc.text, gotBytes, tc.want)
+			}
+
+			total := 0
+			for _, n := range got {
+				total += n
+			}
+			if count := ac.Count(tc.text); total != count {
+				t.Errorf("CountByPattern(%q) adds up to %d, Count got %d", tc.text, total, count)
+			}
+		})
+	}
+}
+
+func TestAhoCorasickFindAllFunc(t *testing.T) {
+	patterns := []string{"he", "she", "his", "hers", "s"}
+	text := "ushers and his sheep"
+
+	for _, kind := range []MatchKind{Standard, LeftmostLongest, LeftmostFirst} {
+		ac := NewWithOptions(patterns, Options{MatchKind: kind})
+		want := ac.FindAll(text)
+
+		var got []ACMatch
+		ac.FindAllFunc(text, func(m ACMatch) bool {
+			got = append(got, m)
+			return true
+		})
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("kind %d: FindAllFunc(%q) = %v; want %v", kind, text, got, want)
+		}
+
+		var gotBytes []ACMatch
+		ac.FindAllFuncBytes([]byte(text), func(m ACMatch) bool {
+			gotBytes = append(gotBytes, m)
+			return true
+		})
+		if !reflect.DeepEqual(gotBytes, want) {
+			t.Errorf("kind %d: FindAllFuncBytes(%q) = %v; want %v", kind, text, gotBytes, want)
+		}
+
+		// Returning false stops the search right away
+		var first []ACMatch
+		ac.FindAllFunc(text, func(m ACMatch) bool {
+			first = append(first, m)
+			return len(first) < 2
+		})
+		if !reflect.DeepEqual(first, want[:2]) {
+			t.Errorf("kind %d: FindAllFunc with early stop = %v; want %v", kind, first, want[:2])
+		}
+	}
+}
+
+// TestAhoCorasickFindAllOrder pins the documented Standard order (End, then Start,
+// then PatternIndex) across the ways an automaton can be built and searched
+func TestAhoCorasickFindAllOrder(t *testing.T) {
+	patterns := []string{"he", "she", "hers", "e", "he", "ushe"}
+	text := "ushers"
+	want := []ACMatch{
+		{PatternIndex: 5, Start: 0, End: 3}, // "ushe"
+		{PatternIndex: 1, Start: 1, End: 3}, // "she"
+		{PatternIndex: 0, Start: 2, End: 3}, // "he"
+		{PatternIndex: 4, Start: 2, End: 3}, // "he" again
+		{PatternIndex: 3, Start: 3, End: 3}, // "e"
+		{PatternIndex: 2, Start: 2, End: 5}, // "hers"
+	}
+
+	incremental := New(patterns[:2], false)
+	for _, p := range patterns[2:] {
+		incremental.Add(p)
+	}
+	data, err := New(patterns, false).MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error: %v", err)
+	}
+	var loaded AhoCorasick
+	if err := loaded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary() error: %v", err)
+	}
+	automata := map[string]*AhoCorasick{
+		"New":             New(patterns, false),
+		"SparseMap":       NewWithOptions(patterns, Options{Backend: SparseMap}),
+		"DoubleArray":     NewWithOptions(patterns, Options{Backend: DoubleArray}),
+		"Add":             incremental,
+		"UnmarshalBinary": &loaded,
+	}
+
+	for name, ac := range automata {
+		t.Run(name, func(t *testing.T) {
+			if got := ac.FindAll(text); !reflect.DeepEqual(got, want) {
+				t.Errorf("FindAll(%q) got %v, want %v", text, got, want)
+			}
+			if got := ac.FindAllBytes([]byte(text)); !reflect.DeepEqual(got, want) {
+				t.Errorf("FindAllBytes(%q) got %v, want %v", text, got, want)
+			}
+			var got []ACMatch
+			ac.FindAllFunc(text, func(m ACMatch) bool {
+				got = append(got, m)
+				return true
+			})
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("FindAllFunc(%q) got %v, want %v", text, got, want)
+			}
+			got = nil
+			if err := ac.FindAllReader(strings.NewReader(text), func(m ACMatch) { got = append(got, m) }); err != nil {
+				t.Fatalf("FindAllReader() error: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("FindAllReader(%q) got %v, want %v", text, got, want)
+			}
+		})
+	}
+}
+
+func TestAhoCorasickFindAllSorted(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		text     string
+		opts     Options
+		want     []ACMatch
+	}{
+		{
+			name:     "Longer matches that end later come first",
+			patterns: []string{"he", "she", "his", "hers"},
+			text:     "ushers",
+			want: []ACMatch{
+				{PatternIndex: 1, Start: 1, End: 3},
+				{PatternIndex: 0, Start: 2, End: 3},
+				{PatternIndex: 3, Start: 2, End: 5},
+			},
+		},
+		{
+			name:     "Same start, shorter first",
+			patterns: []string{"abcd", "ab", "bc", "abc"},
+			text:     "abcd",
+			want: []ACMatch{
+				{PatternIndex: 1, Start: 0, End: 1},
+				{PatternIndex: 3, Start: 0, End: 2},
+				{PatternIndex: 0, Start: 0, End: 3},
+				{PatternIndex: 2, Start: 1, End: 2},
+			},
+		},
+		{
+			name:     "Duplicate patterns by index",
+			patterns: []string{"x", "ax", "x"},
+			text:     "ax",
+			want: []ACMatch{
+				{PatternIndex: 1, Start: 0, End: 1},
+				{PatternIndex: 0, Start: 1, End: 1},
+				{PatternIndex: 2, Start: 1, End: 1},
+			},
+		},
+		{
+			name:     "Leftmost matches are already sorted",
+			patterns: []string{"ab", "abcd", "cd"},
+			text:     "abcdab",
+			opts:     Options{MatchKind: LeftmostLongest},
+			want: []ACMatch{
+				{PatternIndex: 1, Start: 0, End: 3},
+				{PatternIndex: 0, Start: 4, End: 5},
+			},
+		},
+		{
+			name:     "No match",
+			patterns: []string{"x"},
+			text:     "abc",
+			want:     nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ac := NewWithOptions(tc.patterns, tc.opts)
+			if got := ac.FindAllSorted(tc.text); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("FindAllSorted(%q) got %v, want %v", tc.text, got, tc.want)
+			}
+			if got := ac.FindAllSortedBytes([]byte(tc.text)); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("FindAllSortedBytes(%q) got %v, want %v", tc.text, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAhoCorasickNewWithError(t *testing.T) {
+	tests := []struct {
+		name       string
+		patterns   []string
+		ignoreCase bool
+		wantErr    error
+	}{
+		{name: "Valid", patterns: []string{"he", "she", "hers"}},
+		{name: "No patterns", patterns: nil},
+		{name: "Empty pattern", patterns: []string{"he", ""}, wantErr: ErrEmptyPattern},
+		{name: "Duplicate pattern", patterns: []string{"he", "she", "he"}, wantErr: ErrDuplicatePattern},
+		{name: "Duplicate after folding", patterns: []string{"He", "hE"}, ignoreCase: true, wantErr: ErrDuplicatePattern},
+		{name: "Case differs without folding", patterns: []string{"He", "hE"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ac, err := NewWithError(tc.patterns, tc.ignoreCase)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("NewWithError(%q) error = %v; want %v", tc.patterns, err, tc.wantErr)
+			}
+			if err == nil && ac == nil {
+				t.Fatalf("NewWithError(%q) returned nil automaton", tc.patterns)
+			}
+			if err != nil && ac != nil {
+				t.Errorf("NewWithError(%q) returned an automaton along with error %v", tc.patterns, err)
+			}
+		})
+	}
+}
+
+func TestAhoCorasickNewFromBytes(t *testing.T) {
+	patterns := []string{"he", "She", "HIS", "hers", ""}
+	text := "ushers and HIS sHe"
+	for _, ignoreCase := range []bool{false, true} {
+		bs := make([][]byte, len(patterns))
+		for i, p := range patterns {
+			bs[i] = []byte(p)
+		}
+		ac := NewFromBytes(bs, ignoreCase)
+		want := New(patterns, ignoreCase)
+		if got := ac.FindAll(text); !reflect.DeepEqual(got, want.FindAll(text)) {
+			t.Errorf("ignoreCase=%v: FindAll(%q) got %v, want %v", ignoreCase, text, got, want.FindAll(text))
+		}
+		if ignoreCase && string(bs[1]) != "she" {
+			t.Errorf("pattern after NewFromBytes = %q, want it lowercased in place to %q", bs[1], "she")
+		}
+
+		// The patterns as given are kept, so the automaton can still be rebuilt
+		ac.Rebuild(!ignoreCase)
+		want.Rebuild(!ignoreCase)
+		if got := ac.FindAll(text); !reflect.DeepEqual(got, want.FindAll(text)) {
+			t.Errorf("after Rebuild(%v): FindAll(%q) got %v, want %v", !ignoreCase, text, got, want.FindAll(text))
+		}
+	}
+}
+
+func TestAhoCorasickNewDedup(t *testing.T) {
+	tests := []struct {
+		name        string
+		patterns    []string
+		ignoreCase  bool
+		text        string
+		wantIndex   []int
+		wantMatches []ACMatch
+	}{
+		{
+			name:      "No duplicates",
+			patterns:  []string{"he", "she"},
+			text:      "she",
+			wantIndex: []int{0, 1},
+			wantMatches: []ACMatch{
+				{PatternIndex: 1, Start: 0, End: 2},
+				{PatternIndex: 0, Start: 1, End: 2},
+			},
+		},
+		{
+			name:      "Duplicates share the first index",
+			patterns:  []string{"he", "she", "he", "she", "hers"},
+			text:      "shers",
+			wantIndex: []int{0, 1, 0, 1, 2},
+			wantMatches: []ACMatch{
+				{PatternIndex: 1, Start: 0, End: 2},
+				{PatternIndex: 0, Start: 1, End: 2},
+				{PatternIndex: 2, Start: 1, End: 4},
+			},
+		},
+		{
+			name:        "Duplicates after folding",
+			patterns:    []string{"He", "hE", "x"},
+			ignoreCase:  true,
+			text:        "HE",
+			wantIndex:   []int{0, 0, 1},
+			wantMatches: []ACMatch{{PatternIndex: 0, Start: 0, End: 1}},
+		},
+		{
+			name:        "Case differs without folding",
+			patterns:    []string{"He", "hE"},
+			text:        "hE",
+			wantIndex:   []int{0, 1},
+			wantMatches: []ACMatch{{PatternIndex: 1, Start: 0, End: 1}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ac, index := NewDedup(tc.patterns, Options{IgnoreCase: tc.ignoreCase})
+			if !reflect.DeepEqual(index, tc.wantIndex) {
+				t.Errorf("NewDedup(%q) index = %v; want %v", tc.patterns, index, tc.wantIndex)
+			}
+			if got := ac.FindAll(tc.text); !reflect.DeepEqual(got, tc.wantMatches) {
+				t.Errorf("FindAll(%q) = %v; want %v", tc.text, got, tc.wantMatches)
+			}
+		})
+	}
+}
+
+func TestAhoCorasickFindAllLongest(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		text     string
+		kind     MatchKind
+		want     []ACMatch
+	}{
+		{
+			name:     "Shorter suffix dropped",
+			patterns: []string{"he", "she", "hers"},
+			text:     "ushers",
+			want: []ACMatch{
+				{PatternIndex: 1, Start: 1, End: 3},
+				{PatternIndex: 2, Start: 2, End: 5},
+			},
+		},
+		{
+			name:     "Inherited pattern longer than a shorter one",
+			patterns: []string{"c", "bc", "abc"},
+			text:     "abcbc",
+			want: []ACMatch{
+				{PatternIndex: 2, Start: 0, End: 2},
+				{PatternIndex: 1, Start: 3, End: 4},
+			},
+		},
+		{
+			name:     "Overlapping ends are kept",
+			patterns: []string{"aa"},
+			text:     "aaaa",
+			want: []ACMatch{
+				{PatternIndex: 0, Start: 0, End: 1},
+				{PatternIndex: 0, Start: 1, End: 2},
+				{PatternIndex: 0, Start: 2, End: 3},
+			},
+		},
+		{
+			name:     "Duplicate keyword reports lowest index",
+			patterns: []string{"x", "ab", "ab"},
+			text:     "ab",
+			want:     []ACMatch{{PatternIndex: 1, Start: 0, End: 1}},
+		},
+		{
+			name:     "Independent of MatchKind",
+			patterns: []string{"he", "she"},
+			text:     "she",
+			kind:     LeftmostFirst,
+			want:     []ACMatch{{PatternIndex: 1, Start: 0, End: 2}},
+		},
+		{
+			name:     "No match",
+			patterns: []string{"cat"},
+			text:     "dog",
+			want:     nil,
+		},
+	}
+
+	for _, tc := range tests {
+		for _, backend := range []Backend{ArrayOfArrays, SparseMap, DoubleArray} {
+			t.Run(tc.name, func(t *testing.T) {
+				ac := NewWithOptions(tc.patterns, Options{MatchKind: tc.kind, Backend: backend})
+				if got := ac.FindAllLongest(tc.text); !reflect.DeepEqual(got, tc.want) {
+					t.Errorf("backend %d: FindAllLongest(%q) = %v; want %v", backend, tc.text, got, tc.want)
+				}
+				if got := ac.FindAllLongestBytes([]byte(tc.text)); !reflect.DeepEqual(got, tc.want) {
+					t.Errorf("backend %d: FindAllLongestBytes(%q) = %v; want %v", backend, tc.text, got, tc.want)
+				}
+			})
+		}
+	}
+}
+
+func TestAhoCorasickMatchedPatterns(t *testing.T) {
+	type testCase struct {
+		name     string
+		patterns []string
+		text     string
+		opts     Options
+		want     []bool
+	}
+	tests := []testCase{
+		{
+			name:     "Presence only",
+			patterns: []string{"a", "ab", "zz", "b"},
+			text:     "abab ba",
+			want:     []bool{true, true, false, true},
+		},
+		{
+			name:     "Nested occurrences count whatever the MatchKind",
+			patterns: []string{"he", "she"},
+			text:     "she",
+			opts:     Options{MatchKind: LeftmostLongest},
+			want:     []bool{true, true},
+		},
+		{
+			name:     "Duplicates are both marked",
+			patterns: []string{"x", "x", "y"},
+			text:     "xxxx",
+			want:     []bool{true, true, false},
+		},
+		{
+			name:     "Ignore case",
+			patterns: []string{"Go", "rust"},
+			text:     "GO go",
+			opts:     Options{IgnoreCase: true},
+			want:     []bool{true, false},
+		},
+		{
+			name:     "No patterns",
+			patterns: []string{},
+			text:     "text",
+			want:     []bool{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ac := NewWithOptions(tc.patterns, tc.opts)
+			if got := ac.MatchedPatterns(tc.text); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("MatchedPatterns(%q) got %v, want %v", tc.text, got, tc.want)
+			}
+			if got := ac.MatchedPatternsBytes([]byte(tc.text)); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("MatchedPatternsBytes(%q) got %v, want %v", tc.text, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAhoCorasickRemove(t *testing.T) {
+	type testCase struct {
+		name     string
+		patterns []string
+		removed  []int
+		text     string
+		kind     MatchKind
+	}
+	tests := []testCase{
+		{
+			name:     "Remove a pattern sharing a prefix",
+			patterns: []string{"he", "she", "his", "hers"},
+			removed:  []int{3},
+			text:     "ushers and his",
+		},
+		{
+			name:     "Remove a suffix reported through failure links",
+			patterns: []string{"abc", "bc", "c"},
+			removed:  []int{1},
+			text:     "xabcabc",
+		},
+		{
+			name:     "Remove one copy of a duplicate",
+			patterns: []string{"ab", "ab"},
+			removed:  []int{0},
+			text:     "abab",
+		},
+		{
+			name:     "Remove every pattern",
+			patterns: []string{"a", "b"},
+			removed:  []int{0, 1},
+			text:     "abab",
+		},
+		{
+			name:     "Leftmost-longest falls back to the shorter pattern",
+			patterns: []string{"abcd", "ab", "cd"},
+			removed:  []int{0},
+			text:     "abcd",
+			kind:     LeftmostLongest,
+		},
+		{
+			name:     "Leftmost-first falls back to the next pattern",
+			patterns: []string{"abc", "a", "bcd"},
+			removed:  []int{1},
+			text:     "abcd",
+			kind:     LeftmostFirst,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := Options{MatchKind: tc.kind}
+			ac := NewWithOptions(tc.patterns, opts)
+			remaining := append([]string{}, tc.patterns...)
+			for _, idx := range tc.removed {
+				if !ac.Remove(idx) {
+					t.Fatalf("Remove(%d) got false, want true", idx)
+				}
+				remaining[idx] = ""
+			}
+
+			want := NewWithOptions(remaining, opts).FindAll(tc.text)
+			if got := ac.FindAll(tc.text); !reflect.DeepEqual(got, want) {
+				t.Errorf("FindAll(%q) after Remove got %v, want %v", tc.text, got, want)
+			}
+
+			nodes := ac.numNodes()
+			ac.Recompile()
+			if got := ac.FindAll(tc.text); !reflect.DeepEqual(got, want) {
+				t.Errorf("FindAll(%q) after Recompile got %v, want %v", tc.text, got, want)
+			}
+			if ac.numNodes() > nodes {
+				t.Errorf("Recompile grew the trie from %d to %d nodes", nodes, ac.numNodes())
+			}
+		})
+	}
+}
+
+func TestAhoCorasickRemoveIndices(t *testing.T) {
+	ac := New([]string{"he", "she", "hers"}, false)
+
+	for _, idx := range []int{-1, 3} {
+		if ac.Remove(idx) {
+			t.Errorf("Remove(%d) got true for an out-of-range index", idx)
+		}
+	}
+	if !ac.Remove(1) {
+		t.Fatal("Remove(1) got false, want true")
+	}
+	if ac.Remove(1) {
+		t.Error("Remove(1) got true for a pattern already removed")
+	}
+
+	// Removed indices are not reused, and the remaining patterns keep theirs
+	if got := ac.Add("us"); got != 3 {
+		t.Errorf("Add after Remove got index %d, want 3", got)
+	}
+	want := []ACMatch{
+		{PatternIndex: 3, Start: 0, End: 1},
+		{PatternIndex: 0, Start: 2, End: 3},
+		{PatternIndex: 2, Start: 2, End: 5},
+	}
+	if got := ac.FindAll("ushers"); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindAll after Remove and Add got %v, want %v", got, want)
+	}
+
+	// Recompile drops the nodes of "she" but keeps every index
+	nodes := ac.numNodes()
+	ac.Recompile()
+	if ac.numNodes() != nodes-3 {
+		t.Errorf("Recompile left %d nodes, want %d", ac.numNodes(), nodes-3)
+	}
+	if got := ac.FindAll("ushers"); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindAll after Recompile got %v, want %v", got, want)
+	}
+
+	// A removed pattern stays removed through MarshalBinary
+	data, err := ac.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+	var restored AhoCorasick
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary failed: %v", err)
+	}
+	if got := restored.FindAll("ushers"); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindAll after UnmarshalBinary got %v, want %v", got, want)
+	}
+}
+
+func TestAhoCorasickContainsDoesNotAllocate(t *testing.T) {
+	patterns := []string{"needle", "STRASSE", "ǅemal"}
+	kinds := []MatchKind{Standard, LeftmostFirst, LeftmostLongest}
+	texts := []string{
+		strings.Repeat("haystack ", 100) + "needle",
+		strings.Repeat("Straße ǆ \xff ", 100) + "Strasse",
+		strings.Repeat("no match here ", 100),
+	}
+	for _, runes := range []bool{false, true} {
+		for _, kind := range kinds {
+			ac := NewWithOptions(patterns, Options{IgnoreCase: true, Runes: runes, MatchKind: kind})
+			for _, text := range texts {
+				want := len(ac.FindAll(text)) > 0
+				allocs := testing.AllocsPerRun(10, func() {
+					if got := ac.Contains(text); got != want {
+						t.Fatalf("Contains got %v, want %v (runes=%v, kind=%v)", got, want, runes, kind)
+					}
+					if got := ac.ContainsBytes([]byte(text)); got != want {
+						t.Fatalf("ContainsBytes got %v, want %v (runes=%v, kind=%v)", got, want, runes, kind)
+					}
+				})
+				if allocs != 0 {
+					t.Errorf("Contains allocated %v times per run; want 0 (runes=%v, kind=%v)", allocs, runes, kind)
+				}
+			}
+		}
+	}
+}
+
+func TestAhoCorasickFindAllInto(t *testing.T) {
+	patterns := []string{"he", "she", "his", "hers"}
+	texts := []string{"ushers", "", "nothing", "his hers she"}
+	for _, kind := range []MatchKind{Standard, LeftmostFirst, LeftmostLongest} {
+		for _, runes := range []bool{false, true} {
+			ac := NewWithOptions(patterns, Options{MatchKind: kind, Runes: runes})
+			for _, text := range texts {
+				want := ac.FindAll(text)
+
+				// Matches are appended after what dst already holds
+				prefix := []ACMatch{{PatternIndex: -1}}
+				got := ac.FindAllInto(text, prefix)
+				if len(got) != len(want)+1 || got[0] != prefix[0] || (len(want) > 0 && !reflect.DeepEqual(got[1:], want)) {
+					t.Errorf("FindAllInto(%q) got %v, want %v after %v (kind=%v, runes=%v)", text, got, want, prefix, kind, runes)
+				}
+
+				// Passing dst[:0] reuses its storage
+				again := ac.FindAllIntoBytes([]byte(text), got[:0])
+				if len(want) > 0 && (!reflect.DeepEqual(again, want) || &again[0] != &got[0]) {
+					t.Errorf("FindAllIntoBytes(%q, dst[:0]) got %v, want %v in dst's storage (kind=%v, runes=%v)", text, again, want, kind, runes)
+				}
+			}
+		}
+	}
+}
+
+func TestAhoCorasickFindAllIntoDoesNotAllocate(t *testing.T) {
+	texts := []string{"ushers", "nothing", "his hers she"}
+	for _, kind := range []MatchKind{Standard, LeftmostFirst, LeftmostLongest} {
+		ac := NewWithOptions([]string{"he", "she", "his", "hers"}, Options{MatchKind: kind})
+		buf := make([]ACMatch, 0, 16)
+		allocs := testing.AllocsPerRun(10, func() {
+			for _, text := range texts {
+				buf = ac.FindAllInto(text, buf[:0])
+			}
+		})
+		if allocs != 0 {
+			t.Errorf("FindAllInto into a large enough buffer allocated %v times per run; want 0 (kind=%v)", allocs, kind)
+		}
+	}
+}
+
+func TestAhoCorasickPatternLens(t *testing.T) {
+	type testCase struct {
+		name     string
+		patterns []string
+		opts     Options
+		wantMin  int
+		wantMax  int
+	}
+	tests := []testCase{
+		{"Bytes", []string{"he", "she", "hers"}, Options{}, 2, 4},
+		{"Empty patterns are ignored", []string{"", "abc", "", "de"}, Options{}, 2, 3},
+		{"Only empty patterns", []string{""}, Options{}, 0, 0},
+		{"No patterns", nil, Options{}, 0, 0},
+		{"Multi-byte keywords count bytes", []string{"世界", "a"}, Options{}, 1, 6},
+		{"Rune mode counts runes", []string{"世界", "abc"}, Options{Runes: true}, 2, 3},
+		{"Wildcards count one position each", []string{"e##"}, Options{Wildcards: map[byte]byteclass.Set{'#': byteclass.Any()}}, 3, 3},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ac := NewWithOptions(tc.patterns, tc.opts)
+			if got := ac.MinPatternLen(); got != tc.wantMin {
+				t.Errorf("MinPatternLen() = %d; want %d", got, tc.wantMin)
+			}
+			if got := ac.MaxPatternLen(); got != tc.wantMax {
+				t.Errorf("MaxPatternLen() = %d; want %d", got, tc.wantMax)
+			}
+		})
+	}
+
+	// Add, Remove and UnmarshalBinary keep the lengths current
+	ac := New([]string{"abc", "de"}, false)
+	ac.Add("fghij")
+	ac.Remove(1)
+	if ac.MinPatternLen() != 3 || ac.MaxPatternLen() != 5 {
+		t.Errorf("after Add and Remove: MinPatternLen() = %d, MaxPatternLen() = %d; want 3, 5",
+			ac.MinPatternLen(), ac.MaxPatternLen())
+	}
+	data, err := ac.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary returned error: %v", err)
+	}
+	var loaded AhoCorasick
+	if err := loaded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary returned error: %v", err)
+	}
+	if loaded.MinPatternLen() != 3 || loaded.MaxPatternLen() != 5 {
+		t.Errorf("after UnmarshalBinary: MinPatternLen() = %d, MaxPatternLen() = %d; want 3, 5",
+			loaded.MinPatternLen(), loaded.MaxPatternLen())
+	}
+}
+
+func TestAhoCorasickEqual(t *testing.T) {
+	patterns := []string{"he", "she", "his"}
+	removed := New([]string{"he", "she", "his"}, false)
+	removed.Remove(1)
+	bounded := NewWithOptions(patterns, Options{TokenBoundary: func(c byte) bool { return c == ' ' }})
+	tests := []struct {
+		name string
+		a, b *AhoCorasick
+		want bool
+	}{
+		{"Same patterns", New(patterns, false), New(patterns, false), true},
+		{"Different order", New(patterns, false), New([]string{"she", "he", "his"}, false), false},
+		{"Extra pattern", New(patterns, false), New(append(patterns, "hers"), false), false},
+		{"Case differs", New([]string{"HE"}, false), New([]string{"he"}, false), false},
+		{"Case folded away", New([]string{"HE", "She"}, true), New([]string{"he", "shE"}, true), true},
+		{"IgnoreCase differs", New(patterns, true), New(patterns, false), false},
+		{"MatchKind differs", New(patterns, false), NewWithOptions(patterns, Options{MatchKind: LeftmostLongest}), false},
+		{"Runes differs", New(patterns, false), NewWithOptions(patterns, Options{Runes: true}), false},
+		{"Backend is ignored", New(patterns, false), NewWithOptions(patterns, Options{Backend: SparseMap}), true},
+		{"Added pattern", func() *AhoCorasick { ac := New(patterns[:2], false); ac.Add("his"); return ac }(), New(patterns, false), true},
+		{"Removed pattern", removed, New([]string{"he", "", "his"}, false), true},
+		{"TokenBoundary is not comparable", bounded, NewWithOptions(patterns, bounded.options()), false},
+		{"TokenBoundary against itself", bounded, bounded, true},
+		{"Nil", nil, nil, true},
+		{"Nil against an automaton", New(nil, false), nil, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.a.Equal(tc.b); got != tc.want {
+				t.Errorf("a.Equal(b) = %v; want %v", got, tc.want)
+			}
+			if got := tc.b.Equal(tc.a); got != tc.want {
+				t.Errorf("b.Equal(a) = %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAhoCorasickFindAllCapped(t *testing.T) {
+	patterns := []string{"a", "ab", "", "b", "zz"}
+	texts := []string{"", "abababab", "aaab", "bbbbb ab", "xyz"}
+	for _, kind := range []MatchKind{Standard, LeftmostFirst, LeftmostLongest} {
+		for _, runes := range []bool{false, true} {
+			ac := NewWithOptions(patterns, Options{MatchKind: kind, Runes: runes})
+			for _, text := range texts {
+				all := ac.FindAll(text)
+				for _, capPerPattern := range []int{-1, 0, 1, 2, 100} {
+					// the reference keeps the first capPerPattern matches of each pattern in FindAll
+					var want []ACMatch
+					seen := make([]int, len(patterns))
+					for _, m := range all {
+						if capPerPattern < 0 || seen[m.PatternIndex] < capPerPattern {
+							want = append(want, m)
+							seen[m.PatternIndex]++
+						}
+					}
+
+					got := ac.FindAllCapped(text, capPerPattern)
+					if !reflect.DeepEqual(got, want) {
+						t.Errorf("FindAllCapped(%q, %d) = %v, want %v (kind=%v, runes=%v)", text, capPerPattern, got, want, kind, runes)
+					}
+					if gotBytes := ac.FindAllCappedBytes([]byte(text), capPerPattern); !reflect.DeepEqual(gotBytes, want) {
+						t.Errorf("FindAllCappedBytes(%q, %d) = %v, want %v (kind=%v, runes=%v)", text, capPerPattern, gotBytes, want, kind, runes)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestAhoCorasickFindAllCappedFirstMatches(t *testing.T) {
+	ac := New([]string{"a", "b"}, false)
+	text := "ab" + strings.Repeat("a", 1000) + "b"
+	got := ac.FindAllCapped(text, 1)
+	want := []ACMatch{{PatternIndex: 0, Start: 0, End: 0}, {PatternIndex: 1, Start: 1, End: 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindAllCapped = %v, want %v", got, want)
+	}
+}
+
+func TestAhoCorasickFindAllMinLen(t *testing.T) {
+	patterns := []string{"a", "ab", "abc", "bc", "é", "éé", "c"}
+	texts := []string{"", "abcabc", "ab bc c", "éé é aé", "xyz"}
+	for _, kind := range []MatchKind{Standard, LeftmostFirst, LeftmostLongest} {
+		for _, runes := range []bool{false, true} {
+			opts := Options{MatchKind: kind, Runes: runes}
+			for _, minLen := range []int{-1, 0, 1, 2, 3, 4} {
+				// the reference automaton has the short patterns blanked out, keeping the indices
+				kept := make([]string, len(patterns))
+				for i, p := range patterns {
+					n := len(p)
+					if runes {
+						n = utf8.RuneCountInString(p)
+					}
+					if n >= minLen {
+						kept[i] = p
+					}
+				}
+				ac, ref := NewWithOptions(patterns, opts), NewWithOptions(kept, opts)
+
+				for _, text := range texts {
+					want := ref.FindAll(text)
+					if got := ac.FindAllMinLen(text, minLen); !reflect.DeepEqual(got, want) {
+						t.Errorf("FindAllMinLen(%q, %d) = %v, want %v (kind=%v, runes=%v)", text, minLen, got, want, kind, runes)
+					}
+					if got := ac.FindAllMinLenBytes([]byte(text), minLen); !reflect.DeepEqual(got, want) {
+						t.Errorf("FindAllMinLenBytes(%q, %d) = %v, want %v (kind=%v, runes=%v)", text, minLen, got, want, kind, runes)
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestAhoCorasickEmptyPatternNeverMatches locks down the policy shared by every package in the
+// module: an empty pattern never matches, rather than matching at every position, in every API
+// and mode, alone or next to other patterns.
+func TestAhoCorasickEmptyPatternNeverMatches(t *testing.T) {
+	// reported tells whether pattern 0, the empty one, shows up through each API
+	checks := []struct {
+		name     string
+		reported func(ac *AhoCorasick, text string) bool
+	}{
+		{"FindAll", func(ac *AhoCorasick, text string) bool { return hasPattern0(ac.FindAll(text)) }},
+		{"FindAllBytes", func(ac *AhoCorasick, text string) bool { return hasPattern0(ac.FindAllBytes([]byte(text))) }},
+		{"FindAllNonOverlapping", func(ac *AhoCorasick, text string) bool { return hasPattern0(ac.FindAllNonOverlapping(text)) }},
+		{"FindAllLongest", func(ac *AhoCorasick, text string) bool { return hasPattern0(ac.FindAllLongest(text)) }},
+		{"FindAllWholeWord", func(ac *AhoCorasick, text string) bool { return hasPattern0(ac.FindAllWholeWord(text)) }},
+		{"FindAllInto", func(ac *AhoCorasick, text string) bool { return hasPattern0(ac.FindAllInto(text, nil)) }},
+		{"FindAllFunc", func(ac *AhoCorasick, text string) bool {
+			var ms []ACMatch
+			ac.FindAllFunc(text, func(m ACMatch) bool { ms = append(ms, m); return true })
+			return hasPattern0(ms)
+		}},
+		{"Scanner", func(ac *AhoCorasick, text string) bool {
+			var ms []ACMatch
+			for s := NewScanner(ac, strings.NewReader(text)); s.Scan(); {
+				ms = append(ms, s.Match())
+			}
+			return hasPattern0(ms)
+		}},
+		{"CountByPattern", func(ac *AhoCorasick, text string) bool { return ac.CountByPattern(text)[0] != 0 }},
+		{"MatchedPatterns", func(ac *AhoCorasick, text string) bool { return ac.MatchedPatterns(text)[0] }},
+		{"MatchPrefix", func(ac *AhoCorasick, text string) bool {
+			m, ok := ac.MatchPrefix(text)
+			return ok && m.PatternIndex == 0
+		}},
+		{"ReplaceFunc", func(ac *AhoCorasick, text string) bool {
+			var ms []ACMatch
+			ac.ReplaceFunc(text, func(m ACMatch) string { ms = append(ms, m); return "" })
+			return hasPattern0(ms)
+		}},
+	}
+
+	for _, patterns := range [][]string{{""}, {"", "a", "bc"}} {
+		for _, kind := range []MatchKind{Standard, LeftmostFirst, LeftmostLongest} {
+			for _, runes := range []bool{false, true} {
+				ac := NewWithOptions(patterns, Options{MatchKind: kind, Runes: runes})
+				for _, text := range []string{"", "a", "abc", "naïve"} {
+					for _, c := range checks {
+						if c.reported(ac, text) {
+							t.Errorf("%s(%q) with patterns %q (kind=%v, runes=%v) reported the empty pattern",
+								c.name, text, patterns, kind, runes)
+						}
+					}
+					if len(patterns) == 1 && (ac.Contains(text) || ac.Count(text) != 0) {
+						t.Errorf("Contains or Count(%q) (kind=%v, runes=%v) counted the empty pattern", text, kind, runes)
+					}
+				}
+			}
+		}
+	}
+}
+
+// hasPattern0 reports whether ms holds a match of pattern 0
+func hasPattern0(ms []ACMatch) bool {
+	return slices.ContainsFunc(ms, func(m ACMatch) bool { return m.PatternIndex == 0 })
+}
+
+func TestAhoCorasickRebuild(t *testing.T) {
+	ac := NewWithOptions([]string{"He", "SHE"}, Options{MatchKind: LeftmostLongest})
+	ac.Add("Hers")
+	text := "she SHE hers Hers"
+
+	want := []ACMatch{
+		{PatternIndex: 1, Start: 4, End: 6},
+		{PatternIndex: 2, Start: 13, End: 16},
+	}
+	if got := ac.FindAll(text); !reflect.DeepEqual(got, want) {
+		t.Fatalf("FindAll(%q) got %v, want %v", text, got, want)
+	}
+
+	ac.Rebuild(true)
+	want = []ACMatch{
+		{PatternIndex: 1, Start: 0, End: 2},
+		{PatternIndex: 1, Start: 4, End: 6},
+		{PatternIndex: 2, Start: 8, End: 11},
+		{PatternIndex: 2, Start: 13, End: 16},
+	}
+	if got := ac.FindAll(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindAll(%q) after Rebuild(true) got %v, want %v", text, got, want)
+	}
+
+	// Switching back restores the original casing of the patterns, not their folded form
+	ac.Rebuild(false)
+	if got := ac.FindAll("he He"); !reflect.DeepEqual(got, []ACMatch{{PatternIndex: 0, Start: 3, End: 4}}) {
+		t.Errorf("FindAll after Rebuild(false) got %v", got)
+	}
+}
+
+// naiveFindAll returns every occurrence of every non-empty pattern in text, found with
+// strings.Index after lowercasing ASCII letters of both if ignoreCase is set,
+// sorted by End, then by decreasing length, then by PatternIndex like Standard matches.
+func naiveFindAll(text string, patterns []string, ignoreCase bool) []ACMatch {
+	var matches []ACMatch
+	for idx, p := range patterns {
+		if p == "" {
+			continue
+		}
+		t := text
+		if ignoreCase {
+			t, p = string(foldASCII([]byte(t))), string(foldASCII([]byte(p)))
+		}
+		for from := 0; ; {
+			i := strings.Index(t[from:], p)
+			if i < 0 {
+				break
+			}
+			start := from + i
+			matches = append(matches, ACMatch{PatternIndex: idx, Start: start, End: start + len(p) - 1})
+			from = start + 1
+		}
+	}
+	slices.SortFunc(matches, func(a, b ACMatch) int {
+		if a.End != b.End {
+			return a.End - b.End
+		}
+		if a.Start != b.Start {
+			return a.Start - b.Start
+		}
+		return a.PatternIndex - b.PatternIndex
+	})
+	return matches
+}
+
+// naiveLeftmost picks from Standard matches (as naiveFindAll returns them) the ones the
+// leftmost kinds report: the earliest-starting match, then, among those starting there, the
+// longest (LeftmostLongest) or the earliest pattern (LeftmostFirst), and so on after its End.
+// Identical keywords tie under LeftmostLongest; the stable sort keeps the lowest PatternIndex.
+func naiveLeftmost(all []ACMatch, kind MatchKind) []ACMatch {
+	sorted := slices.Clone(all)
+	slices.SortStableFunc(sorted, func(a, b ACMatch) int {
+		switch {
+		case betterLeftmost(a, b, kind):
+			return -1
+		case betterLeftmost(b, a, kind):
+			return 1
+		}
+		return 0
+	})
+	var matches []ACMatch
+	pos := 0
+	for _, m := range sorted {
+		if m.Start >= pos {
+			matches = append(matches, m)
+			pos = m.End + 1
+		}
+	}
+	return matches
+}
+
+func TestAhoCorasickRootSkip(t *testing.T) {
+	// Most text bytes cannot begin a pattern, so the search spends its time skipping at the root
+	rng := rand.New(rand.NewPCG(7, 7))
+	const alphabet = "abcdefgh XYZxyz"
+	randomText := func(n int) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = alphabet[rng.IntN(len(alphabet))]
+		}
+		return string(b)
+	}
+	patterns := []string{"X", "Xy", "Yz", "zab", "ZaB", "xyzX"}
+	texts := []string{"", "abc", "X", "abcX", randomText(100), randomText(1000), randomText(3 * stopCheckInterval)}
+
+	for _, ignoreCase := range []bool{false, true} {
+		for _, backend := range []Backend{ArrayOfArrays, SparseMap, DoubleArray} {
+			for _, kind := range []MatchKind{Standard, LeftmostFirst, LeftmostLongest} {
+				built := NewWithOptions(patterns[:2], Options{IgnoreCase: ignoreCase, Backend: backend, MatchKind: kind})
+				for _, p := range patterns[2:] {
+					built.Add(p)
+				}
+				data, err := built.MarshalBinary()
+				if err != nil {
+					t.Fatalf("MarshalBinary failed: %v", err)
+				}
+				restored := &AhoCorasick{}
+				if err := restored.UnmarshalBinary(data); err != nil {
+					t.Fatalf("UnmarshalBinary failed: %v", err)
+				}
+
+				for _, ac := range []*AhoCorasick{built, restored} {
+					for _, text := range texts {
+						all := naiveFindAll(text, patterns, ignoreCase)
+						want := all
+						if kind != Standard {
+							want = naiveLeftmost(all, kind)
+						}
+						if got := ac.FindAll(text); !reflect.DeepEqual(got, want) {
+							t.Fatalf("ignoreCase=%v backend=%d kind=%v: FindAll(%.20q...) got %d matches, want %d",
+								ignoreCase, backend, kind, text, len(got), len(want))
+						}
+						if got, err := ac.FindAllContext(context.Background(), text); err != nil || !reflect.DeepEqual(got, want) {
+							t.Errorf("ignoreCase=%v backend=%d kind=%v: FindAllContext(%.20q...) got %d matches (err %v), want %d",
+								ignoreCase, backend, kind, text, len(got), err, len(want))
+						}
+						if got := ac.Contains(text); got != (len(want) > 0) {
+							t.Errorf("ignoreCase=%v backend=%d kind=%v: Contains(%.20q...) got %v", ignoreCase, backend, kind, text, got)
+						}
+						var streamed []ACMatch
+						for s := NewScanner(ac, strings.NewReader(text)); s.Scan(); {
+							streamed = append(streamed, s.Match())
+						}
+						if !reflect.DeepEqual(streamed, all) {
+							t.Errorf("ignoreCase=%v backend=%d: Scanner over %.20q... got %d matches, want %d",
+								ignoreCase, backend, text, len(streamed), len(all))
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func FuzzAhoCorasick(f *testing.F) {
+	f.Add("ushers", "he,she,his,hers", false)
+	f.Add("USHERS", "he,She,his,hers", true)
+	f.Add("aaaa", "a,aa,aaa,a", false)
+	f.Add("abcd", "bcd,abcd,cd,c", false)
+	f.Add("[@`{", "@,`", true) // bytes next to the ASCII letter ranges
+	f.Add("ab", ",a,", false)
+
+	f.Fuzz(func(t *testing.T, text, patternList string, ignoreCase bool) {
+		// Empty patterns never match, like in naiveFindAll
+		patterns := strings.Split(patternList, ",")
+		want := naiveFindAll(text, patterns, ignoreCase)
+
+		for _, backend := range []Backend{ArrayOfArrays, SparseMap, DoubleArray} {
+			ac := NewWithOptions(patterns, Options{IgnoreCase: ignoreCase, Backend: backend})
+			if got := ac.FindAll(text); !reflect.DeepEqual(got, want) {
+				t.Errorf("backend %d: FindAll(%q) with %q = %v; want %v", backend, text, patterns, got, want)
+			}
+			if got := ac.Count(text); got != len(want) {
+				t.Errorf("backend %d: Count(%q) with %q = %d; want %d", backend, text, patterns, got, len(want))
+			}
+			if got := ac.Contains(text); got != (len(want) > 0) {
+				t.Errorf("backend %d: Contains(%q) with %q = %v; want %v", backend, text, patterns, got, len(want) > 0)
+			}
+		}
+	})
+}
+
+func TestAhoCorasickNestedPatterns(t *testing.T) {
+	type testCase struct {
+		name     string
+		patterns []string
+		text     string
+		kind     MatchKind
+		want     []ACMatch
+	}
+	tests := []testCase{
+		{
+			name:     "Prefix at the root",
+			patterns: []string{"a", "ab"},
+			text:     "ab",
+			want: []ACMatch{
+				{PatternIndex: 0, Start: 0, End: 0},
+				{PatternIndex: 1, Start: 0, End: 1},
+			},
+		},
+		{
+			name:     "Prefix given second",
+			patterns: []string{"ab", "a"},
+			text:     "abab",
+			want: []ACMatch{
+				{PatternIndex: 1, Start: 0, End: 0},
+				{PatternIndex: 0, Start: 0, End: 1},
+				{PatternIndex: 1, Start: 2, End: 2},
+				{PatternIndex: 0, Start: 2, End: 3},
+			},
+		},
+		{
+			name:     "Chain of prefixes",
+			patterns: []string{"abc", "ab", "a"},
+			text:     "abc",
+			want: []ACMatch{
+				{PatternIndex: 2, Start: 0, End: 0},
+				{PatternIndex: 1, Start: 0, End: 1},
+				{PatternIndex: 0, Start: 0, End: 2},
+			},
+		},
+		{
+			name:     "Suffix reached through a failure link",
+			patterns: []string{"abc", "bc", "c"},
+			text:     "abc",
+			want: []ACMatch{
+				{PatternIndex: 0, Start: 0, End: 2},
+				{PatternIndex: 1, Start: 1, End: 2},
+				{PatternIndex: 2, Start: 2, End: 2},
+			},
+		},
+		{
+			name:     "Prefix of a failed longer match",
+			patterns: []string{"a", "abc"},
+			text:     "abd",
+			want:     []ACMatch{{PatternIndex: 0, Start: 0, End: 0}},
+		},
+		{
+			name:     "Empty pattern never matches",
+			patterns: []string{"", "a"},
+			text:     "aba",
+			want: []ACMatch{
+				{PatternIndex: 1, Start: 0, End: 0},
+				{PatternIndex: 1, Start: 2, End: 2},
+			},
+		},
+		{
+			name:     "Only an empty pattern",
+			patterns: []string{""},
+			text:     "abc",
+			want:     nil,
+		},
+		{
+			name:     "Empty pattern under LeftmostFirst",
+			patterns: []string{"", "ab"},
+			text:     "abab",
+			kind:     LeftmostFirst,
+			want: []ACMatch{
+				{PatternIndex: 1, Start: 0, End: 1},
+				{PatternIndex: 1, Start: 2, End: 3},
+			},
+		},
+		{
+			name:     "Prefix under LeftmostLongest",
+			patterns: []string{"a", "ab"},
+			text:     "ab",
+			kind:     LeftmostLongest,
+			want:     []ACMatch{{PatternIndex: 1, Start: 0, End: 1}},
+		},
+		{
+			name:     "Prefix under LeftmostFirst",
+			patterns: []string{"a", "ab"},
+			text:     "ab",
+			kind:     LeftmostFirst,
+			want:     []ACMatch{{PatternIndex: 0, Start: 0, End: 0}},
+		},
+	}
+
+	for _, tc := range tests {
+		for _, backend := range []Backend{ArrayOfArrays, SparseMap, DoubleArray} {
+			t.Run(tc.name, func(t *testing.T) {
+				ac := NewWithOptions(tc.patterns, Options{MatchKind: tc.kind, Backend: backend})
+				if got := ac.FindAll(tc.text); !reflect.DeepEqual(got, tc.want) {
+					t.Errorf("backend %d: FindAll(%q) got %v, want %v", backend, tc.text, got, tc.want)
+				}
+
+				// Adding the patterns one by one must build the same automaton
+				inc := NewWithOptions(nil, Options{MatchKind: tc.kind, Backend: backend})
+				for _, p := range tc.patterns {
+					inc.Add(p)
+				}
+				if got := inc.FindAll(tc.text); !reflect.DeepEqual(got, tc.want) {
+					t.Errorf("backend %d: FindAll(%q) after Add got %v, want %v", backend, tc.text, got, tc.want)
+				}
+			})
+		}
+	}
+}
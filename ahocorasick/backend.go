@@ -0,0 +1,242 @@
+package ahocorasick
+
+import "slices"
+
+// Backend selects how trie transitions are stored
+type Backend int
+
+const (
+	// ArrayOfArrays stores a full [256]int transition row per node, with missing
+	// edges pre-resolved through failure links. Every search step is a single
+	// table lookup, but each node costs 2 KiB regardless of how many edges it has.
+	ArrayOfArrays Backend = iota
+	// SparseMap stores only the real trie edges of each node in a map and follows
+	// failure links at search time. Memory grows with the number of edges instead
+	// of nodes × 256, at the cost of slower transitions.
+	SparseMap
+	// DoubleArray stores the trie edges of all nodes in shared arrays: the edge of a node on
+	// byte c sits in slot base+c, where each node's base is chosen so that the slots of
+	// different nodes interleave without colliding, and the slot records its owner so that
+	// a lookup can tell the node's edges from its neighbours'. Memory is a few 32-bit words
+	// per node and slot, less than SparseMap on a large dictionary (a small automaton still
+	// pays for one 256-slot span), and failure links are followed at search time as with
+	// SparseMap. A transition is a few reads from arrays small enough to stay in cache, which
+	// on a large dictionary makes the search faster than ArrayOfArrays. Construction looks for
+	// a free base whenever a node gains an edge its slot cannot hold, so it is slower than
+	// SparseMap's (see BenchmarkNewDictionary and BenchmarkFindAllDictionary).
+	DoubleArray
+)
+
+// numNodes returns the number of trie nodes, including the root
+func (ac *AhoCorasick) numNodes() int {
+	return len(ac.fail)
+}
+
+// addNode appends a new node one level below parent and returns it
+func (ac *AhoCorasick) addNode(parent int) int {
+	switch ac.backend {
+	case SparseMap:
+		ac.edges = append(ac.edges, nil)
+	case DoubleArray:
+		ac.base = append(ac.base, -1)
+	default:
+		ac.next = append(ac.next, [256]int{})
+	}
+	ac.fail = append(ac.fail, 0)
+	ac.out = append(ac.out, []int{})
+	ac.depth = append(ac.depth, ac.depth[parent]+1)
+	return ac.numNodes() - 1
+}
+
+// child returns the trie child of node on byte c, or 0 if there is no such edge.
+// Transitions pre-resolved through failure links are not trie edges: they never lead one level deeper.
+func (ac *AhoCorasick) child(node int, c byte) int {
+	switch ac.backend {
+	case SparseMap:
+		return ac.edges[node][c]
+	case DoubleArray:
+		return ac.childDouble(node, c)
+	default:
+		nx := ac.next[node][c]
+		if nx != 0 && ac.depth[nx] == ac.depth[node]+1 {
+			return nx
+		}
+		return 0
+	}
+}
+
+// setChild adds the trie edge node -c-> nx
+func (ac *AhoCorasick) setChild(node int, c byte, nx int) {
+	switch ac.backend {
+	case SparseMap:
+		if ac.edges[node] == nil {
+			ac.edges[node] = make(map[byte]int)
+		}
+		ac.edges[node][c] = nx
+	case DoubleArray:
+		ac.setChildDouble(node, c, nx)
+	default:
+		ac.next[node][c] = nx
+	}
+}
+
+// forEachChild calls fn for every trie edge of node, in increasing byte order
+func (ac *AhoCorasick) forEachChild(node int, fn func(c byte, nx int)) {
+	switch ac.backend {
+	case SparseMap:
+		labels := make([]byte, 0, len(ac.edges[node]))
+		for c := range ac.edges[node] {
+			labels = append(labels, c)
+		}
+		slices.Sort(labels)
+		for _, c := range labels {
+			fn(c, ac.edges[node][c])
+		}
+	case DoubleArray:
+		if b := int(ac.base[node]); b >= 0 {
+			for c := 0; c < 256 && b+c < len(ac.check); c++ {
+				if int(ac.check[b+c]) == node+1 {
+					fn(byte(c), int(ac.target[b+c]))
+				}
+			}
+		}
+	default:
+		for c := 0; c < 256; c++ {
+			if nx := ac.child(node, byte(c)); nx != 0 {
+				fn(byte(c), nx)
+			}
+		}
+	}
+}
+
+// step returns the automaton state reached from node on byte c,
+// following failure links where the trie has no edge
+func (ac *AhoCorasick) step(node int, c byte) int {
+	switch {
+	case ac.next != nil:
+		return ac.next[node][c]
+	case ac.base != nil:
+		return ac.stepDouble(node, c)
+	}
+	return ac.stepSparse(node, c)
+}
+
+// stepSparse is step for backends that do not pre-resolve failure transitions
+func (ac *AhoCorasick) stepSparse(node int, c byte) int {
+	for {
+		if nx, ok := ac.edges[node][c]; ok {
+			return nx
+		}
+		if node == 0 {
+			return 0
+		}
+		node = ac.fail[node]
+	}
+}
+
+// stepDouble is stepSparse for the DoubleArray backend
+func (ac *AhoCorasick) stepDouble(node int, c byte) int {
+	for {
+		if nx := ac.childDouble(node, c); nx != 0 {
+			return nx
+		}
+		if node == 0 {
+			return 0
+		}
+		node = ac.fail[node]
+	}
+}
+
+// childDouble is child for the DoubleArray backend
+func (ac *AhoCorasick) childDouble(node int, c byte) int {
+	b := int(ac.base[node])
+	if t := b + int(c); b >= 0 && t < len(ac.check) && int(ac.check[t]) == node+1 {
+		return int(ac.target[t])
+	}
+	return 0
+}
+
+// setChildDouble is setChild for the DoubleArray backend. If the slot for c is taken by
+// another node, the node's edges move to a base where all of them and c fit. Only the
+// node's own slots move: its children are found through their own bases, which stay put.
+func (ac *AhoCorasick) setChildDouble(node int, c byte, nx int) {
+	b := int(ac.base[node])
+	if b < 0 || !ac.slotFree(b+int(c), node) {
+		var labels []byte
+		ac.forEachChild(node, func(l byte, _ int) {
+			labels = append(labels, l)
+		})
+		nb := ac.findBase(append(labels, c))
+		for _, l := range labels {
+			from := b + int(l)
+			ac.useSlot(nb+int(l), node, int(ac.target[from]))
+			ac.releaseSlot(from)
+		}
+		ac.base[node] = int32(nb)
+		b = nb
+	}
+	ac.useSlot(b+int(c), node, nx)
+}
+
+// slotFree reports whether slot t is unused or already belongs to node
+func (ac *AhoCorasick) slotFree(t, node int) bool {
+	return t >= len(ac.check) || ac.check[t] == 0 || int(ac.check[t]) == node+1
+}
+
+// findBase returns a base at which the slots of all labels are free. It tries to place the
+// smallest label in each free slot in turn, so only free slots are visited, and falls back
+// to the end of the arrays.
+func (ac *AhoCorasick) findBase(labels []byte) int {
+	lo := int(slices.Min(labels))
+	for t := ac.freeHead - 1; t >= 0; t = int(ac.freeNext[t]) - 1 {
+		b := t - lo
+		if b < 0 {
+			continue
+		}
+		fits := true
+		for _, l := range labels {
+			if !ac.slotFree(b+int(l), -1) {
+				fits = false
+				break
+			}
+		}
+		if fits {
+			return b
+		}
+	}
+	return max(len(ac.check)-lo, 0)
+}
+
+// useSlot makes slot t the edge of node leading to nx, growing the arrays as needed
+func (ac *AhoCorasick) useSlot(t, node, nx int) {
+	for len(ac.check) <= t {
+		ac.check = append(ac.check, 0)
+		ac.target = append(ac.target, 0)
+		ac.freeNext = append(ac.freeNext, 0)
+		ac.freePrev = append(ac.freePrev, 0)
+		ac.releaseSlot(len(ac.check) - 1)
+	}
+	if ac.check[t] == 0 {
+		// Unlink t from the free list
+		prev, next := ac.freePrev[t], ac.freeNext[t]
+		if prev > 0 {
+			ac.freeNext[prev-1] = next
+		} else {
+			ac.freeHead = int(next)
+		}
+		if next > 0 {
+			ac.freePrev[next-1] = prev
+		}
+	}
+	ac.check[t], ac.target[t] = int32(node+1), int32(nx)
+}
+
+// releaseSlot marks slot t free and puts it at the head of the free list
+func (ac *AhoCorasick) releaseSlot(t int) {
+	ac.check[t], ac.target[t] = 0, 0
+	ac.freePrev[t], ac.freeNext[t] = 0, int32(ac.freeHead)
+	if ac.freeHead > 0 {
+		ac.freePrev[ac.freeHead-1] = int32(t + 1)
+	}
+	ac.freeHead = t + 1
+}
@@ -0,0 +1,52 @@
+package ahocorasick
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// FindAllBatch returns FindAll(texts[i]) for every text, in the order of texts, searching the
+// texts on up to workers goroutines. Each text is searched by a single goroutine, so the
+// matches of every text keep the order documented on FindAll. The automaton is read-only
+// during a search, so this is safe; it must not be modified (Add, Remove, Rebuild) meanwhile.
+// With workers <= 1, or a single text, the texts are searched on the calling goroutine.
+func (ac *AhoCorasick) FindAllBatch(texts []string, workers int) [][]ACMatch {
+	return ac._findAllBatch(len(texts), workers, func(i int) []ACMatch {
+		return ac.FindAll(texts[i])
+	})
+}
+
+// FindAllBatchBytes returns FindAllBytes(data[i]) for every byte slice, in the order of data,
+// searching them on up to workers goroutines
+func (ac *AhoCorasick) FindAllBatchBytes(data [][]byte, workers int) [][]ACMatch {
+	return ac._findAllBatch(len(data), workers, func(i int) []ACMatch {
+		return ac.FindAllBytes(data[i])
+	})
+}
+
+// _findAllBatch stores find(i) for every i below n. The workers take the next unsearched index
+// from a shared counter, so a few long texts do not leave the other workers idle.
+func (ac *AhoCorasick) _findAllBatch(n, workers int, find func(i int) []ACMatch) [][]ACMatch {
+	results := make([][]ACMatch, n)
+	workers = min(workers, n)
+	if workers <= 1 {
+		for i := range results {
+			results[i] = find(i)
+		}
+		return results
+	}
+
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := int(next.Add(1) - 1); i < n; i = int(next.Add(1) - 1) {
+				results[i] = find(i)
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
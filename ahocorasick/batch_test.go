@@ -0,0 +1,59 @@
+package ahocorasick
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAhoCorasickFindAllBatch(t *testing.T) {
+	texts := []string{
+		"ushers",
+		"",
+		"nothing to see",
+		strings.Repeat("his hers she ", 50),
+		"HERS",
+		"she",
+	}
+
+	tests := []struct {
+		name    string
+		opts    Options
+		workers int
+	}{
+		{name: "Sequential", workers: 1},
+		{name: "No workers", workers: 0},
+		{name: "Parallel", workers: 3},
+		{name: "More workers than texts", workers: 100},
+		{name: "Leftmost-longest", opts: Options{MatchKind: LeftmostLongest}, workers: 4},
+		{name: "Ignore case in rune mode", opts: Options{IgnoreCase: true, Runes: true}, workers: 2},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ac := NewWithOptions([]string{"he", "she", "his", "hers"}, tc.opts)
+			want := make([][]ACMatch, len(texts))
+			data := make([][]byte, len(texts))
+			for i, text := range texts {
+				want[i] = ac.FindAll(text)
+				data[i] = []byte(text)
+			}
+
+			if got := ac.FindAllBatch(texts, tc.workers); !reflect.DeepEqual(got, want) {
+				t.Errorf("FindAllBatch(%d workers) got %v, want %v", tc.workers, got, want)
+			}
+			if got := ac.FindAllBatchBytes(data, tc.workers); !reflect.DeepEqual(got, want) {
+				t.Errorf("FindAllBatchBytes(%d workers) got %v, want %v", tc.workers, got, want)
+			}
+		})
+	}
+}
+
+func TestAhoCorasickFindAllBatchEmpty(t *testing.T) {
+	ac := New([]string{"he"}, false)
+	for _, workers := range []int{1, 4} {
+		if got := ac.FindAllBatch(nil, workers); len(got) != 0 {
+			t.Errorf("FindAllBatch(nil, %d) got %v, want no results", workers, got)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package ahocorasick
+
+import "context"
+
+// FindAllContext is FindAll for searches that must be abandoned once ctx is done, e.g. over a
+// large in-memory buffer while serving a request. ctx is checked before the search starts and
+// then every 64 KiB of text, which keeps the overhead negligible while bounding the work done
+// after cancellation. On cancellation it returns nil and ctx.Err(). In rune mode the text is
+// folded as a whole before the search starts.
+func (ac *AhoCorasick) FindAllContext(ctx context.Context, text string) ([]ACMatch, error) {
+	return ac.FindAllContextBytes(ctx, []byte(text))
+}
+
+// FindAllContextBytes is FindAllBytes for searches that must be abandoned once ctx is done.
+// On cancellation it returns nil and ctx.Err().
+func (ac *AhoCorasick) FindAllContextBytes(ctx context.Context, data []byte) ([]ACMatch, error) {
+	var matches []ACMatch
+	canceled := func() bool { return ctx.Err() != nil }
+	if canceled() {
+		return nil, ctx.Err()
+	}
+	ac.findFunc(data, ac.kind, nil, ac.runes, func(m ACMatch) bool {
+		matches = append(matches, m)
+		return true
+	}, canceled)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
@@ -0,0 +1,61 @@
+package ahocorasick
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAhoCorasickFindAllContext(t *testing.T) {
+	text := strings.Repeat("she sells sea shells ", 10000)
+	kinds := []struct {
+		name string
+		opts Options
+	}{
+		{"Standard", Options{}},
+		{"LeftmostLongest", Options{MatchKind: LeftmostLongest}},
+		{"Runes", Options{IgnoreCase: true, Runes: true}},
+	}
+
+	for _, k := range kinds {
+		t.Run(k.name, func(t *testing.T) {
+			ac := NewWithOptions([]string{"she", "shells", "sea"}, k.opts)
+
+			got, err := ac.FindAllContext(context.Background(), text)
+			if err != nil {
+				t.Fatalf("FindAllContext() error: %v", err)
+			}
+			if want := ac.FindAll(text); !reflect.DeepEqual(got, want) {
+				t.Errorf("FindAllContext() returned %d matches, FindAll %d", len(got), len(want))
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			got, err = ac.FindAllContextBytes(ctx, []byte(text))
+			if !errors.Is(err, context.Canceled) || got != nil {
+				t.Errorf("FindAllContextBytes() on a canceled context = %d matches, %v; want nil, %v", len(got), err, context.Canceled)
+			}
+		})
+	}
+}
+
+// TestAhoCorasickStopMidSearch checks both scan loops poll stop at least every stopCheckInterval bytes
+func TestAhoCorasickStopMidSearch(t *testing.T) {
+	text := []byte(strings.Repeat("x", 4*stopCheckInterval))
+	for _, kind := range []MatchKind{Standard, LeftmostFirst} {
+		ac := NewWithOptions([]string{"x"}, Options{MatchKind: kind})
+		seen, stopped := 0, false
+		ac.findFunc(text, kind, nil, false, func(ACMatch) bool {
+			seen++
+			if seen == 10 {
+				stopped = true // the scan should end at the next poll
+			}
+			return true
+		}, func() bool { return stopped })
+		if seen < 10 || seen > stopCheckInterval {
+			t.Errorf("MatchKind %v: scan reported %d matches, want it to stop within %d bytes", kind, seen, stopCheckInterval)
+		}
+	}
+}
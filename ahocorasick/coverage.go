@@ -0,0 +1,38 @@
+package ahocorasick
+
+import "unicode/utf8"
+
+// Coverage returns the fraction of text covered by at least one match, between 0 and 1.
+// Every match counts, overlapping ones included, whatever the automaton's MatchKind: the
+// covered positions are the union of the match ranges, so a position inside several
+// matches is counted once. An empty text has a coverage of 0. In rune mode the fraction is
+// of runes rather than bytes.
+func (ac *AhoCorasick) Coverage(text string) float64 {
+	return ac._coverage([]byte(text))
+}
+
+// CoverageBytes returns the fraction of the byte slice covered by at least one match
+func (ac *AhoCorasick) CoverageBytes(data []byte) float64 {
+	return ac._coverage(data)
+}
+
+func (ac *AhoCorasick) _coverage(data []byte) float64 {
+	n := len(data)
+	if ac.runes {
+		n = utf8.RuneCount(data)
+	}
+	if n == 0 {
+		return 0
+	}
+
+	covered := 0
+	last := 0 // end of the text merged so far
+	for _, m := range sortMatches(ac._findAll(data, Standard, nil)) {
+		// Count only the part of m past the merged intervals
+		if end := m.End + 1; end > last {
+			covered += end - max(m.Start, last)
+			last = end
+		}
+	}
+	return float64(covered) / float64(n)
+}
@@ -0,0 +1,132 @@
+package ahocorasick
+
+import (
+	"math/rand/v2"
+	"strings"
+	"testing"
+)
+
+func TestAhoCorasickCoverage(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		text     string
+		opts     Options
+		want     float64
+	}{
+		{
+			name:     "Disjoint matches",
+			patterns: []string{"cat", "dog"},
+			text:     "cat and dog!",
+			want:     6.0 / 12,
+		},
+		{
+			name:     "Overlapping matches are merged",
+			patterns: []string{"he", "she", "hers"},
+			text:     "ushers",
+			want:     5.0 / 6,
+		},
+		{
+			name:     "Nested match counted once",
+			patterns: []string{"abcd", "bc"},
+			text:     "abcdxx",
+			want:     4.0 / 6,
+		},
+		{
+			name:     "Overlaps count under leftmost kinds too",
+			patterns: []string{"abc", "cde"},
+			text:     "abcdef",
+			opts:     Options{MatchKind: LeftmostLongest},
+			want:     5.0 / 6,
+		},
+		{
+			name:     "Repeated overlapping keyword",
+			patterns: []string{"aa"},
+			text:     "aaaab",
+			want:     4.0 / 5,
+		},
+		{
+			name:     "Fully covered",
+			patterns: []string{"ab", "ba"},
+			text:     "abab",
+			want:     1,
+		},
+		{
+			name:     "No match",
+			patterns: []string{"xyz"},
+			text:     "hello",
+			want:     0,
+		},
+		{
+			name:     "Empty text",
+			patterns: []string{"xyz"},
+			text:     "",
+			want:     0,
+		},
+		{
+			name:     "Ignore case",
+			patterns: []string{"go"},
+			text:     "GO go Go",
+			opts:     Options{IgnoreCase: true},
+			want:     6.0 / 8,
+		},
+		{
+			name:     "Rune mode counts runes",
+			patterns: []string{"世界"},
+			text:     "こんにちは世界",
+			opts:     Options{Runes: true},
+			want:     2.0 / 7,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ac := NewWithOptions(tc.patterns, tc.opts)
+			if got := ac.Coverage(tc.text); got != tc.want {
+				t.Errorf("Coverage(%q) = %v; want %v", tc.text, got, tc.want)
+			}
+			if got := ac.CoverageBytes([]byte(tc.text)); got != tc.want {
+				t.Errorf("CoverageBytes(%q) = %v; want %v", tc.text, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestAhoCorasickCoverageRandom checks Coverage against marking every matched position
+func TestAhoCorasickCoverageRandom(t *testing.T) {
+	rng := rand.New(rand.NewPCG(11, 12))
+	randString := func(n int) string {
+		var b strings.Builder
+		for i := 0; i < n; i++ {
+			b.WriteByte("abc"[rng.IntN(3)])
+		}
+		return b.String()
+	}
+
+	for i := 0; i < 200; i++ {
+		patterns := make([]string, 1+rng.IntN(5))
+		for j := range patterns {
+			patterns[j] = randString(1 + rng.IntN(4))
+		}
+		text := randString(1 + rng.IntN(40))
+		ac := New(patterns, false)
+
+		marked := make([]bool, len(text))
+		for _, m := range ac.FindAll(text) {
+			for p := m.Start; p <= m.End; p++ {
+				marked[p] = true
+			}
+		}
+		covered := 0
+		for _, ok := range marked {
+			if ok {
+				covered++
+			}
+		}
+
+		want := float64(covered) / float64(len(text))
+		if got := ac.Coverage(text); got != want {
+			t.Fatalf("Coverage(%q) with %q = %v; want %v", text, patterns, got, want)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package ahocorasick
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+// WriteDOT writes the automaton to w in Graphviz DOT format, for debugging and teaching:
+// render it with e.g. dot -Tsvg. Every trie node is drawn with its number and the patterns
+// in its out list, i.e. those reported when the search reaches it; nodes where some pattern
+// ends are double circles. Trie edges are solid and labeled with their byte, failure links
+// are dashed. Failure links to the root are omitted to keep the graph readable. Under
+// ignoreCase the edges carry the folded keywords' bytes.
+func (ac *AhoCorasick) WriteDOT(w io.Writer) error {
+	var buf bytes.Buffer
+	buf.WriteString("digraph ahocorasick {\n\trankdir=LR;\n\tnode [shape=circle];\n")
+	for node := 0; node < ac.numNodes(); node++ {
+		label := strconv.Itoa(node)
+		shape := ""
+		if len(ac.out[node]) > 0 {
+			shape = ", shape=doublecircle"
+			for _, patIdx := range ac.out[node] {
+				label += fmt.Sprintf("\n%d: %s", patIdx, dotText(ac.patterns[patIdx]))
+			}
+		}
+		fmt.Fprintf(&buf, "\t%d [label=%s%s];\n", node, strconv.Quote(label), shape)
+	}
+	for node := 0; node < ac.numNodes(); node++ {
+		ac.forEachChild(node, func(c byte, nx int) {
+			fmt.Fprintf(&buf, "\t%d -> %d [label=%s];\n", node, nx, strconv.Quote(dotText(string([]byte{c}))))
+		})
+		if node != 0 && ac.fail[node] != 0 {
+			fmt.Fprintf(&buf, "\t%d -> %d [style=dashed, color=gray];\n", node, ac.fail[node])
+		}
+	}
+	buf.WriteString("}\n")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// dotText returns s for display in a label: printable characters as is and any other byte,
+// including those of invalid UTF-8, spelled out as \xNN. Quoting the result with
+// strconv.Quote then only adds the \\, \" and \n escapes, which DOT reads the same way.
+func dotText(s string) string {
+	var b []byte
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if (r == utf8.RuneError && size == 1) || !unicode.IsPrint(r) {
+			for _, c := range []byte(s[i : i+size]) {
+				b = fmt.Appendf(b, "\\x%02x", c)
+			}
+		} else {
+			b = append(b, s[i:i+size]...)
+		}
+		i += size
+	}
+	return string(b)
+}
@@ -0,0 +1,68 @@
+package ahocorasick
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAhoCorasickWriteDOT(t *testing.T) {
+	ac := New([]string{"he", "she"}, false)
+	var sb strings.Builder
+	if err := ac.WriteDOT(&sb); err != nil {
+		t.Fatalf("WriteDOT() error: %v", err)
+	}
+	want := `digraph ahocorasick {
+	rankdir=LR;
+	node [shape=circle];
+	0 [label="0"];
+	1 [label="1"];
+	2 [label="2\n0: he", shape=doublecircle];
+	3 [label="3"];
+	4 [label="4"];
+	5 [label="5\n1: she\n0: he", shape=doublecircle];
+	0 -> 1 [label="h"];
+	0 -> 3 [label="s"];
+	1 -> 2 [label="e"];
+	3 -> 4 [label="h"];
+	4 -> 5 [label="e"];
+	4 -> 1 [style=dashed, color=gray];
+	5 -> 2 [style=dashed, color=gray];
+}
+`
+	if got := sb.String(); got != want {
+		t.Errorf("WriteDOT() wrote\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestAhoCorasickWriteDOTLabels(t *testing.T) {
+	ac := New([]string{`a"b\`, "é\x00"}, false)
+	var sb strings.Builder
+	if err := ac.WriteDOT(&sb); err != nil {
+		t.Fatalf("WriteDOT() error: %v", err)
+	}
+	got := sb.String()
+	for _, want := range []string{
+		`[label="\""]`,       // quote edge
+		`[label="\\"]`,       // backslash edge
+		`[label="\\xc3"]`,    // first byte of é
+		`0: a\"b\\"`,         // pattern with quote and backslash
+		`1: é\\x00", shape=`, // printable rune kept, NUL spelled out
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("WriteDOT() output lacks %s:\n%s", want, got)
+		}
+	}
+}
+
+// errWriter fails every write with err
+type errWriter struct{ err error }
+
+func (w errWriter) Write([]byte) (int, error) { return 0, w.err }
+
+func TestAhoCorasickWriteDOTError(t *testing.T) {
+	wantErr := errors.New("write failed")
+	if err := New([]string{"x"}, false).WriteDOT(errWriter{wantErr}); !errors.Is(err, wantErr) {
+		t.Errorf("WriteDOT() error = %v, want %v", err, wantErr)
+	}
+}
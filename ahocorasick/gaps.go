@@ -0,0 +1,39 @@
+package ahocorasick
+
+import "unicode/utf8"
+
+// FindGaps returns the maximal half-open ranges [start, end) of text not covered by any match,
+// the complement of what FindAllNonOverlapping reports. Like it, matches are chosen with
+// leftmost-longest semantics whatever the automaton's MatchKind, so the ranges and the matches
+// together tile the text exactly. A text without matches is one gap; an empty text has none.
+// In rune mode the ranges are rune indices.
+func (ac *AhoCorasick) FindGaps(text string) [][2]int {
+	return ac._findGaps([]byte(text))
+}
+
+// FindGapsBytes returns the maximal half-open ranges of the byte slice not covered by any match
+func (ac *AhoCorasick) FindGapsBytes(data []byte) [][2]int {
+	return ac._findGaps(data)
+}
+
+func (ac *AhoCorasick) _findGaps(data []byte) [][2]int {
+	n := len(data)
+	if ac.runes {
+		n = utf8.RuneCount(data)
+	}
+
+	var gaps [][2]int
+	last := 0 // end of the text covered so far
+	ac._findAllFunc(data, LeftmostLongest, nil, func(m ACMatch) bool {
+		if m.Start > last {
+			gaps = append(gaps, [2]int{last, m.Start})
+		}
+		// Under Normalize two matches may cover the same rune
+		last = max(last, m.End+1)
+		return true
+	})
+	if last < n {
+		gaps = append(gaps, [2]int{last, n})
+	}
+	return gaps
+}
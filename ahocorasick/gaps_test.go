@@ -0,0 +1,104 @@
+package ahocorasick
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAhoCorasickFindGaps(t *testing.T) {
+	type testCase struct {
+		name     string
+		patterns []string
+		text     string
+		opts     Options
+		want     [][2]int
+	}
+	tests := []testCase{
+		{
+			name:     "Gaps around and between matches",
+			patterns: []string{"cat", "dog"},
+			text:     "a cat and a dog!",
+			want:     [][2]int{{0, 2}, {5, 12}, {15, 16}},
+		},
+		{
+			name:     "Adjacent matches leave no gap",
+			patterns: []string{"ab", "cd"},
+			text:     "abcdab",
+			want:     nil,
+		},
+		{
+			name:     "Overlapping patterns use leftmost-longest",
+			patterns: []string{"he", "she", "hers"},
+			text:     "ushers",
+			want:     [][2]int{{0, 1}, {4, 6}},
+		},
+		{
+			name:     "Leftmost-longest even under LeftmostFirst",
+			patterns: []string{"ab", "abcd"},
+			text:     "abcdx",
+			opts:     Options{MatchKind: LeftmostFirst},
+			want:     [][2]int{{4, 5}},
+		},
+		{
+			name:     "No match",
+			patterns: []string{"xyz"},
+			text:     "hello",
+			want:     [][2]int{{0, 5}},
+		},
+		{
+			name:     "Empty text",
+			patterns: []string{"xyz"},
+			text:     "",
+			want:     nil,
+		},
+		{
+			name:     "Ignore case",
+			patterns: []string{"go"},
+			text:     "GO go Go",
+			opts:     Options{IgnoreCase: true},
+			want:     [][2]int{{2, 3}, {5, 6}},
+		},
+		{
+			name:     "Rune indices",
+			patterns: []string{"世界"},
+			text:     "こんにちは世界です",
+			opts:     Options{Runes: true},
+			want:     [][2]int{{0, 5}, {7, 9}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ac := NewWithOptions(tc.patterns, tc.opts)
+			if got := ac.FindGaps(tc.text); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("FindGaps(%q) = %v; want %v", tc.text, got, tc.want)
+			}
+			if got := ac.FindGapsBytes([]byte(tc.text)); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("FindGapsBytes(%q) = %v; want %v", tc.text, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestAhoCorasickFindGapsTiling checks that the gaps and the non-overlapping matches
+// together cover the text exactly once
+func TestAhoCorasickFindGapsTiling(t *testing.T) {
+	ac := New([]string{"a", "ab", "bab", "bc", "ca"}, false)
+	text := "xabcabababcaxxbcab"
+	covered := make([]int, len(text))
+	for _, g := range ac.FindGaps(text) {
+		for i := g[0]; i < g[1]; i++ {
+			covered[i]++
+		}
+	}
+	for _, m := range ac.FindAllNonOverlapping(text) {
+		for i := m.Start; i <= m.End; i++ {
+			covered[i]++
+		}
+	}
+	for i, c := range covered {
+		if c != 1 {
+			t.Errorf("position %d covered %d times; want once", i, c)
+		}
+	}
+}
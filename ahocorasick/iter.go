@@ -0,0 +1,22 @@
+package ahocorasick
+
+import "iter"
+
+// All returns an iterator over the pattern matches in text, in the order FindAll would return them.
+// The automaton walks the text lazily as the loop asks for matches, so breaking out of the loop
+// stops the search and no slice of matches is built.
+func (ac *AhoCorasick) All(text string) iter.Seq[ACMatch] {
+	// Converting inside the iterator keeps the string-to-bytes conversion from copying;
+	// rune mode still builds a folded copy of the text, as every search does there
+	return func(yield func(ACMatch) bool) {
+		ac._findAllFunc([]byte(text), ac.kind, nil, yield)
+	}
+}
+
+// AllBytes returns an iterator over the pattern matches in the byte slice, in the order
+// FindAllBytes would return them. Breaking out of the loop stops the search.
+func (ac *AhoCorasick) AllBytes(data []byte) iter.Seq[ACMatch] {
+	return func(yield func(ACMatch) bool) {
+		ac._findAllFunc(data, ac.kind, nil, yield)
+	}
+}
@@ -0,0 +1,53 @@
+package ahocorasick
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestAhoCorasickAll(t *testing.T) {
+	type testCase struct {
+		name     string
+		patterns []string
+		text     string
+		opts     Options
+	}
+	tests := []testCase{
+		{"Overlapping matches", []string{"he", "she", "his", "hers"}, "ushers", Options{}},
+		{"No match", []string{"cat", "dog"}, "mouse", Options{}},
+		{"Leftmost-longest", []string{"ab", "abcd", "bc"}, "abcdabc", Options{MatchKind: LeftmostLongest}},
+		{"Ignore case", []string{"go"}, "GO gopher Go", Options{IgnoreCase: true}},
+		{"Rune indices", []string{"世界", "界"}, "こんにちは世界", Options{Runes: true}},
+		{"Empty pattern", []string{""}, "abc", Options{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ac := NewWithOptions(tc.patterns, tc.opts)
+			want := ac.FindAll(tc.text)
+
+			if got := slices.Collect(ac.All(tc.text)); !reflect.DeepEqual(got, want) {
+				t.Errorf("All(%q) = %v; want %v", tc.text, got, want)
+			}
+			if got := slices.Collect(ac.AllBytes([]byte(tc.text))); !reflect.DeepEqual(got, want) {
+				t.Errorf("AllBytes(%q) = %v; want %v", tc.text, got, want)
+			}
+		})
+	}
+}
+
+func TestAhoCorasickAllBreak(t *testing.T) {
+	ac := New([]string{"ab"}, false)
+	var got []ACMatch
+	for m := range ac.All("ababababab") {
+		got = append(got, m)
+		if len(got) == 2 {
+			break
+		}
+	}
+	want := []ACMatch{{PatternIndex: 0, Start: 0, End: 1}, {PatternIndex: 0, Start: 2, End: 3}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("All with break = %v; want %v", got, want)
+	}
+}
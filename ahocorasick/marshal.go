@@ -0,0 +1,380 @@
+package ahocorasick
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/notJoon/searcher/byteclass"
+)
+
+// binaryMagic and binaryVersion start every serialized automaton.
+// Version 1 stored the folded keywords instead of the patterns as given;
+// it is still accepted, with the keywords standing in for the patterns.
+// Version 3 added the Wildcards table, which follows the backend when flagWildcards is set.
+const (
+	binaryMagic   = "AHOC"
+	binaryVersion = 3
+)
+
+// Bits of the serialized flags field
+const (
+	flagIgnoreCase = 1 << iota
+	flagRunes
+	flagPrefilter
+	flagWildcards
+)
+
+// ErrNormalize is returned by MarshalBinary for automata built with Options.Normalize,
+// since the function cannot be serialized
+var ErrNormalize = errors.New("ahocorasick: cannot serialize an automaton with a Normalize function")
+
+// ErrTokenBoundary is returned by MarshalBinary for automata built with Options.TokenBoundary,
+// since the function cannot be serialized
+var ErrTokenBoundary = errors.New("ahocorasick: cannot serialize an automaton with a TokenBoundary function")
+
+// ErrInvalidBinary is returned by UnmarshalBinary for data that is not a valid serialized automaton
+var ErrInvalidBinary = errors.New("ahocorasick: invalid serialized automaton")
+
+// MarshalBinary serializes the compiled automaton: patterns, options, trie edges,
+// failure links and out lists. Loading it with UnmarshalBinary skips trie and
+// failure link construction entirely. An automaton with a Normalize or TokenBoundary
+// function cannot be serialized and yields ErrNormalize or ErrTokenBoundary.
+func (ac *AhoCorasick) MarshalBinary() ([]byte, error) {
+	if ac.normalize != nil {
+		return nil, ErrNormalize
+	}
+	if ac.tokenBoundary != nil {
+		return nil, ErrTokenBoundary
+	}
+	buf := []byte(binaryMagic)
+	buf = append(buf, binaryVersion)
+
+	var flags uint64
+	if ac.ignoreCase {
+		flags |= flagIgnoreCase
+	}
+	if ac.runes {
+		flags |= flagRunes
+	}
+	if ac.prefilter {
+		flags |= flagPrefilter
+	}
+	if len(ac.wildcards) > 0 {
+		flags |= flagWildcards
+	}
+	buf = binary.AppendUvarint(buf, flags)
+	buf = binary.AppendUvarint(buf, uint64(ac.kind))
+	buf = binary.AppendUvarint(buf, uint64(ac.backend))
+
+	if len(ac.wildcards) > 0 {
+		buf = binary.AppendUvarint(buf, uint64(len(ac.wildcards)))
+		for _, c := range slices.Sorted(maps.Keys(ac.wildcards)) {
+			buf = append(buf, c)
+			for _, w := range ac.wildcards[c] {
+				buf = binary.LittleEndian.AppendUint64(buf, w)
+			}
+		}
+	}
+
+	buf = binary.AppendUvarint(buf, uint64(len(ac.patterns)))
+	for _, p := range ac.patterns {
+		buf = binary.AppendUvarint(buf, uint64(len(p)))
+		buf = append(buf, p...)
+	}
+
+	buf = binary.AppendUvarint(buf, uint64(ac.numNodes()))
+	for node := 0; node < ac.numNodes(); node++ {
+		buf = binary.AppendUvarint(buf, uint64(ac.fail[node]))
+
+		var edges []byte
+		n := 0
+		ac.forEachChild(node, func(c byte, nx int) {
+			edges = append(edges, c)
+			edges = binary.AppendUvarint(edges, uint64(nx))
+			n++
+		})
+		buf = binary.AppendUvarint(buf, uint64(n))
+		buf = append(buf, edges...)
+
+		buf = binary.AppendUvarint(buf, uint64(len(ac.out[node])))
+		for _, patIdx := range ac.out[node] {
+			buf = binary.AppendUvarint(buf, uint64(patIdx))
+		}
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary restores an automaton serialized by MarshalBinary, replacing the receiver's contents.
+// The header and all node and pattern references are validated, and every pattern in an out list
+// must spell the end of its node's path; malformed data yields an error wrapping ErrInvalidBinary
+// and leaves the receiver unchanged.
+func (ac *AhoCorasick) UnmarshalBinary(data []byte) error {
+	if len(data) < len(binaryMagic)+1 || string(data[:len(binaryMagic)]) != binaryMagic {
+		return fmt.Errorf("%w: bad magic header", ErrInvalidBinary)
+	}
+	version := data[len(binaryMagic)]
+	if version < 1 || version > binaryVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidBinary, version)
+	}
+	d := decoder{data: data[len(binaryMagic)+1:]}
+
+	flags := d.uvarint()
+	kind := MatchKind(d.uvarint())
+	backend := Backend(d.uvarint())
+	if d.err == nil && (flags&^(flagIgnoreCase|flagRunes|flagPrefilter|flagWildcards) != 0 ||
+		kind > LeftmostFirst || backend > DoubleArray) {
+		return fmt.Errorf("%w: unknown option value", ErrInvalidBinary)
+	}
+
+	res := &AhoCorasick{
+		ignoreCase: flags&flagIgnoreCase != 0,
+		runes:      flags&flagRunes != 0,
+		prefilter:  flags&flagPrefilter != 0,
+		kind:       kind,
+		backend:    backend,
+	}
+	if flags&flagWildcards != 0 {
+		res.wildcards = make(map[byte]byteclass.Set)
+		for n := d.count(); n > 0 && d.err == nil; n-- {
+			c := d.byte()
+			var set byteclass.Set
+			for i := range set {
+				set[i] = d.uint64()
+			}
+			res.wildcards[c] = set
+		}
+	}
+
+	opts := Options{IgnoreCase: res.ignoreCase, Runes: res.runes}
+	numKeywords := d.count()
+	for i := 0; i < numKeywords && d.err == nil; i++ {
+		p := d.bytes(d.count())
+		res.patterns = append(res.patterns, string(p))
+		if version == 1 {
+			res.keywords = append(res.keywords, p)
+		} else {
+			res.keywords = append(res.keywords, foldPattern(string(p), opts))
+		}
+	}
+
+	// Every node takes at least its failure link and two counts, and every node but the
+	// root an edge into it, so the data bounds the node count before anything is allocated
+	numNodes := d.count()
+	if d.err == nil && numNodes == 0 {
+		d.err = errors.New("automaton has no root")
+	}
+	if d.err == nil && 3*numNodes+2*(numNodes-1) > len(d.data) {
+		d.err = errors.New("node count exceeds data")
+	}
+	if d.err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBinary, d.err)
+	}
+	res.fail = make([]int, numNodes)
+	res.out = make([][]int, numNodes)
+	res.depth = make([]int, numNodes)
+
+	children := make([][]edge, numNodes)
+	hasParent := make([]bool, numNodes)
+	for node := 0; node < numNodes && d.err == nil; node++ {
+		res.fail[node] = d.index(numNodes)
+		for n := d.count(); n > 0 && d.err == nil; n-- {
+			c := d.byte()
+			nx := d.index(numNodes)
+			if d.err == nil && (nx == 0 || hasParent[nx]) {
+				d.err = errors.New("trie edges do not form a tree")
+			}
+			if k := len(children[node]); d.err == nil && k > 0 && children[node][k-1].c >= c {
+				d.err = errors.New("edge labels not in increasing order")
+			}
+			hasParent[nx] = true
+			children[node] = append(children[node], edge{c, nx})
+		}
+		res.out[node] = []int{}
+		for n := d.count(); n > 0 && d.err == nil; n-- {
+			res.out[node] = append(res.out[node], d.index(numKeywords))
+		}
+	}
+	if d.err == nil && len(d.data) != 0 {
+		d.err = errors.New("trailing data")
+	}
+	if d.err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidBinary, d.err)
+	}
+
+	// The backend's transition storage, 2 KiB per node for ArrayOfArrays, is only
+	// allocated once the whole stream has parsed
+	switch backend {
+	case SparseMap:
+		res.edges = make([]map[byte]int, numNodes)
+	case DoubleArray:
+		res.base = make([]int32, numNodes)
+		for i := range res.base {
+			res.base[i] = -1
+		}
+	default:
+		res.next = make([][256]int, numNodes)
+	}
+
+	// Rebuild the trie in BFS order, recomputing depths and, for ArrayOfArrays, the transitions
+	// resolved through failure links. Failure links point to shallower nodes, whose rows are
+	// therefore complete by the time they are copied from.
+	parent := make([]edge, numNodes) // the trie edge into each node, from edge.nx
+	queue := []int{0}
+	visited := 1
+	for len(queue) > 0 {
+		f := queue[0]
+		queue = queue[1:]
+		if f != 0 && res.depth[res.fail[f]] >= res.depth[f] {
+			return fmt.Errorf("%w: failure link does not point to a shallower node", ErrInvalidBinary)
+		}
+		for _, e := range children[f] {
+			res.depth[e.nx] = res.depth[f] + 1
+			parent[e.nx] = edge{e.c, f}
+			res.setChild(f, e.c, e.nx)
+			queue = append(queue, e.nx)
+			visited++
+		}
+		if res.next != nil && f != 0 {
+			for c := 0; c < 256; c++ {
+				if res.next[f][c] == 0 {
+					res.next[f][c] = res.next[res.fail[f]][c]
+				}
+			}
+		}
+	}
+	if visited != numNodes {
+		return fmt.Errorf("%w: unreachable trie nodes", ErrInvalidBinary)
+	}
+
+	// A match is reported as ending where its node is reached, and starts len(keyword)-1
+	// bytes earlier, so every pattern in an out list must spell the end of the node's path
+	sets := make(map[byte]byteclass.Set, len(res.wildcards))
+	for c, set := range res.wildcards {
+		var folded byteclass.Set
+		for _, b := range res.wildcardBytes(set) {
+			folded = folded.Add(b)
+		}
+		sets[c] = folded
+	}
+	for node, out := range res.out {
+		for _, idx := range out {
+			if !res.spellsPathEnd(res.keywords[idx], node, parent, sets) {
+				return fmt.Errorf("%w: pattern %d does not end at node %d", ErrInvalidBinary, idx, node)
+			}
+		}
+	}
+	res.buildLeavesRoot()
+	res.buildPatternLens()
+
+	*ac = *res
+	return nil
+}
+
+// spellsPathEnd reports whether keyword k spells the last len(k) trie edges leading to node,
+// following the edges recorded in parent back towards the root. A wildcard sentinel of k
+// matches the edges in its folded set in sets.
+func (ac *AhoCorasick) spellsPathEnd(k []byte, node int, parent []edge, sets map[byte]byteclass.Set) bool {
+	if len(k) == 0 || len(k) > ac.depth[node] {
+		return false
+	}
+	for i := len(k) - 1; i >= 0; i-- {
+		e := parent[node]
+		if set, ok := sets[k[i]]; ok {
+			if !set.Has(e.c) {
+				return false
+			}
+		} else if k[i] != e.c {
+			return false
+		}
+		node = e.nx
+	}
+	return true
+}
+
+// edge is a trie edge labelled c, read back by UnmarshalBinary
+type edge struct {
+	c  byte
+	nx int
+}
+
+// decoder reads the serialized automaton, remembering the first error
+type decoder struct {
+	data []byte
+	err  error
+}
+
+// uvarint reads an unsigned varint
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.err = errors.New("truncated or malformed varint")
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+// count reads a length that must not exceed the remaining data, which bounds allocations
+func (d *decoder) count() int {
+	v := d.uvarint()
+	if d.err == nil && v > uint64(len(d.data)) {
+		d.err = errors.New("length exceeds data")
+		return 0
+	}
+	return int(v)
+}
+
+// index reads a reference that must be below n
+func (d *decoder) index(n int) int {
+	v := d.uvarint()
+	if d.err == nil && v >= uint64(n) {
+		d.err = fmt.Errorf("index %d out of range [0, %d)", v, n)
+		return 0
+	}
+	return int(v)
+}
+
+// uint64 reads a little-endian 64-bit word
+func (d *decoder) uint64() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	if len(d.data) < 8 {
+		d.err = errors.New("truncated data")
+		return 0
+	}
+	v := binary.LittleEndian.Uint64(d.data)
+	d.data = d.data[8:]
+	return v
+}
+
+// byte reads a single byte
+func (d *decoder) byte() byte {
+	if d.err != nil {
+		return 0
+	}
+	if len(d.data) == 0 {
+		d.err = errors.New("truncated data")
+		return 0
+	}
+	c := d.data[0]
+	d.data = d.data[1:]
+	return c
+}
+
+// bytes reads n raw bytes into a new slice
+func (d *decoder) bytes(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	b := make([]byte, n)
+	copy(b, d.data)
+	d.data = d.data[n:]
+	return b
+}
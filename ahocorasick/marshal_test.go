@@ -0,0 +1,258 @@
+package ahocorasick
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/notJoon/searcher/byteclass"
+)
+
+func TestAhoCorasickMarshalBinary(t *testing.T) {
+	patterns := []string{"he", "She", "his", "hers", "s", "ushe"}
+	texts := []string{"ushers", "USHERS his hIs", "", "xyz", "shehishers"}
+
+	for _, backend := range []Backend{ArrayOfArrays, SparseMap, DoubleArray} {
+		for _, kind := range []MatchKind{Standard, LeftmostLongest, LeftmostFirst} {
+			for _, ignoreCase := range []bool{false, true} {
+				opts := Options{IgnoreCase: ignoreCase, MatchKind: kind, Backend: backend}
+				orig := NewWithOptions(patterns, opts)
+
+				data, err := orig.MarshalBinary()
+				if err != nil {
+					t.Fatalf("MarshalBinary(%+v) returned error: %v", opts, err)
+				}
+				var loaded AhoCorasick
+				if err := loaded.UnmarshalBinary(data); err != nil {
+					t.Fatalf("UnmarshalBinary(%+v) returned error: %v", opts, err)
+				}
+				if !loaded.Equal(orig) {
+					t.Errorf("%+v: loaded automaton is not Equal to the original", opts)
+				}
+
+				for _, text := range texts {
+					if got, want := loaded.FindAll(text), orig.FindAll(text); !reflect.DeepEqual(got, want) {
+						t.Errorf("%+v: loaded FindAll(%q) = %v; want %v", opts, text, got, want)
+					}
+				}
+
+				// The loaded automaton must stay fully functional, including incremental updates
+				orig.Add("rs")
+				loaded.Add("rs")
+				for _, text := range texts {
+					if got, want := loaded.FindAll(text), orig.FindAll(text); !reflect.DeepEqual(got, want) {
+						t.Errorf("%+v: loaded FindAll(%q) after Add = %v; want %v", opts, text, got, want)
+					}
+				}
+
+				// The patterns as given survive, so the case setting can still be switched
+				orig.Rebuild(!ignoreCase)
+				loaded.Rebuild(!ignoreCase)
+				for _, text := range texts {
+					if got, want := loaded.FindAll(text), orig.FindAll(text); !reflect.DeepEqual(got, want) {
+						t.Errorf("%+v: loaded FindAll(%q) after Rebuild = %v; want %v", opts, text, got, want)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestAhoCorasickMarshalBinaryNormalize(t *testing.T) {
+	ac := NewWithOptions([]string{"fi"}, Options{Normalize: func(r rune) string { return string(r) }})
+	if _, err := ac.MarshalBinary(); !errors.Is(err, ErrNormalize) {
+		t.Errorf("MarshalBinary error = %v; want %v", err, ErrNormalize)
+	}
+}
+
+func TestAhoCorasickMarshalBinaryTokenBoundary(t *testing.T) {
+	ac := NewWithOptions([]string{"go"}, Options{TokenBoundary: func(c byte) bool { return c == ' ' }})
+	if _, err := ac.MarshalBinary(); !errors.Is(err, ErrTokenBoundary) {
+		t.Errorf("MarshalBinary error = %v; want %v", err, ErrTokenBoundary)
+	}
+}
+
+func TestAhoCorasickMarshalBinaryWildcards(t *testing.T) {
+	opts := Options{Wildcards: map[byte]byteclass.Set{'#': byteclass.Range('0', '9'), '?': byteclass.Any()}}
+	orig := NewWithOptions([]string{"e#", "x?y", "n##"}, opts)
+	data, err := orig.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary returned error: %v", err)
+	}
+	var loaded AhoCorasick
+	if err := loaded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary returned error: %v", err)
+	}
+	if !loaded.Equal(orig) {
+		t.Errorf("loaded automaton is not Equal to the original")
+	}
+	if loaded.Equal(New([]string{"e#", "x?y", "n##"}, false)) {
+		t.Errorf("loaded automaton is Equal to one without wildcards")
+	}
+
+	const text = "e1 x-y n42 e#"
+	if got, want := loaded.FindAll(text), orig.FindAll(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("loaded FindAll(%q) = %v; want %v", text, got, want)
+	}
+	// Add walks the wildcard paths, so the table must have been restored
+	orig.Add("#e")
+	loaded.Add("#e")
+	if got, want := loaded.FindAll(text), orig.FindAll(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("loaded FindAll(%q) after Add = %v; want %v", text, got, want)
+	}
+
+	for i := len(binaryMagic) + 1; i < len(data); i++ {
+		if err := new(AhoCorasick).UnmarshalBinary(data[:i]); !errors.Is(err, ErrInvalidBinary) {
+			t.Fatalf("UnmarshalBinary of %d bytes: error = %v; want %v", i, err, ErrInvalidBinary)
+		}
+	}
+}
+
+func TestAhoCorasickUnmarshalBinaryVersion1(t *testing.T) {
+	ac := New([]string{"He", "SHE", "his"}, true)
+
+	// Version 1 wrote the folded keywords where later versions write the patterns as given
+	v1 := New([]string{"he", "she", "his"}, true)
+	data, err := v1.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary returned error: %v", err)
+	}
+	data[len(binaryMagic)] = 1
+
+	var loaded AhoCorasick
+	if err := loaded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary returned error: %v", err)
+	}
+	if got, want := loaded.FindAll("USHERS his"), ac.FindAll("USHERS his"); !reflect.DeepEqual(got, want) {
+		t.Errorf("loaded FindAll = %v; want %v", got, want)
+	}
+	// The keywords stand in for the lost patterns
+	if want := []string{"he", "she", "his"}; !reflect.DeepEqual(loaded.patterns, want) {
+		t.Errorf("loaded patterns = %q; want %q", loaded.patterns, want)
+	}
+}
+
+// trieStream serializes the trie of "abc" and "bc" by hand, with extra as the out list
+// of node "ab", where neither pattern ends
+func trieStream(extra ...int) []byte {
+	buf := []byte(binaryMagic)
+	buf = append(buf, binaryVersion)
+	buf = binary.AppendUvarint(buf, 0) // flags
+	buf = binary.AppendUvarint(buf, uint64(Standard))
+	buf = binary.AppendUvarint(buf, uint64(ArrayOfArrays))
+	buf = binary.AppendUvarint(buf, 2)
+	for _, p := range []string{"abc", "bc"} {
+		buf = binary.AppendUvarint(buf, uint64(len(p)))
+		buf = append(buf, p...)
+	}
+
+	// node: failure link, edges, out list
+	nodes := []struct {
+		fail  int
+		edges []edge
+		out   []int
+	}{
+		{0, []edge{{'a', 1}, {'b', 4}}, nil}, // root
+		{0, []edge{{'b', 2}}, nil},           // a
+		{4, []edge{{'c', 3}}, extra},         // ab
+		{5, nil, []int{0, 1}},                // abc
+		{0, []edge{{'c', 5}}, nil},           // b
+		{0, nil, []int{1}},                   // bc
+	}
+	buf = binary.AppendUvarint(buf, uint64(len(nodes)))
+	for _, n := range nodes {
+		buf = binary.AppendUvarint(buf, uint64(n.fail))
+		buf = binary.AppendUvarint(buf, uint64(len(n.edges)))
+		for _, e := range n.edges {
+			buf = append(buf, e.c)
+			buf = binary.AppendUvarint(buf, uint64(e.nx))
+		}
+		buf = binary.AppendUvarint(buf, uint64(len(n.out)))
+		for _, idx := range n.out {
+			buf = binary.AppendUvarint(buf, uint64(idx))
+		}
+	}
+	return buf
+}
+
+func TestAhoCorasickUnmarshalBinaryOutLists(t *testing.T) {
+	// Untampered, the hand-built stream loads and searches like New
+	ac := New(nil, false)
+	if err := ac.UnmarshalBinary(trieStream()); err != nil {
+		t.Fatalf("UnmarshalBinary returned error: %v", err)
+	}
+	want := New([]string{"abc", "bc"}, false).FindAll("xabcbc")
+	if got := ac.FindAll("xabcbc"); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindAll = %v; want %v", got, want)
+	}
+}
+
+func TestAhoCorasickUnmarshalBinaryInvalid(t *testing.T) {
+	valid, err := New([]string{"he", "she", "his"}, false).MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary returned error: %v", err)
+	}
+
+	withVersion := func(v byte) []byte {
+		b := append([]byte(nil), valid...)
+		b[len(binaryMagic)] = v
+		return b
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "Empty", data: nil},
+		{name: "Bad magic", data: append([]byte("ACHO"), valid[len(binaryMagic):]...)},
+		{name: "Unknown version", data: withVersion(binaryVersion + 1)},
+		{name: "Version 0", data: withVersion(0)},
+		{name: "Trailing data", data: append(append([]byte(nil), valid...), 0)},
+	}
+	unknownFlag := append([]byte(nil), valid...)
+	unknownFlag[len(binaryMagic)+1] = flagWildcards << 1
+
+	// A header claiming far more nodes than the zero bytes after it could hold
+	manyNodes := []byte(binaryMagic)
+	manyNodes = append(manyNodes, binaryVersion, 0, byte(Standard), byte(ArrayOfArrays), 0)
+	manyNodes = binary.AppendUvarint(manyNodes, 1<<16)
+	manyNodes = append(manyNodes, make([]byte, 1<<16)...)
+
+	swapped, err := New([]string{"ab", "cd"}, false).MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary returned error: %v", err)
+	}
+	tests = append(tests, []struct {
+		name string
+		data []byte
+	}{
+		{name: "Unknown flag", data: unknownFlag},
+		{name: "Node count exceeds data", data: manyNodes},
+		{name: "Out list longer than its node", data: trieStream(0)},
+		{name: "Out list not spelling its path", data: trieStream(1)},
+		// The out lists still name the node of "cd" for pattern 1
+		{name: "Pattern not ending at its node", data: bytes.Replace(swapped, []byte("\x02cd"), []byte("\x02xd"), 1)},
+	}...)
+	for i := len(binaryMagic) + 1; i < len(valid); i++ {
+		tests = append(tests, struct {
+			name string
+			data []byte
+		}{name: "Truncated", data: valid[:i]})
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ac := New([]string{"x"}, false)
+			err := ac.UnmarshalBinary(tc.data)
+			if !errors.Is(err, ErrInvalidBinary) {
+				t.Fatalf("UnmarshalBinary error = %v; want %v", err, ErrInvalidBinary)
+			}
+			// A failed load leaves the receiver untouched
+			if got := ac.Count("xx"); got != 2 {
+				t.Errorf("Count after failed UnmarshalBinary = %d; want 2", got)
+			}
+		})
+	}
+}
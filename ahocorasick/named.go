@@ -0,0 +1,73 @@
+package ahocorasick
+
+import (
+	"maps"
+	"slices"
+)
+
+// NamedMatch is a match reported by Named.FindAllNamed, carrying the name of the pattern
+// instead of its index
+type NamedMatch struct {
+	Name  string // name the pattern was registered under
+	Start int    // start index of the match
+	End   int    // end index of the match (inclusive)
+}
+
+// Named is an AhoCorasick automaton whose patterns were registered under names.
+// All of AhoCorasick's methods are available on it and still report PatternIndex values;
+// Name maps them back. Patterns added later with Add have no name.
+type Named struct {
+	*AhoCorasick
+	names []string // names[i] is the name of pattern i
+}
+
+// NewNamed creates an automaton from a map of name to pattern. Since map iteration order is
+// not deterministic, the patterns are indexed by their names in sorted order, so the same map
+// always yields the same PatternIndex values: Names()[i] is the name of pattern i.
+func NewNamed(patterns map[string]string, ignoreCase bool) *Named {
+	return NewNamedWithOptions(patterns, Options{IgnoreCase: ignoreCase})
+}
+
+// NewNamedWithOptions is NewNamed configured by opts
+func NewNamedWithOptions(patterns map[string]string, opts Options) *Named {
+	names := slices.Sorted(maps.Keys(patterns))
+	ps := make([]string, len(names))
+	for i, name := range names {
+		ps[i] = patterns[name]
+	}
+	return &Named{AhoCorasick: NewWithOptions(ps, opts), names: names}
+}
+
+// Names returns the pattern names in PatternIndex order. The slice must not be modified.
+func (n *Named) Names() []string {
+	return n.names
+}
+
+// Name returns the name of the pattern with the given index, or "" if it has none,
+// as for a pattern added with Add
+func (n *Named) Name(patternIndex int) string {
+	if patternIndex < 0 || patternIndex >= len(n.names) {
+		return ""
+	}
+	return n.names[patternIndex]
+}
+
+// FindAllNamed is FindAll reporting each match with the name of its pattern, in the same order
+func (n *Named) FindAllNamed(text string) []NamedMatch {
+	return n._findAllNamed([]byte(text))
+}
+
+// FindAllNamedBytes is FindAllNamed for a byte slice
+func (n *Named) FindAllNamedBytes(data []byte) []NamedMatch {
+	return n._findAllNamed(data)
+}
+
+// _findAllNamed converts matches as the scan reports them, without collecting ACMatch values first
+func (n *Named) _findAllNamed(data []byte) []NamedMatch {
+	var matches []NamedMatch
+	n._findAllFunc(data, n.kind, nil, func(m ACMatch) bool {
+		matches = append(matches, NamedMatch{Name: n.Name(m.PatternIndex), Start: m.Start, End: m.End})
+		return true
+	})
+	return matches
+}
@@ -0,0 +1,72 @@
+package ahocorasick
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNamed(t *testing.T) {
+	patterns := map[string]string{
+		"pronoun/she": "she",
+		"pronoun/he":  "he",
+		"possessive":  "his",
+		"plural":      "hers",
+	}
+	n := NewNamed(patterns, false)
+
+	// Indices follow the sorted names, whatever the map's iteration order
+	wantNames := []string{"plural", "possessive", "pronoun/he", "pronoun/she"}
+	if got := n.Names(); !reflect.DeepEqual(got, wantNames) {
+		t.Fatalf("Names() = %v; want %v", got, wantNames)
+	}
+	for i, name := range wantNames {
+		if got := n.Name(i); got != name {
+			t.Errorf("Name(%d) = %q; want %q", i, got, name)
+		}
+	}
+
+	want := []NamedMatch{
+		{Name: "pronoun/she", Start: 1, End: 3},
+		{Name: "pronoun/he", Start: 2, End: 3},
+		{Name: "plural", Start: 2, End: 5},
+	}
+	if got := n.FindAllNamed("ushers"); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindAllNamed(%q) = %v; want %v", "ushers", got, want)
+	}
+	if got := n.FindAllNamedBytes([]byte("ushers")); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindAllNamedBytes(%q) = %v; want %v", "ushers", got, want)
+	}
+	if got := n.FindAllNamed("nothing"); got != nil {
+		t.Errorf("FindAllNamed(%q) = %v; want nil", "nothing", got)
+	}
+
+	// The embedded automaton reports the same matches by index
+	for _, m := range n.FindAll("ushers his") {
+		if got := n.Name(m.PatternIndex); got == "" || patterns[got] != n.MatchedString("ushers his", m) {
+			t.Errorf("match %v has name %q", m, got)
+		}
+	}
+
+	// A pattern added later has no name
+	idx := n.Add("us")
+	if got := n.Name(idx); got != "" {
+		t.Errorf("Name of an added pattern = %q; want \"\"", got)
+	}
+	if got := n.FindAllNamed("us"); !reflect.DeepEqual(got, []NamedMatch{{Start: 0, End: 1}}) {
+		t.Errorf("FindAllNamed after Add = %v", got)
+	}
+	for _, i := range []int{-1, idx + 1} {
+		if got := n.Name(i); got != "" {
+			t.Errorf("Name(%d) = %q; want \"\"", i, got)
+		}
+	}
+}
+
+func TestNamedWithOptions(t *testing.T) {
+	n := NewNamedWithOptions(map[string]string{"greeting": "HÉLLO", "name": "wörld"},
+		Options{IgnoreCase: true, Runes: true, MatchKind: LeftmostLongest})
+	want := []NamedMatch{{Name: "greeting", Start: 0, End: 4}, {Name: "name", Start: 6, End: 10}}
+	if got := n.FindAllNamed("héllo WÖRLD"); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindAllNamed = %v; want %v", got, want)
+	}
+}
@@ -0,0 +1,5 @@
+//go:build !race
+
+package ahocorasick
+
+const raceEnabled = false
@@ -0,0 +1,49 @@
+package ahocorasick
+
+import "sync"
+
+// maxPooledMatches is the capacity above which MatchPool.Put drops a slice instead of keeping it,
+// so that one pathological text does not pin a huge buffer for the life of the pool
+const maxPooledMatches = 1 << 16
+
+// MatchPool recycles match slices across searches, for use with FindAllInto:
+//
+//	buf := pool.Get()
+//	buf = ac.FindAllInto(text, buf)
+//	... use buf ...
+//	pool.Put(buf)
+//
+// Matches still needed after Put must be copied out first, since the slice's storage will be
+// handed to another caller. Slices holding more than 65536 matches are not kept.
+// The zero value is ready to use, and a MatchPool is safe for concurrent use; it must not be
+// copied after first use.
+type MatchPool struct {
+	slices  sync.Pool // *[]ACMatch holding a slice to reuse
+	headers sync.Pool // empty *[]ACMatch, so that Put does not allocate one per call
+}
+
+// Get returns an empty slice, with the storage of a slice given back with Put if there is one
+func (p *MatchPool) Get() []ACMatch {
+	sp, _ := p.slices.Get().(*[]ACMatch)
+	if sp == nil {
+		return nil
+	}
+	s := (*sp)[:0]
+	*sp = nil
+	p.headers.Put(sp)
+	return s
+}
+
+// Put gives the storage of s back to the pool. s and any slice sharing its storage must not be
+// used afterwards.
+func (p *MatchPool) Put(s []ACMatch) {
+	if cap(s) == 0 || cap(s) > maxPooledMatches {
+		return
+	}
+	sp, _ := p.headers.Get().(*[]ACMatch)
+	if sp == nil {
+		sp = new([]ACMatch)
+	}
+	*sp = s[:0]
+	p.slices.Put(sp)
+}
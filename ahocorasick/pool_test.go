@@ -0,0 +1,68 @@
+package ahocorasick
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestMatchPool(t *testing.T) {
+	var pool MatchPool
+	if got := pool.Get(); len(got) != 0 {
+		t.Fatalf("Get() from an empty pool = %v; want an empty slice", got)
+	}
+
+	ac := New([]string{"he", "she", "his", "hers"}, false)
+	buf := ac.FindAllInto("ushers", pool.Get())
+	want := ac.FindAll("ushers")
+	if !reflect.DeepEqual(buf, want) {
+		t.Fatalf("FindAllInto with a pooled slice = %v; want %v", buf, want)
+	}
+	pool.Put(buf)
+
+	// Oversized and empty slices are dropped rather than kept
+	pool.Put(make([]ACMatch, 0, maxPooledMatches+1))
+	pool.Put(nil)
+	if got := pool.Get(); len(got) != 0 || cap(got) > maxPooledMatches {
+		t.Errorf("Get() = slice of len %d, cap %d; want an empty slice of at most %d", len(got), cap(got), maxPooledMatches)
+	}
+}
+
+func TestMatchPoolDoesNotAllocate(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector makes sync.Pool drop items")
+	}
+	var pool MatchPool
+	ac := New([]string{"he", "she", "his", "hers"}, false)
+	pool.Put(make([]ACMatch, 0, 16))
+	allocs := testing.AllocsPerRun(100, func() {
+		buf := ac.FindAllInto("ushers his hers", pool.Get())
+		pool.Put(buf)
+	})
+	if allocs != 0 {
+		t.Errorf("Get, FindAllInto and Put allocated %v times per run; want 0", allocs)
+	}
+}
+
+func TestMatchPoolConcurrent(t *testing.T) {
+	var pool MatchPool
+	ac := New([]string{"he", "she", "his", "hers"}, false)
+	want := ac.FindAll("ushers his hers")
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 200 {
+				buf := ac.FindAllInto("ushers his hers", pool.Get())
+				if !reflect.DeepEqual(buf, want) {
+					t.Errorf("FindAllInto with a pooled slice = %v; want %v", buf, want)
+					return
+				}
+				pool.Put(buf)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,32 @@
+package ahocorasick
+
+import "encoding/binary"
+
+// maxPrefilterBytes is the largest number of distinct start bytes the prefilter tests for.
+// Each one costs a comparison per word of text, so with more of them the table lookup per
+// byte in skipRoot is as fast.
+const maxPrefilterBytes = 4
+
+const (
+	lsbs = 0x0101010101010101 // the lowest bit of every byte
+	msbs = 0x8080808080808080 // the highest bit of every byte
+)
+
+// skipWords advances i over data[i:end] eight bytes at a time while none of the eight is a
+// start byte, and returns where it stopped: at end, or at a word skipRoot must finish byte by
+// byte. Each word is compared against every start byte repeated eight times (SWAR).
+func (ac *AhoCorasick) skipWords(data []byte, i, end int) int {
+	ws := &ac.startWords
+	for ; i+8 <= end; i += 8 {
+		w := binary.LittleEndian.Uint64(data[i:])
+		if hasZeroByte(w^ws[0]) || hasZeroByte(w^ws[1]) || hasZeroByte(w^ws[2]) || hasZeroByte(w^ws[3]) {
+			break
+		}
+	}
+	return i
+}
+
+// hasZeroByte reports whether any of the eight bytes of w is zero
+func hasZeroByte(w uint64) bool {
+	return (w-lsbs)&^w&msbs != 0
+}
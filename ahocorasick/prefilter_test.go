@@ -0,0 +1,92 @@
+package ahocorasick
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAhoCorasickPrefilter(t *testing.T) {
+	tests := []struct {
+		name      string
+		patterns  []string
+		opts      Options
+		wantBytes int // start bytes the prefilter tests for
+	}{
+		{name: "One start byte", patterns: []string{"ab", "abc", "a"}, wantBytes: 1},
+		{name: "Four start bytes", patterns: []string{"ab", "ba", "cab", "d"}, wantBytes: 4},
+		{name: "Too many start bytes", patterns: []string{"a", "b", "c", "d", "e"}, wantBytes: 0},
+		{name: "Ignore case counts both cases", patterns: []string{"ab", "BA"}, opts: Options{IgnoreCase: true}, wantBytes: 4},
+		{name: "Ignore case, too many", patterns: []string{"ab", "ba", "c"}, opts: Options{IgnoreCase: true}, wantBytes: 0},
+		{name: "Leftmost-longest", patterns: []string{"ab", "abc", "b"}, opts: Options{MatchKind: LeftmostLongest}, wantBytes: 2},
+		{name: "Runes", patterns: []string{"éa", "b"}, opts: Options{Runes: true, IgnoreCase: true}, wantBytes: 3},
+		{name: "Sparse map", patterns: []string{"ab", "c"}, opts: Options{Backend: SparseMap}, wantBytes: 2},
+		{name: "No patterns", patterns: nil, wantBytes: 0},
+	}
+
+	rng := rand.New(rand.NewPCG(1, 2))
+	var texts []string
+	for range 200 {
+		var b strings.Builder
+		for range rng.IntN(40) {
+			b.WriteByte("abcdeABCDE xyz"[rng.IntN(14)])
+		}
+		texts = append(texts, b.String())
+	}
+	texts = append(texts, strings.Repeat("xyz ", 100)+"ab"+strings.Repeat("z", 13)+"cab", "é"+strings.Repeat("y", 17)+"ÉA")
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			plain := NewWithOptions(tc.patterns, tc.opts)
+			opts := tc.opts
+			opts.Prefilter = true
+			ac := NewWithOptions(tc.patterns, opts)
+			if ac.numStartBytes != tc.wantBytes {
+				t.Fatalf("prefilter tests for %d start bytes; want %d", ac.numStartBytes, tc.wantBytes)
+			}
+
+			for _, text := range texts {
+				if got, want := ac.FindAll(text), plain.FindAll(text); !reflect.DeepEqual(got, want) {
+					t.Errorf("FindAll(%q) with prefilter = %v; want %v", text, got, want)
+				}
+				if got, want := ac.Contains(text), plain.Contains(text); got != want {
+					t.Errorf("Contains(%q) with prefilter = %v; want %v", text, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestAhoCorasickPrefilterKept(t *testing.T) {
+	ac := NewWithOptions([]string{"ab"}, Options{Prefilter: true})
+
+	// Add rebuilds the start bytes
+	ac.Add("cd")
+	if ac.numStartBytes != 2 {
+		t.Errorf("after Add the prefilter tests for %d start bytes; want 2", ac.numStartBytes)
+	}
+	if got := ac.Count("xx ab xx cd"); got != 2 {
+		t.Errorf("Count after Add = %d; want 2", got)
+	}
+
+	ac.Rebuild(true)
+	if ac.numStartBytes != 4 {
+		t.Errorf("after Rebuild the prefilter tests for %d start bytes; want 4", ac.numStartBytes)
+	}
+
+	data, err := ac.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary returned error: %v", err)
+	}
+	var loaded AhoCorasick
+	if err := loaded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary returned error: %v", err)
+	}
+	if !loaded.prefilter || loaded.numStartBytes != 4 {
+		t.Errorf("loaded automaton has prefilter=%v with %d start bytes; want true with 4", loaded.prefilter, loaded.numStartBytes)
+	}
+	if !loaded.Equal(NewWithOptions([]string{"ab", "cd"}, Options{IgnoreCase: true})) {
+		t.Errorf("Equal should ignore the prefilter")
+	}
+}
@@ -0,0 +1,134 @@
+package ahocorasick
+
+// MatchPrefix returns the longest pattern the text begins with, as a match with Start 0.
+// Only trie edges from the root are followed, so the scan stops at the first byte that
+// no pattern continues with instead of reading the whole text; in rune mode the text is
+// folded one rune at a time as the walk goes, so that holds there too. When several patterns
+// are the same keyword, the one with the lowest PatternIndex is reported. Under
+// Options.TokenBoundary the longest pattern followed by a boundary or the end of the text is.
+// The second result is false if no pattern is a prefix of the text.
+func (ac *AhoCorasick) MatchPrefix(text string) (ACMatch, bool) {
+	return ac._matchPrefix([]byte(text))
+}
+
+// MatchPrefixBytes returns the longest pattern the byte slice begins with, as a match with Start 0.
+// The second result is false if no pattern is a prefix of the data.
+func (ac *AhoCorasick) MatchPrefixBytes(data []byte) (ACMatch, bool) {
+	return ac._matchPrefix(data)
+}
+
+// _matchPrefix walks the trie along data. A node's own patterns come first in its out
+// list and are exactly those as long as the node is deep; later entries are inherited
+// through failure links and start after 0.
+func (ac *AhoCorasick) _matchPrefix(data []byte) (ACMatch, bool) {
+	if ac.runes {
+		return ac.matchPrefixRunes(data)
+	}
+	var best ACMatch
+	found := false
+	node := 0
+	for i := 0; i < len(data); i++ {
+		if node = ac.child(node, ac.normChar(data[i])); node == 0 {
+			break
+		}
+		if idx, ok := ac.ownPattern(node); ok && ac.endsToken(data, i+1) {
+			best = ACMatch{PatternIndex: idx, Start: 0, End: i}
+			found = true
+		}
+	}
+	return best, found
+}
+
+// endsToken reports whether a match of a prefix ending just before data[next] is a token
+// under Options.TokenBoundary; the start of data is a text edge
+func (ac *AhoCorasick) endsToken(data []byte, next int) bool {
+	return ac.tokenBoundary == nil || next == len(data) || ac.tokenBoundary(data[next])
+}
+
+// ownPattern returns the lowest-indexed pattern ending exactly at node, not inherited
+// through its failure link
+func (ac *AhoCorasick) ownPattern(node int) (int, bool) {
+	if out := ac.out[node]; len(out) > 0 && len(ac.keywords[out[0]]) == ac.depth[node] {
+		return out[0], true
+	}
+	return 0, false
+}
+
+// KeywordsWithPrefix returns the patterns that begin with prefix, as given to New, for keyword
+// autocompletion on the automaton's trie. Only the prefix is walked, then the subtree below it,
+// so the cost does not depend on the number of other patterns. Prefix and patterns are compared
+// after the automaton's case folding, and the results come in the trie's byte order, shorter
+// before longer, with a duplicate pattern reported once per copy. Empty and removed patterns
+// are never reported.
+//
+// The prefix is walked like text, so with Options.Wildcards it spells concrete bytes: under
+// {'#': byteclass.Range('0', '9')}, "err5" completes to the pattern "err#", while a literal '#'
+// in the prefix only follows keywords whose wildcard sets contain '#'. A wildcard pattern is
+// reported once, where the first of its expanded paths comes in byte order.
+func (ac *AhoCorasick) KeywordsWithPrefix(prefix string) []string {
+	var words []string
+	if node, ok := ac.walkPrefix([]byte(prefix)); ok {
+		ac.eachKeyword(node, make([]bool, len(ac.patterns)), func(idx int) bool {
+			words = append(words, ac.patterns[idx])
+			return true
+		})
+	}
+	return words
+}
+
+// HasPrefix reports whether some non-empty pattern begins with prefix,
+// after the automaton's case folding. A pattern begins with itself.
+func (ac *AhoCorasick) HasPrefix(prefix string) bool {
+	node, ok := ac.walkPrefix([]byte(prefix))
+	if !ok {
+		return false
+	}
+	found := false
+	ac.eachKeyword(node, nil, func(int) bool {
+		found = true
+		return false
+	})
+	return found
+}
+
+// walkPrefix follows the trie edges spelling the folded prefix and returns the node reached.
+// The second result is false if no pattern continues the prefix.
+func (ac *AhoCorasick) walkPrefix(prefix []byte) (int, bool) {
+	h := ac.prepare(prefix)
+	node := 0
+	for _, c := range h.data {
+		if node = ac.child(node, ac.normChar(c)); node == 0 {
+			return 0, false
+		}
+	}
+	return node, true
+}
+
+// eachKeyword calls fn with the index of every pattern ending in the subtree of node, depth
+// first, and reports whether it got to the end: it stops as soon as fn returns false.
+// A node's own patterns are those as long as the node is deep. The root's are empty, and
+// nodes left behind by Remove have none. A wildcard pattern ends at one node per expanded
+// path; if seen is not nil it records the patterns reported, so each is reported once.
+func (ac *AhoCorasick) eachKeyword(node int, seen []bool, fn func(idx int) bool) bool {
+	if node != 0 {
+		for _, idx := range ac.out[node] {
+			if len(ac.keywords[idx]) != ac.depth[node] {
+				continue
+			}
+			if seen != nil {
+				if seen[idx] {
+					continue
+				}
+				seen[idx] = true
+			}
+			if !fn(idx) {
+				return false
+			}
+		}
+	}
+	more := true
+	ac.forEachChild(node, func(_ byte, nx int) {
+		more = more && ac.eachKeyword(nx, seen, fn)
+	})
+	return more
+}
@@ -0,0 +1,254 @@
+package ahocorasick
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/notJoon/searcher/byteclass"
+)
+
+func TestAhoCorasickMatchPrefix(t *testing.T) {
+	tests := []struct {
+		name       string
+		patterns   []string
+		text       string
+		ignoreCase bool
+		want       ACMatch
+		wantOK     bool
+	}{
+		{
+			name:     "Longest prefix",
+			patterns: []string{"GET", "GETALL", "POST"},
+			text:     "GETALL /users",
+			want:     ACMatch{PatternIndex: 1, Start: 0, End: 5},
+			wantOK:   true,
+		},
+		{
+			name:     "Shorter prefix when longer does not complete",
+			patterns: []string{"GET", "GETALL"},
+			text:     "GETAL",
+			want:     ACMatch{PatternIndex: 0, Start: 0, End: 2},
+			wantOK:   true,
+		},
+		{
+			name:     "Match later in text is ignored",
+			patterns: []string{"he", "she"},
+			text:     "ushers",
+			wantOK:   false,
+		},
+		{
+			name:     "Inherited output is not a prefix",
+			patterns: []string{"she", "he"},
+			text:     "shx",
+			wantOK:   false,
+		},
+		{
+			name:     "Duplicate pattern reports lowest index",
+			patterns: []string{"b", "ab", "ab"},
+			text:     "abc",
+			want:     ACMatch{PatternIndex: 1, Start: 0, End: 1},
+			wantOK:   true,
+		},
+		{
+			name:       "Ignore case",
+			patterns:   []string{"Post"},
+			text:       "POST /",
+			ignoreCase: true,
+			want:       ACMatch{PatternIndex: 0, Start: 0, End: 3},
+			wantOK:     true,
+		},
+		{
+			name:     "Empty text",
+			patterns: []string{"a"},
+			text:     "",
+			wantOK:   false,
+		},
+	}
+
+	for _, tc := range tests {
+		for _, backend := range []Backend{ArrayOfArrays, SparseMap, DoubleArray} {
+			t.Run(tc.name, func(t *testing.T) {
+				ac := NewWithOptions(tc.patterns, Options{IgnoreCase: tc.ignoreCase, Backend: backend})
+				got, ok := ac.MatchPrefix(tc.text)
+				if ok != tc.wantOK || got != tc.want {
+					t.Errorf("MatchPrefix(%q) with backend %d = %v, %v; want %v, %v",
+						tc.text, backend, got, ok, tc.want, tc.wantOK)
+				}
+				got, ok = ac.MatchPrefixBytes([]byte(tc.text))
+				if ok != tc.wantOK || got != tc.want {
+					t.Errorf("MatchPrefixBytes(%q) with backend %d = %v, %v; want %v, %v",
+						tc.text, backend, got, ok, tc.want, tc.wantOK)
+				}
+			})
+		}
+	}
+}
+
+func TestAhoCorasickMatchPrefixRunes(t *testing.T) {
+	calls := 0
+	opts := Options{IgnoreCase: true, Normalize: func(r rune) string {
+		calls++
+		return decompose(r)
+	}}
+	ac := NewWithOptions([]string{"fi", "fin", "café"}, opts)
+
+	// Runes: C0 a1 f2 É3 _4 ...; "fi" ends inside the decomposition of "ﬁ" and covers it
+	tests := []struct {
+		text   string
+		want   ACMatch
+		wantOK bool
+	}{
+		{"CafÉ au lait", ACMatch{PatternIndex: 2, Start: 0, End: 3}, true},
+		{"ﬁne", ACMatch{PatternIndex: 1, Start: 0, End: 1}, true},
+		{"ﬁ", ACMatch{PatternIndex: 0, Start: 0, End: 0}, true},
+		{"xﬁ", ACMatch{}, false},
+	}
+	for _, tc := range tests {
+		got, ok := ac.MatchPrefix(tc.text)
+		if ok != tc.wantOK || got != tc.want {
+			t.Errorf("MatchPrefix(%q) = %v, %v; want %v, %v", tc.text, got, ok, tc.want, tc.wantOK)
+		}
+	}
+
+	// The text is folded only as far as the walk gets
+	calls = 0
+	text := "cab" + strings.Repeat("é", 1000)
+	if _, ok := ac.MatchPrefix(text); ok {
+		t.Errorf("MatchPrefix(%q...) found a match", text[:5])
+	}
+	if calls > 3 {
+		t.Errorf("Normalize called %d times; want at most 3", calls)
+	}
+}
+
+func TestAhoCorasickKeywordsWithPrefix(t *testing.T) {
+	tests := []struct {
+		name       string
+		patterns   []string
+		prefix     string
+		opts       Options
+		want       []string
+		wantHas    bool
+		removeIdxs []int
+	}{
+		{
+			name:     "Completions in byte order",
+			patterns: []string{"status", "stash", "start", "push", "st"},
+			prefix:   "sta",
+			want:     []string{"start", "stash", "status"},
+			wantHas:  true,
+		},
+		{
+			name:     "Prefix equal to a pattern includes it first",
+			patterns: []string{"status", "st", "stop"},
+			prefix:   "st",
+			want:     []string{"st", "status", "stop"},
+			wantHas:  true,
+		},
+		{
+			name:     "Empty prefix lists every non-empty pattern",
+			patterns: []string{"b", "", "a"},
+			prefix:   "",
+			want:     []string{"a", "b"},
+			wantHas:  true,
+		},
+		{
+			name:     "No pattern continues the prefix",
+			patterns: []string{"status", "push"},
+			prefix:   "stx",
+			wantHas:  false,
+		},
+		{
+			name:     "Prefix longer than every pattern",
+			patterns: []string{"st"},
+			prefix:   "status",
+			wantHas:  false,
+		},
+		{
+			name:     "Duplicates are reported once per copy",
+			patterns: []string{"go", "gopher", "go"},
+			prefix:   "g",
+			want:     []string{"go", "go", "gopher"},
+			wantHas:  true,
+		},
+		{
+			name:     "Ignore case returns the patterns as given",
+			patterns: []string{"GoLang", "gopher", "Rust"},
+			prefix:   "GO",
+			opts:     Options{IgnoreCase: true},
+			want:     []string{"GoLang", "gopher"},
+			wantHas:  true,
+		},
+		{
+			name:     "Unicode folding in rune mode",
+			patterns: []string{"Straße", "STRASSE"},
+			prefix:   "stra",
+			opts:     Options{IgnoreCase: true, Runes: true},
+			want:     []string{"STRASSE", "Straße"},
+			wantHas:  true,
+		},
+		{
+			name:     "Wildcard pattern reported once",
+			patterns: []string{"err#", "errx"},
+			prefix:   "err",
+			opts:     Options{Wildcards: map[byte]byteclass.Set{'#': byteclass.Range('0', '9')}},
+			want:     []string{"err#", "errx"},
+			wantHas:  true,
+		},
+		{
+			name:     "Prefix spelling a wildcard byte",
+			patterns: []string{"err#", "errx"},
+			prefix:   "err5",
+			opts:     Options{Wildcards: map[byte]byteclass.Set{'#': byteclass.Range('0', '9')}},
+			want:     []string{"err#"},
+			wantHas:  true,
+		},
+		{
+			name:     "Literal sentinel in the prefix",
+			patterns: []string{"err#", "errx"},
+			prefix:   "err#",
+			opts:     Options{Wildcards: map[byte]byteclass.Set{'#': byteclass.Range('0', '9')}},
+			wantHas:  false,
+		},
+		{
+			name:       "Removed patterns are not reported",
+			patterns:   []string{"stash", "status"},
+			prefix:     "stas",
+			removeIdxs: []int{0},
+			wantHas:    false,
+		},
+		{
+			name:     "Empty automaton",
+			patterns: nil,
+			prefix:   "",
+			wantHas:  false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ac := NewWithOptions(tc.patterns, tc.opts)
+			for _, idx := range tc.removeIdxs {
+				ac.Remove(idx)
+			}
+			if got := ac.KeywordsWithPrefix(tc.prefix); !slices.Equal(got, tc.want) {
+				t.Errorf("KeywordsWithPrefix(%q) = %q; want %q", tc.prefix, got, tc.want)
+			}
+			if got := ac.HasPrefix(tc.prefix); got != tc.wantHas {
+				t.Errorf("HasPrefix(%q) = %v; want %v", tc.prefix, got, tc.wantHas)
+			}
+		})
+	}
+}
+
+func TestAhoCorasickKeywordsWithPrefixBackends(t *testing.T) {
+	patterns := []string{"car", "card", "care", "cart", "cat", "dog"}
+	want := []string{"car", "card", "care", "cart"}
+	for _, backend := range []Backend{ArrayOfArrays, SparseMap, DoubleArray} {
+		ac := NewWithOptions(patterns, Options{Backend: backend})
+		if got := ac.KeywordsWithPrefix("car"); !slices.Equal(got, want) {
+			t.Errorf("backend %d: KeywordsWithPrefix(%q) = %q; want %q", backend, "car", got, want)
+		}
+	}
+}
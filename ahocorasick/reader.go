@@ -0,0 +1,252 @@
+package ahocorasick
+
+import (
+	"io"
+	"unicode/utf8"
+)
+
+// readChunkSize is the number of bytes requested from the reader per read
+const readChunkSize = 32 * 1024
+
+// FindAllReader feeds the stream through the automaton and calls cb for every match
+// as soon as it ends, in the Standard order documented on FindAll. Start and End are
+// absolute offsets in the stream. Because the automaton state carries over between reads,
+// matches straddling reads need no special handling and nothing but the current chunk
+// is kept in memory.
+// Every match of every pattern is reported (Standard semantics), whatever the
+// automaton's MatchKind, since leftmost semantics would require rescanning consumed input.
+// In rune mode Start and End are rune indices in the stream.
+// Returns nil when the stream is exhausted, or the first error other than io.EOF returned by the reader.
+func (ac *AhoCorasick) FindAllReader(r io.Reader, cb func(ACMatch)) error {
+	s := NewScanner(ac, r)
+	for s.Scan() {
+		cb(s.Match())
+	}
+	return s.Err()
+}
+
+// Scanner reads matches from a stream one at a time, the pull-based counterpart of
+// FindAllReader. Successive calls to Scan step through the same matches FindAllReader
+// reports, in the same order:
+//
+//	s := ahocorasick.NewScanner(ac, r)
+//	for s.Scan() {
+//		use(s.Match())
+//	}
+//	if err := s.Err(); err != nil {
+//		...
+//	}
+//
+// The automaton state and the absolute offset carry over between reads, so matches
+// straddling reads need no special handling by the caller.
+type Scanner struct {
+	ac  *AhoCorasick
+	r   io.Reader
+	buf []byte // read buffer, with room for a rune cut off at the end of the previous read
+	err error  // first error other than io.EOF returned by r
+	eof bool
+
+	data   []byte // bytes read and not yet fed to the automaton are data[i:]
+	i      int
+	offset int // absolute offset of data[0] in byte mode
+
+	node   int    // automaton state
+	folded []byte // rune mode: the folded bytes of the current rune not yet fed are folded[fi:]
+	fi     int
+	next   int // rune mode: absolute index of the next rune to decode
+
+	// Under Normalize keywords are counted in normalized runes, which need not line up with
+	// the stream's runes. starts[k%len(starts)] is the stream rune index of the k-th
+	// normalized rune fed, for the last len(starts) of them, and fed is how many were fed.
+	starts  []int
+	fed     int
+	invalid bool // the current rune is an invalid byte, copied as a rune of its own
+
+	// Under TokenBoundary a match needs the bytes around it. In byte mode fill keeps the last
+	// tail bytes fed in data, so that the byte before Start is still there. In rune mode
+	// lead[k%len(lead)] tells whether stream rune k begins a token, for the last len(lead)
+	// runes, and last is the final byte of the latest rune. peeked is set once the byte after
+	// the pending matches, data[i], has been read and found to be a boundary or the end.
+	tail   int
+	lead   []bool
+	last   byte
+	peeked bool
+
+	out   []int // patterns ending at the current position not reported yet
+	end   int   // End of those matches
+	match ACMatch
+}
+
+// NewScanner returns a Scanner reporting the matches of ac's patterns in the stream read from r.
+// As with FindAllReader, every match of every pattern is reported (Standard semantics)
+// whatever ac's MatchKind, and in rune mode Start and End are rune indices in the stream.
+func NewScanner(ac *AhoCorasick, r io.Reader) *Scanner {
+	s := &Scanner{ac: ac, r: r}
+	if ac.tokenBoundary != nil {
+		if ac.runes {
+			s.lead = make([]bool, max(ac.maxLen, 1))
+		} else {
+			s.tail = ac.maxLen + 1
+		}
+	}
+	s.buf = make([]byte, readChunkSize+utf8.UTFMax+s.tail)
+	if ac.normalize != nil {
+		longest := 1
+		for _, kw := range ac.keywords {
+			longest = max(longest, utf8.RuneCount(kw))
+		}
+		s.starts = make([]int, longest)
+	}
+	return s
+}
+
+// Scan advances to the next match, which is then available through Match.
+// It returns false when the stream is exhausted or a read fails; Err tells which.
+// Matches found in the data read before a failed read are still reported.
+func (s *Scanner) Scan() bool {
+	for {
+		if len(s.out) > 0 && s.ac.tokenBoundary != nil && !s.peeked {
+			if s.i == len(s.data) && s.fill() {
+				continue
+			}
+			s.peeked = true
+			if s.i < len(s.data) && !s.ac.tokenBoundary(s.data[s.i]) {
+				s.out = nil
+			}
+		}
+		for len(s.out) > 0 {
+			patIdx := s.out[0]
+			s.out = s.out[1:]
+			m := ACMatch{
+				PatternIndex: patIdx,
+				Start:        s.end - s.keywordLen(patIdx) + 1,
+				End:          s.end,
+			}
+			if s.starts != nil {
+				m.Start = s.starts[(s.fed-s.keywordLen(patIdx))%len(s.starts)]
+			}
+			if s.ac.tokenBoundary == nil || s.startsToken(m.Start) {
+				s.match = m
+				return true
+			}
+		}
+		s.peeked = false
+		var found bool
+		if s.ac.runes {
+			found = s.advanceRunes()
+		} else {
+			found = s.advance()
+		}
+		if !found && !s.fill() {
+			return false
+		}
+	}
+}
+
+// Match returns the match found by the last successful call to Scan.
+func (s *Scanner) Match() ACMatch {
+	return s.match
+}
+
+// Err returns the first error other than io.EOF returned by the reader, or nil.
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+// advance feeds buffered bytes through the automaton until it reaches a node where patterns
+// end, and reports whether it did before running out of data.
+func (s *Scanner) advance() bool {
+	ac, data := s.ac, s.data
+	node := s.node
+	for i := s.i; i < len(data); i++ {
+		if node == 0 {
+			if i = ac.skipRoot(data, i, len(data)); i == len(data) {
+				break
+			}
+		}
+		node = ac.step(node, ac.normChar(data[i]))
+		if out := ac.out[node]; len(out) > 0 {
+			s.node, s.i = node, i+1
+			s.out, s.end = out, s.offset+i
+			return true
+		}
+	}
+	s.node, s.i = node, len(data)
+	return false
+}
+
+// advanceRunes is advance in rune mode. Each rune is folded as it is decoded and fed through
+// the automaton byte by byte; a rune cut off at the end of the data is left for the next read
+// to complete, unless the stream has ended.
+func (s *Scanner) advanceRunes() bool {
+	ac := s.ac
+	node, fi := s.node, s.fi
+	for {
+		for folded := s.folded; fi < len(folded); {
+			if s.starts != nil && (s.invalid || utf8.RuneStart(folded[fi])) {
+				s.starts[s.fed%len(s.starts)] = s.next - 1
+				s.fed++
+			}
+			node = ac.step(node, ac.normChar(folded[fi]))
+			fi++
+			if out := ac.out[node]; len(out) > 0 {
+				s.node, s.fi = node, fi
+				s.out, s.end = out, s.next-1
+				return true
+			}
+		}
+		rest := s.data[s.i:]
+		if len(rest) == 0 || (!s.eof && s.err == nil && !utf8.FullRune(rest)) {
+			s.node, s.fi = node, fi
+			return false
+		}
+		var size int
+		s.folded, size = ac.appendFolded(s.folded[:0], rest)
+		s.invalid = size == 1 && rest[0] >= utf8.RuneSelf
+		if s.lead != nil {
+			s.lead[s.next%len(s.lead)] = s.next == 0 || ac.tokenBoundary(s.last)
+			s.last = rest[size-1]
+		}
+		s.i, fi = s.i+size, 0
+		s.next++
+	}
+}
+
+// fill reads the next chunk from the reader, keeping the bytes not yet fed and up to
+// tail bytes before them, and reports whether there may be more to scan.
+func (s *Scanner) fill() bool {
+	if s.eof || s.err != nil {
+		return false
+	}
+	keep := min(s.i, s.tail)
+	kept := copy(s.buf, s.data[s.i-keep:])
+	s.offset += s.i - keep
+	n, err := s.r.Read(s.buf[kept : kept+readChunkSize])
+	s.data, s.i = s.buf[:kept+n], keep
+	if err == io.EOF {
+		s.eof = true
+	} else if err != nil {
+		s.err = err
+	}
+	return true
+}
+
+// startsToken reports whether the byte before the stream position start is a text edge
+// or a boundary under Options.TokenBoundary
+func (s *Scanner) startsToken(start int) bool {
+	if start == 0 {
+		return true
+	}
+	if s.lead != nil {
+		return s.lead[start%len(s.lead)]
+	}
+	return s.ac.tokenBoundary(s.data[start-1-s.offset])
+}
+
+// keywordLen returns the length of the pattern in the units of Start and End
+func (s *Scanner) keywordLen(patIdx int) int {
+	if s.ac.runes {
+		return utf8.RuneCount(s.ac.keywords[patIdx])
+	}
+	return len(s.ac.keywords[patIdx])
+}
@@ -0,0 +1,200 @@
+package ahocorasick
+
+import (
+	"errors"
+	"io"
+	"math/rand/v2"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestAhoCorasickReader(t *testing.T) {
+	type testCase struct {
+		name       string
+		patterns   []string
+		text       string
+		ignoreCase bool
+	}
+	tests := []testCase{
+		{
+			name:     "Basic multiple patterns",
+			patterns: []string{"he", "she", "his", "hers"},
+			text:     "ushers and his shells",
+		},
+		{
+			name:       "Ignore case",
+			patterns:   []string{"He", "She", "Hers"},
+			text:       "USHERS",
+			ignoreCase: true,
+		},
+		{
+			name:     "Matches straddling chunk boundary",
+			patterns: []string{"needle", "dle"},
+			text:     strings.Repeat("x", readChunkSize-3) + "needle" + strings.Repeat("y", readChunkSize) + "needle",
+		},
+		{
+			name:     "No match",
+			patterns: []string{"cat", "dog"},
+			text:     "mouse",
+		},
+		{
+			name:     "Empty text",
+			patterns: []string{"abc"},
+			text:     "",
+		},
+	}
+
+	readers := map[string]func(string) io.Reader{
+		"full":     func(s string) io.Reader { return strings.NewReader(s) },
+		"one byte": func(s string) io.Reader { return iotest.OneByteReader(strings.NewReader(s)) },
+		"half":     func(s string) io.Reader { return iotest.HalfReader(strings.NewReader(s)) },
+		"data+EOF": func(s string) io.Reader { return iotest.DataErrReader(strings.NewReader(s)) },
+	}
+
+	for _, tc := range tests {
+		for rname, newReader := range readers {
+			t.Run(tc.name+"/"+rname, func(t *testing.T) {
+				ac := New(tc.patterns, tc.ignoreCase)
+
+				var got []ACMatch
+				err := ac.FindAllReader(newReader(tc.text), func(m ACMatch) {
+					got = append(got, m)
+				})
+				if err != nil {
+					t.Fatalf("FindAllReader returned error: %v", err)
+				}
+				if want := ac.FindAll(tc.text); !reflect.DeepEqual(got, want) {
+					t.Errorf("FindAllReader got %v, want %v", got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestAhoCorasickReaderTokenBoundary(t *testing.T) {
+	isSep := func(c byte) bool { return c == ' ' || c == ',' }
+	patterns := []string{"a", "ab", "ba", "bab", "é"}
+	straddle := strings.Repeat("a", readChunkSize-1) + " ab ba" + strings.Repeat(" ", readChunkSize-2) + "bab"
+	rng := rand.New(rand.NewPCG(5, 6))
+	const alphabet = "abÉé ,"
+	texts := []string{"", "ab", "a ab,ba bab", straddle}
+	for range 200 {
+		var sb strings.Builder
+		for range rng.IntN(30) {
+			sb.WriteRune([]rune(alphabet)[rng.IntN(len([]rune(alphabet)))])
+		}
+		texts = append(texts, sb.String())
+	}
+
+	for _, opts := range []Options{{}, {IgnoreCase: true, Runes: true}, {Normalize: func(r rune) string { return string(r) }}} {
+		opts.TokenBoundary = isSep
+		ac := NewWithOptions(patterns, opts)
+		for _, text := range texts {
+			want := ac.FindAll(text)
+			for _, r := range []io.Reader{strings.NewReader(text), iotest.OneByteReader(strings.NewReader(text))} {
+				var got []ACMatch
+				if err := ac.FindAllReader(r, func(m ACMatch) { got = append(got, m) }); err != nil {
+					t.Fatalf("FindAllReader returned error: %v", err)
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Fatalf("Runes=%v: FindAllReader(%q) got %v, want %v", ac.runes, text, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestAhoCorasickReaderError(t *testing.T) {
+	wantErr := errors.New("read failed")
+	r := io.MultiReader(strings.NewReader("ushers"), iotest.ErrReader(wantErr))
+
+	var got []ACMatch
+	err := New([]string{"he"}, false).FindAllReader(r, func(m ACMatch) {
+		got = append(got, m)
+	})
+	if !errors.Is(err, wantErr) {
+		t.Errorf("FindAllReader error = %v, want %v", err, wantErr)
+	}
+	if want := []ACMatch{{PatternIndex: 0, Start: 2, End: 3}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FindAllReader before error got %v, want %v", got, want)
+	}
+}
+
+func TestAhoCorasickScanner(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		text     string
+		opts     Options
+	}{
+		{
+			name:     "Nested patterns",
+			patterns: []string{"he", "she", "his", "hers", "e"},
+			text:     "ushers and his shells",
+		},
+		{
+			name:     "Matches straddling chunk boundary",
+			patterns: []string{"needle", "dle"},
+			text:     strings.Repeat("x", readChunkSize-3) + "needle" + strings.Repeat("y", readChunkSize) + "needle",
+		},
+		{
+			name:     "Runes",
+			patterns: []string{"日本", "ÿ", "bk"},
+			text:     strings.Repeat("-", readChunkSize-1) + "日本語 BK Ÿ \xe6\x97",
+			opts:     Options{IgnoreCase: true, Runes: true},
+		},
+		{
+			name:     "Empty text",
+			patterns: []string{"abc"},
+			text:     "",
+		},
+	}
+
+	readers := map[string]func(string) io.Reader{
+		"full":     func(s string) io.Reader { return strings.NewReader(s) },
+		"one byte": func(s string) io.Reader { return iotest.OneByteReader(strings.NewReader(s)) },
+		"data+EOF": func(s string) io.Reader { return iotest.DataErrReader(strings.NewReader(s)) },
+	}
+
+	for _, tc := range tests {
+		for rname, newReader := range readers {
+			t.Run(tc.name+"/"+rname, func(t *testing.T) {
+				ac := NewWithOptions(tc.patterns, tc.opts)
+				s := NewScanner(ac, newReader(tc.text))
+				var got []ACMatch
+				for s.Scan() {
+					got = append(got, s.Match())
+				}
+				if err := s.Err(); err != nil {
+					t.Fatalf("Err() = %v", err)
+				}
+				if want := ac.FindAll(tc.text); !reflect.DeepEqual(got, want) {
+					t.Errorf("Scanner got %v, want %v", got, want)
+				}
+				if s.Scan() {
+					t.Errorf("Scan() after the end = true, want false")
+				}
+			})
+		}
+	}
+}
+
+func TestAhoCorasickScannerError(t *testing.T) {
+	wantErr := errors.New("read failed")
+	r := io.MultiReader(strings.NewReader("ushers"), iotest.ErrReader(wantErr))
+	s := NewScanner(New([]string{"he", "she"}, false), r)
+
+	var got []ACMatch
+	for s.Scan() {
+		got = append(got, s.Match())
+	}
+	if !errors.Is(s.Err(), wantErr) {
+		t.Errorf("Err() = %v, want %v", s.Err(), wantErr)
+	}
+	want := []ACMatch{{PatternIndex: 1, Start: 1, End: 3}, {PatternIndex: 0, Start: 2, End: 3}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Scanner before error got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,33 @@
+package ahocorasick
+
+// Relation describes how two matches lie relative to each other in the text
+type Relation int
+
+const (
+	// Overlap means the matches share at least one position, including when
+	// one match is nested inside the other.
+	Overlap Relation = iota
+	// Adjacent means one match starts right after the other ends, with nothing between them.
+	Adjacent
+	// Gap means at least one position separates the matches.
+	Gap
+)
+
+// Classify reports whether prev and cur overlap, touch, or are separated by a gap.
+// End is inclusive, so cur is Adjacent to prev when cur.Start == prev.End+1.
+// The result does not depend on the order of the arguments, and both matches must use
+// the same unit: byte offsets, or rune indices in rune mode. FindAllNonOverlapping already
+// reports matches that never Overlap, leaving only Adjacent and Gap between consecutive ones.
+func Classify(prev, cur ACMatch) Relation {
+	if cur.Start < prev.Start {
+		prev, cur = cur, prev
+	}
+	switch {
+	case cur.Start <= prev.End:
+		return Overlap
+	case cur.Start == prev.End+1:
+		return Adjacent
+	default:
+		return Gap
+	}
+}
@@ -0,0 +1,45 @@
+package ahocorasick
+
+import "testing"
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name      string
+		prev, cur ACMatch
+		want      Relation
+	}{
+		{"Partial overlap", ACMatch{Start: 0, End: 3}, ACMatch{Start: 2, End: 5}, Overlap},
+		{"Shared end position", ACMatch{Start: 0, End: 3}, ACMatch{Start: 3, End: 4}, Overlap},
+		{"Nested", ACMatch{Start: 0, End: 5}, ACMatch{Start: 2, End: 3}, Overlap},
+		{"Same span", ACMatch{Start: 1, End: 2}, ACMatch{PatternIndex: 1, Start: 1, End: 2}, Overlap},
+		{"Adjacent", ACMatch{Start: 0, End: 2}, ACMatch{Start: 3, End: 4}, Adjacent},
+		{"Adjacent single positions", ACMatch{Start: 4, End: 4}, ACMatch{Start: 5, End: 5}, Adjacent},
+		{"Gap of one", ACMatch{Start: 0, End: 2}, ACMatch{Start: 4, End: 6}, Gap},
+		{"Wide gap", ACMatch{Start: 0, End: 0}, ACMatch{Start: 10, End: 12}, Gap},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := Classify(tc.prev, tc.cur); got != tc.want {
+				t.Errorf("Classify(%+v, %+v) = %d; want %d", tc.prev, tc.cur, got, tc.want)
+			}
+			if got := Classify(tc.cur, tc.prev); got != tc.want {
+				t.Errorf("Classify(%+v, %+v) = %d; want %d", tc.cur, tc.prev, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestClassifyNonOverlapping(t *testing.T) {
+	ac := New([]string{"ab", "abc", "cd", "e"}, false)
+	matches := ac.FindAllNonOverlapping("abce xabe")
+	want := []Relation{Adjacent, Gap, Adjacent}
+	if len(matches) != len(want)+1 {
+		t.Fatalf("FindAllNonOverlapping = %+v; want %d matches", matches, len(want)+1)
+	}
+	for i, w := range want {
+		if got := Classify(matches[i], matches[i+1]); got != w {
+			t.Errorf("Classify(%+v, %+v) = %d; want %d", matches[i], matches[i+1], got, w)
+		}
+	}
+}
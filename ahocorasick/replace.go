@@ -0,0 +1,101 @@
+package ahocorasick
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ReplaceAll returns a copy of text where every match of pattern i is replaced by replacements[i].
+// Matches are chosen with non-overlapping leftmost-longest semantics (see FindAllNonOverlapping),
+// so when patterns overlap the earliest-starting, then longest one is replaced.
+// It panics if len(replacements) differs from the number of patterns.
+func (ac *AhoCorasick) ReplaceAll(text string, replacements []string) string {
+	if len(replacements) != len(ac.keywords) {
+		panic(fmt.Sprintf("ahocorasick: ReplaceAll got %d replacements for %d patterns",
+			len(replacements), len(ac.keywords)))
+	}
+
+	matches := ac.nonOverlappingBytes([]byte(text))
+	if len(matches) == 0 {
+		return text
+	}
+
+	var b strings.Builder
+	b.Grow(len(text))
+	last := 0
+	for _, m := range matches {
+		b.WriteString(text[last:m.Start])
+		b.WriteString(replacements[m.PatternIndex])
+		last = m.End + 1
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// ReplaceFunc returns a copy of text where every match is replaced by the string fn returns
+// for it, so the replacement can depend on which pattern matched and where. Matches are chosen
+// like in ReplaceAll, with non-overlapping leftmost-longest semantics, and passed to fn from
+// left to right as FindAllNonOverlapping reports them: in rune mode Start and End are rune
+// indices, and MatchedString(text, m) gives the matched text in either mode.
+func (ac *AhoCorasick) ReplaceFunc(text string, fn func(ACMatch) string) string {
+	matches := ac.nonOverlappingBytes([]byte(text))
+	if len(matches) == 0 {
+		return text
+	}
+
+	var b strings.Builder
+	b.Grow(len(text))
+	last, runeIdx := 0, 0 // runeIdx is the rune index of text[last] in rune mode
+	for _, m := range matches {
+		b.WriteString(text[last:m.Start])
+		reported := m
+		if ac.runes {
+			reported.Start = runeIdx + utf8.RuneCountInString(text[last:m.Start])
+			reported.End = reported.Start + utf8.RuneCountInString(text[m.Start:m.End+1]) - 1
+			runeIdx = reported.End + 1
+		}
+		b.WriteString(fn(reported))
+		last = m.End + 1
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// Highlight returns a copy of text with open inserted before and close after every match,
+// e.g. Highlight(text, "<b>", "</b>"). Matches are chosen like in ReplaceAll, with
+// leftmost-longest semantics, so the markers never nest or overlap even when the patterns do.
+// The matched text keeps its original casing.
+func (ac *AhoCorasick) Highlight(text, open, close string) string {
+	matches := ac.nonOverlappingBytes([]byte(text))
+	if len(matches) == 0 {
+		return text
+	}
+
+	var b strings.Builder
+	b.Grow(len(text) + len(matches)*(len(open)+len(close)))
+	last := 0
+	for _, m := range matches {
+		b.WriteString(text[last:m.Start])
+		b.WriteString(open)
+		b.WriteString(text[m.Start : m.End+1])
+		b.WriteString(close)
+		last = m.End + 1
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// nonOverlappingBytes returns the leftmost-longest matches in data with byte offsets,
+// which are needed to splice the text even in rune mode. Under Normalize two matches inside
+// the normalized form of one rune both cover that rune; only the first is kept.
+func (ac *AhoCorasick) nonOverlappingBytes(data []byte) []ACMatch {
+	var matches []ACMatch
+	ac.findFunc(data, LeftmostLongest, nil, false, func(m ACMatch) bool {
+		if n := len(matches); n == 0 || m.Start > matches[n-1].End {
+			matches = append(matches, m)
+		}
+		return true
+	}, nil)
+	return matches
+}
@@ -0,0 +1,226 @@
+package ahocorasick
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAhoCorasickReplaceAll(t *testing.T) {
+	type testCase struct {
+		name         string
+		patterns     []string
+		replacements []string
+		text         string
+		ignoreCase   bool
+		want         string
+	}
+	tests := []testCase{
+		{
+			name:         "Multiple keywords",
+			patterns:     []string{"cat", "dog"},
+			replacements: []string{"feline", "canine"},
+			text:         "a cat and a dog",
+			want:         "a feline and a canine",
+		},
+		{
+			name:         "Overlapping keywords use leftmost-longest",
+			patterns:     []string{"he", "she", "hers"},
+			replacements: []string{"[he]", "[she]", "[hers]"},
+			text:         "ushers",
+			want:         "u[she]rs",
+		},
+		{
+			name:         "Longest keyword wins at the same start",
+			patterns:     []string{"new", "new york"},
+			replacements: []string{"N", "NY"},
+			text:         "new york is new",
+			want:         "NY is N",
+		},
+		{
+			name:         "Redaction",
+			patterns:     []string{"secret", "password"},
+			replacements: []string{"***", "***"},
+			text:         "my password is secret",
+			want:         "my *** is ***",
+		},
+		{
+			name:         "Ignore case keeps surrounding text",
+			patterns:     []string{"Go"},
+			replacements: []string{"Golang"},
+			text:         "I like GO and go.",
+			ignoreCase:   true,
+			want:         "I like Golang and Golang.",
+		},
+		{
+			name:         "No match",
+			patterns:     []string{"cat"},
+			replacements: []string{"dog"},
+			text:         "mouse",
+			want:         "mouse",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ac := New(tc.patterns, tc.ignoreCase)
+			got := ac.ReplaceAll(tc.text, tc.replacements)
+			if got != tc.want {
+				t.Errorf("ReplaceAll(%q) got %q, want %q", tc.text, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAhoCorasickReplaceAllMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("ReplaceAll with too few replacements did not panic")
+		}
+	}()
+	New([]string{"a", "b"}, false).ReplaceAll("ab", []string{"x"})
+}
+
+func TestAhoCorasickReplaceFunc(t *testing.T) {
+	patterns := []string{"name", "city", "secret", "naïve"}
+	values := map[string]string{"name": "Ada", "city": "London"}
+
+	tests := []struct {
+		name string
+		text string
+		opts Options
+		fn   func(ac *AhoCorasick, text string) func(ACMatch) string
+		want string
+	}{
+		{
+			name: "Look up a value per pattern",
+			text: "name lives in city",
+			fn: func(ac *AhoCorasick, _ string) func(ACMatch) string {
+				return func(m ACMatch) string { return values[patterns[m.PatternIndex]] }
+			},
+			want: "Ada lives in London",
+		},
+		{
+			name: "Uppercase the matched text",
+			text: "my Secret name",
+			opts: Options{IgnoreCase: true},
+			fn: func(ac *AhoCorasick, text string) func(ACMatch) string {
+				return func(m ACMatch) string { return strings.ToUpper(ac.MatchedString(text, m)) }
+			},
+			want: "my SECRET NAME",
+		},
+		{
+			name: "Fixed-length mask",
+			text: "secret: city",
+			fn: func(ac *AhoCorasick, _ string) func(ACMatch) string {
+				return func(m ACMatch) string { return strings.Repeat("*", m.End-m.Start+1) }
+			},
+			want: "******: ****",
+		},
+		{
+			name: "Rune indices in rune mode",
+			text: "Ünï naïve NAÏVE",
+			opts: Options{IgnoreCase: true, Runes: true},
+			fn: func(ac *AhoCorasick, text string) func(ACMatch) string {
+				return func(m ACMatch) string { return "<" + ac.MatchedString(text, m) + ">" }
+			},
+			want: "Ünï <naïve> <NAÏVE>",
+		},
+		{
+			name: "No match",
+			text: "nothing here",
+			fn: func(ac *AhoCorasick, _ string) func(ACMatch) string {
+				return func(ACMatch) string { return "!" }
+			},
+			want: "nothing here",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ac := NewWithOptions(patterns, tc.opts)
+			if got := ac.ReplaceFunc(tc.text, tc.fn(ac, tc.text)); got != tc.want {
+				t.Errorf("ReplaceFunc(%q) got %q, want %q", tc.text, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestAhoCorasickReplaceFuncMatches(t *testing.T) {
+	for _, runes := range []bool{false, true} {
+		ac := NewWithOptions([]string{"he", "she", "hers", "é"}, Options{Runes: runes})
+		text := "ushers é hers"
+		var got []ACMatch
+		ac.ReplaceFunc(text, func(m ACMatch) string {
+			got = append(got, m)
+			return ""
+		})
+		if want := ac.FindAllNonOverlapping(text); !reflect.DeepEqual(got, want) {
+			t.Errorf("ReplaceFunc passed %v, want %v (runes=%v)", got, want, runes)
+		}
+	}
+}
+
+func TestAhoCorasickHighlight(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		text     string
+		opts     Options
+		want     string
+	}{
+		{
+			name:     "Overlapping patterns do not nest",
+			patterns: []string{"he", "she", "hers"},
+			text:     "ushers",
+			want:     "u<b>she</b>rs",
+		},
+		{
+			name:     "Adjacent matches",
+			patterns: []string{"ab", "cd"},
+			text:     "abcdab",
+			want:     "<b>ab</b><b>cd</b><b>ab</b>",
+		},
+		{
+			name:     "Matches at both ends",
+			patterns: []string{"x", "yz"},
+			text:     "x-yz",
+			want:     "<b>x</b>-<b>yz</b>",
+		},
+		{
+			name:     "Longest wins whatever the MatchKind",
+			patterns: []string{"a", "abc"},
+			text:     "abcd",
+			opts:     Options{MatchKind: LeftmostFirst},
+			want:     "<b>abc</b>d",
+		},
+		{
+			name:     "Keeps original casing in rune mode",
+			patterns: []string{"élan"},
+			text:     "ÉLAN vital",
+			opts:     Options{IgnoreCase: true, Runes: true},
+			want:     "<b>ÉLAN</b> vital",
+		},
+		{
+			name:     "No match",
+			patterns: []string{"cat"},
+			text:     "mouse",
+			want:     "mouse",
+		},
+		{
+			name:     "Empty text",
+			patterns: []string{"cat"},
+			text:     "",
+			want:     "",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ac := NewWithOptions(tc.patterns, tc.opts)
+			if got := ac.Highlight(tc.text, "<b>", "</b>"); got != tc.want {
+				t.Errorf("Highlight(%q) got %q, want %q", tc.text, got, tc.want)
+			}
+		})
+	}
+}
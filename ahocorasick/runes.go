@@ -0,0 +1,193 @@
+package ahocorasick
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// haystack is the byte sequence the search loops actually scan.
+// In byte mode it is the caller's data itself. In rune mode it is a copy of the data,
+// lowercased rune by rune under ignoreCase, together with the mappings back to the
+// original byte offsets and to rune indices.
+type haystack struct {
+	data  []byte
+	offs  []int // offs[i] is the original byte offset of the rune that produced data[i]; nil in byte mode
+	runes []int // runes[i] is the index of the rune that produced data[i]; nil in byte mode
+}
+
+// prepare returns the haystack to search for the given data
+func (ac *AhoCorasick) prepare(data []byte) haystack {
+	if !ac.runes {
+		return haystack{data: data}
+	}
+	h := haystack{
+		data:  make([]byte, 0, len(data)),
+		offs:  make([]int, 0, len(data)+1),
+		runes: make([]int, 0, len(data)+1),
+	}
+	ri := 0
+	for i := 0; i < len(data); ri++ {
+		n := len(h.data)
+		var size int
+		h.data, size = ac.appendFolded(h.data, data[i:])
+		for ; n < len(h.data); n++ {
+			h.offs = append(h.offs, i)
+			h.runes = append(h.runes, ri)
+		}
+		i += size
+	}
+	// One trailing entry each so the position just past a match can be mapped too
+	h.offs = append(h.offs, len(data))
+	h.runes = append(h.runes, ri)
+	return h
+}
+
+// appendFolded appends the first rune of p to dst, normalized if a Normalize function is set
+// and lowercased if ignoreCase is true, and returns the extended slice and the rune's size in p.
+// An invalid UTF-8 byte counts as a rune of its own and is copied unchanged.
+func (ac *AhoCorasick) appendFolded(dst, p []byte) ([]byte, int) {
+	r, size := utf8.DecodeRune(p)
+	switch {
+	case r == utf8.RuneError && size == 1:
+		return append(dst, p[0]), size
+	case ac.normalize != nil:
+		return appendNormalized(dst, r, ac.normalize, ac.ignoreCase), size
+	case ac.ignoreCase:
+		return utf8.AppendRune(dst, unicode.ToLower(r)), size
+	default:
+		return append(dst, p[:size]...), size
+	}
+}
+
+// containsRunes is _contains in rune mode. Rather than preparing a folded copy of the whole
+// haystack it folds one rune at a time into a buffer on the stack, so it does not allocate.
+func (ac *AhoCorasick) containsRunes(data []byte) bool {
+	var buf [utf8.UTFMax]byte
+	node := 0
+	for i := 0; i < len(data); {
+		folded, size := ac.appendFolded(buf[:0], data[i:])
+		i += size
+		for _, c := range folded {
+			node = ac.step(node, ac.normChar(c))
+			if len(ac.out[node]) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchPrefixRunes is _matchPrefix in rune mode, folding data one rune at a time so
+// that the walk stops at the first rune no pattern continues with. A pattern ending inside
+// a rune's normalized form covers that whole rune.
+func (ac *AhoCorasick) matchPrefixRunes(data []byte) (ACMatch, bool) {
+	var buf [utf8.UTFMax]byte
+	var best ACMatch
+	found := false
+	node := 0
+	for i, ri := 0, 0; i < len(data); ri++ {
+		folded, size := ac.appendFolded(buf[:0], data[i:])
+		i += size
+		for _, c := range folded {
+			if node = ac.child(node, ac.normChar(c)); node == 0 {
+				return best, found
+			}
+			if idx, ok := ac.ownPattern(node); ok && ac.endsToken(data, i) {
+				best = ACMatch{PatternIndex: idx, Start: 0, End: ri}
+				found = true
+			}
+		}
+	}
+	return best, found
+}
+
+// orig converts a match in haystack offsets to byte offsets in the original data.
+// A match ending inside the bytes one rune was normalized to is extended to the rune's end.
+func (h haystack) orig(m ACMatch) ACMatch {
+	if h.offs == nil {
+		return m
+	}
+	next := m.End + 1
+	for next < len(h.offs)-1 && h.offs[next] == h.offs[m.End] {
+		next++
+	}
+	m.Start, m.End = h.offs[m.Start], h.offs[next]-1
+	return m
+}
+
+// runeMatch converts a match in haystack offsets to rune indices in the original data
+func (h haystack) runeMatch(m ACMatch) ACMatch {
+	if h.runes == nil {
+		return m
+	}
+	m.Start, m.End = h.runes[m.Start], h.runes[m.End]
+	return m
+}
+
+// byteSpan converts m to the byte range [start, end) it covers in data,
+// reading Start and End as rune indices in rune mode.
+// ok is false if m does not lie within data.
+func (ac *AhoCorasick) byteSpan(data []byte, m ACMatch) (start, end int, ok bool) {
+	if m.Start < 0 || m.Start > m.End {
+		return 0, 0, false
+	}
+	if !ac.runes {
+		return m.Start, m.End + 1, m.End < len(data)
+	}
+	ri := 0
+	for i := 0; i < len(data); ri++ {
+		if ri == m.Start {
+			start = i
+		}
+		_, size := utf8.DecodeRune(data[i:])
+		i += size
+		if ri == m.End {
+			return start, i, true
+		}
+	}
+	return 0, 0, false
+}
+
+// foldRunes lowercases p rune by rune with unicode.ToLower.
+// Invalid UTF-8 bytes are copied unchanged.
+func foldRunes(p []byte) []byte {
+	out := make([]byte, 0, len(p))
+	for i := 0; i < len(p); {
+		r, size := utf8.DecodeRune(p[i:])
+		if r == utf8.RuneError && size == 1 {
+			out = append(out, p[i])
+		} else {
+			out = utf8.AppendRune(out, unicode.ToLower(r))
+		}
+		i += size
+	}
+	return out
+}
+
+// normalizeRunes is foldRunes for an automaton with a Normalize function: every rune of p is
+// replaced by its normalized form, lowercased if ignoreCase is true
+func normalizeRunes(p []byte, normalize func(rune) string, ignoreCase bool) []byte {
+	out := make([]byte, 0, len(p))
+	for i := 0; i < len(p); {
+		r, size := utf8.DecodeRune(p[i:])
+		if r == utf8.RuneError && size == 1 {
+			out = append(out, p[i])
+		} else {
+			out = appendNormalized(out, r, normalize, ignoreCase)
+		}
+		i += size
+	}
+	return out
+}
+
+// appendNormalized appends the normalized form of r to dst, lowercased rune by rune if
+// ignoreCase is true
+func appendNormalized(dst []byte, r rune, normalize func(rune) string, ignoreCase bool) []byte {
+	for _, nr := range normalize(r) {
+		if ignoreCase {
+			nr = unicode.ToLower(nr)
+		}
+		dst = utf8.AppendRune(dst, nr)
+	}
+	return dst
+}
@@ -0,0 +1,281 @@
+package ahocorasick
+
+import (
+	"io"
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"unicode"
+)
+
+func TestAhoCorasickRunes(t *testing.T) {
+	tests := []struct {
+		name       string
+		patterns   []string
+		text       string
+		ignoreCase bool
+		want       []ACMatch
+	}{
+		{
+			name:     "offsets count runes",
+			patterns: []string{"wörld"},
+			text:     "héllo wörld",
+			want:     []ACMatch{{PatternIndex: 0, Start: 6, End: 10}},
+		},
+		{
+			name:       "unicode case folding",
+			patterns:   []string{"école", "É"},
+			text:       "ÉCOLE",
+			ignoreCase: true,
+			want: []ACMatch{
+				{PatternIndex: 1, Start: 0, End: 0},
+				{PatternIndex: 0, Start: 0, End: 4},
+			},
+		},
+		{
+			name:       "folding changes the byte length",
+			patterns:   []string{"key"},
+			text:       "a Key", // KELVIN SIGN lowercases to 'k'
+			ignoreCase: true,
+			want:       []ACMatch{{PatternIndex: 0, Start: 2, End: 4}},
+		},
+		{
+			name:     "invalid UTF-8 counts as one rune per byte",
+			patterns: []string{"b"},
+			text:     "a\xff\xfeb",
+			want:     []ACMatch{{PatternIndex: 0, Start: 3, End: 3}},
+		},
+		{
+			name:     "case sensitive by default",
+			patterns: []string{"é"},
+			text:     "Éé",
+			want:     []ACMatch{{PatternIndex: 0, Start: 1, End: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ac := NewWithOptions(tt.patterns, Options{IgnoreCase: tt.ignoreCase, Runes: true})
+			got := ac.FindAll(tt.text)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindAll(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+			if gotBytes := ac.FindAllBytes([]byte(tt.text)); !reflect.DeepEqual(gotBytes, tt.want) {
+				t.Errorf("FindAllBytes(%q) = %v, want %v", tt.text, gotBytes, tt.want)
+			}
+			if c := ac.Count(tt.text); c != len(tt.want) {
+				t.Errorf("Count(%q) = %d, want %d", tt.text, c, len(tt.want))
+			}
+			if !ac.Contains(tt.text) {
+				t.Errorf("Contains(%q) = false, want true", tt.text)
+			}
+		})
+	}
+}
+
+func TestAhoCorasickRunesHelpers(t *testing.T) {
+	opts := Options{IgnoreCase: true, Runes: true}
+	ac := NewWithOptions([]string{"école", "ÉCOLES"}, opts)
+	text := "à l'ÉCOLE, écoles"
+
+	ms := ac.FindAllNonOverlapping(text)
+	want := []ACMatch{
+		{PatternIndex: 0, Start: 4, End: 8},
+		{PatternIndex: 1, Start: 11, End: 16},
+	}
+	if !reflect.DeepEqual(ms, want) {
+		t.Fatalf("FindAllNonOverlapping() = %v, want %v", ms, want)
+	}
+	if s := ac.MatchedString(text, ms[0]); s != "ÉCOLE" {
+		t.Errorf("MatchedString() = %q, want %q", s, "ÉCOLE")
+	}
+	if b := ac.MatchedBytes([]byte(text), ms[1]); string(b) != "écoles" {
+		t.Errorf("MatchedBytes() = %q, want %q", b, "écoles")
+	}
+	if s := ac.MatchedString(text, ACMatch{Start: 15, End: 17}); s != "" {
+		t.Errorf("MatchedString() past the end = %q, want empty", s)
+	}
+
+	if got := ac.ReplaceAll(text, []string{"[school]", "[schools]"}); got != "à l'[school], [schools]" {
+		t.Errorf("ReplaceAll() = %q", got)
+	}
+	if got := ac.FindAllWholeWord("écoless École"); !reflect.DeepEqual(got, []ACMatch{{PatternIndex: 0, Start: 8, End: 12}}) {
+		t.Errorf("FindAllWholeWord() = %v", got)
+	}
+	if got := ac.FindAllLongest("Écoles"); !reflect.DeepEqual(got, []ACMatch{
+		{PatternIndex: 0, Start: 0, End: 4},
+		{PatternIndex: 1, Start: 0, End: 5},
+	}) {
+		t.Errorf("FindAllLongest() = %v", got)
+	}
+	if got := ac.CountByPattern(text); !reflect.DeepEqual(got, []int{2, 1}) {
+		t.Errorf("CountByPattern() = %v, want [2 1]", got)
+	}
+	if m, ok := ac.MatchPrefix("ÉCOLES!"); !ok || m != (ACMatch{PatternIndex: 1, Start: 0, End: 5}) {
+		t.Errorf("MatchPrefix() = %v, %v", m, ok)
+	}
+
+	data, err := ac.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error: %v", err)
+	}
+	var loaded AhoCorasick
+	if err := loaded.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary() error: %v", err)
+	}
+	if got := loaded.FindAll(text); !reflect.DeepEqual(got, ac.FindAll(text)) {
+		t.Errorf("loaded FindAll() = %v, want %v", got, ac.FindAll(text))
+	}
+}
+
+func TestAhoCorasickRunesReader(t *testing.T) {
+	ac := NewWithOptions([]string{"ÿ", "日本", "bK"}, Options{IgnoreCase: true, Runes: true})
+	text := "日本語 bK bK Ÿ \xe6\x97"
+	want := ac.FindAll(text)
+
+	for name, wrap := range map[string]func(string) io.Reader{
+		"one byte": func(s string) io.Reader { return iotest.OneByteReader(strings.NewReader(s)) },
+		"data+EOF": func(s string) io.Reader { return iotest.DataErrReader(strings.NewReader(s)) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			var got []ACMatch
+			if err := ac.FindAllReader(wrap(text), func(m ACMatch) { got = append(got, m) }); err != nil {
+				t.Fatalf("FindAllReader() error: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("FindAllReader() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+// TestAhoCorasickRunesRandom compares rune mode with a naive search over []rune
+func TestAhoCorasickRunesRandom(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+	alphabet := []rune("aAéÉKk日")
+	randRunes := func(n int) []rune {
+		rs := make([]rune, n)
+		for i := range rs {
+			rs[i] = alphabet[rng.IntN(len(alphabet))]
+		}
+		return rs
+	}
+	lower := func(rs []rune) []rune {
+		out := make([]rune, len(rs))
+		for i, r := range rs {
+			out[i] = unicode.ToLower(r)
+		}
+		return out
+	}
+
+	for iter := 0; iter < 500; iter++ {
+		patterns := make([]string, 1+rng.IntN(4))
+		for i := range patterns {
+			patterns[i] = string(randRunes(1 + rng.IntN(3)))
+		}
+		text := randRunes(rng.IntN(30))
+
+		var want []ACMatch
+		lt := lower(text)
+		for idx, p := range patterns {
+			lp := lower([]rune(p))
+			for s := 0; s+len(lp) <= len(lt); s++ {
+				if slices.Equal(lt[s:s+len(lp)], lp) {
+					want = append(want, ACMatch{PatternIndex: idx, Start: s, End: s + len(lp) - 1})
+				}
+			}
+		}
+
+		got := NewWithOptions(patterns, Options{IgnoreCase: true, Runes: true}).FindAll(string(text))
+		cmp := func(a, b ACMatch) int {
+			if a.Start != b.Start {
+				return a.Start - b.Start
+			}
+			return a.PatternIndex - b.PatternIndex
+		}
+		slices.SortFunc(got, cmp)
+		slices.SortFunc(want, cmp)
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("FindAll(%q) with patterns %q = %v, want %v", string(text), patterns, got, want)
+		}
+	}
+}
+
+// decompose stands in for a real Unicode normalization such as NFKD for the few runes used below
+func decompose(r rune) string {
+	switch r {
+	case 'ﬁ':
+		return "fi"
+	case 'é':
+		return "e\u0301"
+	case 'É':
+		return "E\u0301"
+	}
+	return string(r)
+}
+
+func TestAhoCorasickNormalize(t *testing.T) {
+	opts := Options{IgnoreCase: true, Normalize: decompose}
+	ac := NewWithOptions([]string{"fi", "café"}, opts)
+	if !ac.runes {
+		t.Fatalf("Normalize should imply rune mode")
+	}
+
+	// Runes: ﬁ0 n1 e2 _3 C4 a5 f6 É7 _8 c9 a10 f11 e12 ◌́13
+	text := "ﬁne CafÉ cafe\u0301"
+	want := []ACMatch{
+		{PatternIndex: 0, Start: 0, End: 0},
+		{PatternIndex: 1, Start: 4, End: 7},
+		{PatternIndex: 1, Start: 9, End: 13},
+	}
+	if got := ac.FindAll(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindAll(%q) = %v, want %v", text, got, want)
+	}
+	for i, wantText := range []string{"ﬁ", "CafÉ", "cafe\u0301"} {
+		if got := ac.MatchedString(text, want[i]); got != wantText {
+			t.Errorf("MatchedString(%v) = %q, want %q", want[i], got, wantText)
+		}
+	}
+
+	var streamed []ACMatch
+	if err := ac.FindAllReader(iotest.OneByteReader(strings.NewReader(text)), func(m ACMatch) { streamed = append(streamed, m) }); err != nil {
+		t.Fatalf("FindAllReader() error: %v", err)
+	}
+	if !reflect.DeepEqual(streamed, want) {
+		t.Errorf("FindAllReader() = %v, want %v", streamed, want)
+	}
+
+	// Added patterns and Rebuild keep normalizing
+	ac.Add("ÉF")
+	if got := ac.Count("e\u0301f"); got != 1 {
+		t.Errorf("Count after Add = %d, want 1", got)
+	}
+	ac.Rebuild(false)
+	if got, want := ac.FindAll("ﬁ CAFÉ café"), []ACMatch{{PatternIndex: 0, Start: 0, End: 0}, {PatternIndex: 1, Start: 7, End: 10}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FindAll after Rebuild(false) = %v, want %v", got, want)
+	}
+
+	if !ac.Equal(ac) || ac.Equal(NewWithOptions([]string{"fi", "café", "ÉF"}, Options{Normalize: decompose})) {
+		t.Errorf("an automaton with a Normalize function should only be Equal to itself")
+	}
+}
+
+func TestAhoCorasickNormalizePartialRune(t *testing.T) {
+	// "f" and "i" each match part of the normalized "ﬁ" and are reported as covering it
+	ac := NewWithOptions([]string{"f", "i"}, Options{Normalize: decompose, MatchKind: LeftmostLongest})
+	want := []ACMatch{{PatternIndex: 0, Start: 0, End: 0}, {PatternIndex: 1, Start: 0, End: 0}}
+	if got := ac.FindAll("ﬁx"); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindAll = %v, want %v", got, want)
+	}
+
+	// Only the first of them is replaced, since both cover the same text
+	if got := ac.ReplaceAll("aﬁx", []string{"F", "I"}); got != "aFx" {
+		t.Errorf("ReplaceAll = %q, want %q", got, "aFx")
+	}
+	if got := ac.Highlight("ﬁ", "[", "]"); got != "[ﬁ]" {
+		t.Errorf("Highlight = %q, want %q", got, "[ﬁ]")
+	}
+}
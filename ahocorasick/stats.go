@@ -0,0 +1,70 @@
+package ahocorasick
+
+import "strconv"
+
+// wordSize is the size in bytes of an int and of a pointer
+const wordSize = strconv.IntSize / 8
+
+// sliceHeaderSize is the size in bytes of a slice header
+const sliceHeaderSize = 3 * wordSize
+
+// stringHeaderSize is the size in bytes of a string header
+const stringHeaderSize = 2 * wordSize
+
+// Rough cost of a small Go map from byte to int: the header plus one bucket's
+// worth of key, value and metadata per entry
+const (
+	mapHeaderSize = 48
+	mapEntrySize  = 16
+)
+
+// ACStats describes the size of a compiled automaton
+type ACStats struct {
+	Nodes    int // number of trie nodes, including the root
+	Patterns int // number of patterns, including duplicates
+	// MemoryBytes estimates the heap memory held by the automaton: transition storage,
+	// failure links, depths, out lists, keyword copies and the retained patterns.
+	// It counts slice and map payloads, not allocator overhead, so treat it as an
+	// order of magnitude.
+	MemoryBytes int
+}
+
+// Stats returns the size of the automaton in O(nodes) time
+func (ac *AhoCorasick) Stats() ACStats {
+	n := ac.numNodes()
+	st := ACStats{
+		Nodes:    n,
+		Patterns: len(ac.keywords),
+	}
+
+	mem := 0
+	switch ac.backend {
+	case SparseMap:
+		mem += n * wordSize // map pointers
+		for _, e := range ac.edges {
+			if e != nil {
+				mem += mapHeaderSize + len(e)*mapEntrySize
+			}
+		}
+	case DoubleArray:
+		mem += 4 * (n + 4*len(ac.check)) // base, check, target and the free list
+	default:
+		mem += n * 256 * wordSize
+	}
+
+	mem += 2 * n * wordSize // fail and depth
+	mem += n * sliceHeaderSize
+	for _, o := range ac.out {
+		mem += cap(o) * wordSize
+	}
+	mem += len(ac.keywords) * sliceHeaderSize
+	for _, k := range ac.keywords {
+		mem += cap(k)
+	}
+	mem += len(ac.patterns) * stringHeaderSize
+	for _, p := range ac.patterns {
+		mem += len(p)
+	}
+	st.MemoryBytes = mem
+	return st
+}
@@ -0,0 +1,42 @@
+package ahocorasick
+
+import "testing"
+
+func TestAhoCorasickStats(t *testing.T) {
+	patterns := []string{"he", "she", "his", "hers"}
+
+	dense := NewWithOptions(patterns, Options{}).Stats()
+	sparse := NewWithOptions(patterns, Options{Backend: SparseMap}).Stats()
+	double := NewWithOptions(patterns, Options{Backend: DoubleArray}).Stats()
+
+	// root, h, he, her, hers, hi, his, s, sh, she
+	for _, st := range []ACStats{dense, sparse, double} {
+		if st.Nodes != 10 || st.Patterns != 4 {
+			t.Errorf("Stats() = %+v; want 10 nodes and 4 patterns", st)
+		}
+	}
+	if want := 10 * 256 * wordSize; dense.MemoryBytes < want {
+		t.Errorf("ArrayOfArrays MemoryBytes = %d; want at least %d for the transition table", dense.MemoryBytes, want)
+	}
+	if sparse.MemoryBytes >= dense.MemoryBytes {
+		t.Errorf("SparseMap MemoryBytes = %d; want less than ArrayOfArrays %d", sparse.MemoryBytes, dense.MemoryBytes)
+	}
+	if double.MemoryBytes >= dense.MemoryBytes {
+		t.Errorf("DoubleArray MemoryBytes = %d; want less than ArrayOfArrays %d", double.MemoryBytes, dense.MemoryBytes)
+	}
+	// The double array's slots span at least one byte range, so it beats the maps only on larger tries
+	words := generateDictionary(2000)
+	sparseDict := NewWithOptions(words, Options{Backend: SparseMap}).Stats()
+	if doubleDict := NewWithOptions(words, Options{Backend: DoubleArray}).Stats(); doubleDict.MemoryBytes >= sparseDict.MemoryBytes {
+		t.Errorf("DoubleArray MemoryBytes = %d for a dictionary; want less than SparseMap %d", doubleDict.MemoryBytes, sparseDict.MemoryBytes)
+	}
+
+	// Adding a pattern grows the estimate
+	ac := New(patterns, false)
+	before := ac.Stats()
+	ac.Add("hello")
+	after := ac.Stats()
+	if after.Nodes != before.Nodes+3 || after.Patterns != 5 || after.MemoryBytes <= before.MemoryBytes {
+		t.Errorf("Stats after Add = %+v; before %+v", after, before)
+	}
+}
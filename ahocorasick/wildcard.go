@@ -0,0 +1,48 @@
+package ahocorasick
+
+import "github.com/notJoon/searcher/byteclass"
+
+// walkKeyword follows the trie path spelling keyword k from node and calls fn with the node
+// it ends at. At a sentinel byte of Options.Wildcards the path branches into one path per
+// byte of the sentinel's set, so fn is called once per completed path. Missing nodes are
+// created if create is true; otherwise the paths must already be in the trie.
+func (ac *AhoCorasick) walkKeyword(node int, k []byte, create bool, fn func(end int)) {
+	for i, c := range k {
+		if set, ok := ac.wildcards[c]; ok {
+			for _, b := range ac.wildcardBytes(set) {
+				ac.walkKeyword(ac.walkEdge(node, b, create), k[i+1:], create, fn)
+			}
+			return
+		}
+		node = ac.walkEdge(node, c, create)
+	}
+	fn(node)
+}
+
+// walkEdge returns the trie child of node on byte c, creating it if create is true
+func (ac *AhoCorasick) walkEdge(node int, c byte, create bool) int {
+	nx := ac.child(node, c)
+	if nx == 0 && create {
+		nx = ac.addNode(node)
+		ac.setChild(node, c, nx)
+	}
+	return nx
+}
+
+// wildcardBytes returns the distinct bytes of set after the case folding applied to text
+// bytes, in increasing order, i.e. the trie edges a sentinel with this set branches on
+func (ac *AhoCorasick) wildcardBytes(set byteclass.Set) []byte {
+	var folded byteclass.Set
+	var out []byte
+	for c := 0; c < 256; c++ {
+		if set.Has(byte(c)) {
+			folded = folded.Add(ac.normChar(byte(c)))
+		}
+	}
+	for c := 0; c < 256; c++ {
+		if folded.Has(byte(c)) {
+			out = append(out, byte(c))
+		}
+	}
+	return out
+}
@@ -0,0 +1,191 @@
+package ahocorasick
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/notJoon/searcher/byteclass"
+)
+
+func TestAhoCorasickWildcards(t *testing.T) {
+	digits := map[byte]byteclass.Set{'#': byteclass.Range('0', '9')}
+	type testCase struct {
+		name        string
+		patterns    []string
+		text        string
+		opts        Options
+		wantMatches []ACMatch
+	}
+	tests := []testCase{
+		{
+			name:     "Digit positions",
+			patterns: []string{"error:###"},
+			text:     "error:404 error:4x4 error:12",
+			opts:     Options{Wildcards: digits},
+			wantMatches: []ACMatch{
+				{PatternIndex: 0, Start: 0, End: 8},
+			},
+		},
+		{
+			name:     "Any byte",
+			patterns: []string{"a?c"},
+			text:     "abc a c a\x00c ac",
+			opts:     Options{Wildcards: map[byte]byteclass.Set{'?': byteclass.Any()}},
+			wantMatches: []ACMatch{
+				{PatternIndex: 0, Start: 0, End: 2},
+				{PatternIndex: 0, Start: 4, End: 6},
+				{PatternIndex: 0, Start: 8, End: 10},
+			},
+		},
+		{
+			name:     "Failure links across wildcard edges",
+			patterns: []string{"a#b", "1b", "b#"},
+			text:     "a1b2",
+			opts:     Options{Wildcards: digits},
+			wantMatches: []ACMatch{
+				{PatternIndex: 0, Start: 0, End: 2},
+				{PatternIndex: 1, Start: 1, End: 2},
+				{PatternIndex: 2, Start: 2, End: 3},
+			},
+		},
+		{
+			name:     "Literal patterns are unaffected",
+			patterns: []string{"id=#", "id="},
+			text:     "id=7",
+			opts:     Options{Wildcards: digits},
+			wantMatches: []ACMatch{
+				{PatternIndex: 1, Start: 0, End: 2},
+				{PatternIndex: 0, Start: 0, End: 3},
+			},
+		},
+		{
+			name:     "Sentinel does not match itself",
+			patterns: []string{"v#"},
+			text:     "v# v9",
+			opts:     Options{Wildcards: digits},
+			wantMatches: []ACMatch{
+				{PatternIndex: 0, Start: 3, End: 4},
+			},
+		},
+		{
+			name:     "Set bytes are folded under IgnoreCase",
+			patterns: []string{"x%"},
+			text:     "XA xb x1",
+			opts:     Options{IgnoreCase: true, Wildcards: map[byte]byteclass.Set{'%': byteclass.Range('A', 'Z')}},
+			wantMatches: []ACMatch{
+				{PatternIndex: 0, Start: 0, End: 1},
+				{PatternIndex: 0, Start: 3, End: 4},
+			},
+		},
+		{
+			name:     "Leftmost-longest",
+			patterns: []string{"#", "##"},
+			text:     "123",
+			opts:     Options{Wildcards: digits, MatchKind: LeftmostLongest},
+			wantMatches: []ACMatch{
+				{PatternIndex: 1, Start: 0, End: 1},
+				{PatternIndex: 0, Start: 2, End: 2},
+			},
+		},
+		{
+			name:        "Empty set never matches",
+			patterns:    []string{"a_b"},
+			text:        "a_b ab",
+			opts:        Options{Wildcards: map[byte]byteclass.Set{'_': {}}},
+			wantMatches: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, backend := range []Backend{ArrayOfArrays, SparseMap, DoubleArray} {
+				opts := tc.opts
+				opts.Backend = backend
+				ac := NewWithOptions(tc.patterns, opts)
+				if got := ac.FindAll(tc.text); !reflect.DeepEqual(got, tc.wantMatches) {
+					t.Errorf("backend %d: FindAll(%q) = %v; want %v", backend, tc.text, got, tc.wantMatches)
+				}
+			}
+		})
+	}
+}
+
+func TestAhoCorasickWildcardsUpdates(t *testing.T) {
+	digits := map[byte]byteclass.Set{'#': byteclass.Range('0', '9')}
+	ac := NewWithOptions([]string{"e#", "x"}, Options{Wildcards: digits})
+
+	// Add and Remove recompute the out lists by walking every path of each pattern
+	if idx := ac.Add("#x"); idx != 2 {
+		t.Fatalf("Add returned %d; want 2", idx)
+	}
+	ac.Remove(1)
+	want := []ACMatch{
+		{PatternIndex: 0, Start: 0, End: 1},
+		{PatternIndex: 2, Start: 2, End: 3},
+	}
+	if got := ac.FindAll("e19x"); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindAll after Add and Remove = %v; want %v", got, want)
+	}
+
+	// Rebuild keeps the wildcards
+	ac.Rebuild(true)
+	if got := ac.FindAll("E19X"); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindAll after Rebuild = %v; want %v", got, want)
+	}
+
+	if got := ac.KeywordsWithPrefix("e4"); !slices.Equal(got, []string{"e#"}) {
+		t.Errorf("KeywordsWithPrefix(%q) = %q; want %q", "e4", got, []string{"e#"})
+	}
+}
+
+// TestAhoCorasickWildcardsRandom checks every Standard match against a direct
+// comparison of each pattern at each position
+func TestAhoCorasickWildcardsRandom(t *testing.T) {
+	rng := rand.New(rand.NewPCG(5, 6))
+	sets := map[byte]byteclass.Set{'#': byteclass.SetOf("ab"), '?': byteclass.SetOf("bc")}
+	gen := func(alphabet string, n int) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = alphabet[rng.IntN(len(alphabet))]
+		}
+		return string(b)
+	}
+	matchesAt := func(p, text string, i int) bool {
+		if i+len(p) > len(text) {
+			return false
+		}
+		for j := 0; j < len(p); j++ {
+			if set, ok := sets[p[j]]; ok {
+				if !set.Has(text[i+j]) {
+					return false
+				}
+			} else if p[j] != text[i+j] {
+				return false
+			}
+		}
+		return true
+	}
+
+	for range 500 {
+		patterns := make([]string, 1+rng.IntN(4))
+		for i := range patterns {
+			patterns[i] = gen("abc#?", 1+rng.IntN(4))
+		}
+		text := gen("abc", rng.IntN(30))
+
+		var want []ACMatch
+		for i := range text {
+			for idx, p := range patterns {
+				if matchesAt(p, text, i) {
+					want = append(want, ACMatch{PatternIndex: idx, Start: i, End: i + len(p) - 1})
+				}
+			}
+		}
+		got := NewWithOptions(patterns, Options{Wildcards: sets}).FindAllSorted(text)
+		if !reflect.DeepEqual(got, sortMatches(want)) {
+			t.Fatalf("patterns %q: FindAllSorted(%q) = %v; want %v", patterns, text, got, want)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package ahocorasick
+
+// IsWordByte reports whether c is a word character, i.e. one of [A-Za-z0-9_]
+func IsWordByte(c byte) bool {
+	return c == '_' ||
+		(c >= 'a' && c <= 'z') ||
+		(c >= 'A' && c <= 'Z') ||
+		(c >= '0' && c <= '9')
+}
+
+// FindAllWholeWord finds the pattern matches in text that are not preceded or followed
+// by a word character (as defined by IsWordByte), like grep -w.
+// Under the leftmost match kinds only whole-word matches compete, so a partial-word
+// match never hides a whole-word one.
+func (ac *AhoCorasick) FindAllWholeWord(text string) []ACMatch {
+	return ac.FindAllWholeWordBytes([]byte(text))
+}
+
+// FindAllWholeWordBytes finds the pattern matches in the byte slice that are not preceded
+// or followed by a word character
+func (ac *AhoCorasick) FindAllWholeWordBytes(data []byte) []ACMatch {
+	return ac.FindAllTokensBytes(data, func(c byte) bool { return !IsWordByte(c) })
+}
+
+// FindAllTokens finds the pattern matches in text that stand as tokens of their own: the byte
+// just before Start and the byte just after End must each be a text edge or satisfy isBoundary.
+// FindAllWholeWord is the special case where every byte other than a word character is a boundary.
+// Under the leftmost match kinds only token matches compete, as in FindAllWholeWord.
+// In rune mode the bytes tested are those around the matched runes in the original text.
+// isBoundary applies to this call only, on top of any Options.TokenBoundary; set that option
+// instead to have Contains, Count, the Scanner and every other search honour the boundaries.
+func (ac *AhoCorasick) FindAllTokens(text string, isBoundary func(byte) bool) []ACMatch {
+	return ac.FindAllTokensBytes([]byte(text), isBoundary)
+}
+
+// FindAllTokensBytes finds the pattern matches in the byte slice whose neighbouring bytes are
+// text edges or satisfy isBoundary
+func (ac *AhoCorasick) FindAllTokensBytes(data []byte, isBoundary func(byte) bool) []ACMatch {
+	return ac._findAll(data, ac.kind, func(m ACMatch) bool {
+		return isBounded(data, m, isBoundary)
+	})
+}
+
+// isBounded reports whether the bytes just outside m are text edges or satisfy isBoundary
+func isBounded(data []byte, m ACMatch, isBoundary func(byte) bool) bool {
+	return (m.Start == 0 || isBoundary(data[m.Start-1])) &&
+		(m.End == len(data)-1 || isBoundary(data[m.End+1]))
+}
@@ -0,0 +1,267 @@
+package ahocorasick
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestAhoCorasickWholeWord(t *testing.T) {
+	type testCase struct {
+		name        string
+		patterns    []string
+		text        string
+		opts        Options
+		wantMatches []ACMatch
+	}
+	tests := []testCase{
+		{
+			name:     "Skips matches inside words",
+			patterns: []string{"art", "start"},
+			text:     "start art party",
+			wantMatches: []ACMatch{
+				{PatternIndex: 1, Start: 0, End: 4},
+				{PatternIndex: 0, Start: 6, End: 8},
+			},
+		},
+		{
+			name:     "Punctuation and text edges are boundaries",
+			patterns: []string{"cat"},
+			text:     "cat,(cat)_cat",
+			wantMatches: []ACMatch{
+				{PatternIndex: 0, Start: 0, End: 2},
+				{PatternIndex: 0, Start: 5, End: 7},
+			},
+		},
+		{
+			name:     "Ignore case",
+			patterns: []string{"Go"},
+			text:     "GO gopher go",
+			opts:     Options{IgnoreCase: true},
+			wantMatches: []ACMatch{
+				{PatternIndex: 0, Start: 0, End: 1},
+				{PatternIndex: 0, Start: 10, End: 11},
+			},
+		},
+		{
+			name:     "Leftmost-longest falls back to a whole word",
+			patterns: []string{"new", "new york"},
+			text:     "new yorker",
+			opts:     Options{MatchKind: LeftmostLongest},
+			wantMatches: []ACMatch{
+				{PatternIndex: 0, Start: 0, End: 2},
+			},
+		},
+		{
+			name:        "No whole-word match",
+			patterns:    []string{"art"},
+			text:        "start party",
+			wantMatches: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ac := NewWithOptions(tc.patterns, tc.opts)
+
+			got := ac.FindAllWholeWord(tc.text)
+			if !reflect.DeepEqual(got, tc.wantMatches) {
+				t.Errorf("FindAllWholeWord(%q) got %v, want %v", tc.text, got, tc.wantMatches)
+			}
+
+			gotBytes := ac.FindAllWholeWordBytes([]byte(tc.text))
+			if !reflect.DeepEqual(gotBytes, tc.wantMatches) {
+				t.Errorf("FindAllWholeWordBytes(%q) got %v, want %v", tc.text, gotBytes, tc.wantMatches)
+			}
+		})
+	}
+}
+
+func TestAhoCorasickTokens(t *testing.T) {
+	isSep := func(c byte) bool { return c == ',' || c == ';' || c == ' ' }
+	tests := []struct {
+		name        string
+		patterns    []string
+		text        string
+		opts        Options
+		isBoundary  func(byte) bool
+		wantMatches []ACMatch
+	}{
+		{
+			name:       "Custom separators",
+			patterns:   []string{"ab", "b"},
+			text:       "ab,b;xab b-ab",
+			isBoundary: isSep,
+			wantMatches: []ACMatch{
+				{PatternIndex: 0, Start: 0, End: 1},
+				{PatternIndex: 1, Start: 3, End: 3},
+			},
+		},
+		{
+			name:       "Word characters can be boundaries",
+			patterns:   []string{"key"},
+			text:       "_key_ akey",
+			isBoundary: func(c byte) bool { return c == '_' },
+			wantMatches: []ACMatch{
+				{PatternIndex: 0, Start: 1, End: 3},
+			},
+		},
+		{
+			name:       "Leftmost-first only considers tokens",
+			patterns:   []string{"a", "a-b"},
+			text:       "a-b",
+			opts:       Options{MatchKind: LeftmostFirst},
+			isBoundary: isSep,
+			wantMatches: []ACMatch{
+				{PatternIndex: 1, Start: 0, End: 2},
+			},
+		},
+		{
+			name:       "Rune mode tests the original bytes",
+			patterns:   []string{"été"},
+			text:       "un ÉTÉ,été!",
+			opts:       Options{IgnoreCase: true, Runes: true},
+			isBoundary: isSep,
+			wantMatches: []ACMatch{
+				{PatternIndex: 0, Start: 3, End: 5},
+			},
+		},
+		{
+			name:        "Nothing is a boundary",
+			patterns:    []string{"x"},
+			text:        "x x",
+			isBoundary:  func(byte) bool { return false },
+			wantMatches: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ac := NewWithOptions(tc.patterns, tc.opts)
+
+			got := ac.FindAllTokens(tc.text, tc.isBoundary)
+			if !reflect.DeepEqual(got, tc.wantMatches) {
+				t.Errorf("FindAllTokens(%q) got %v, want %v", tc.text, got, tc.wantMatches)
+			}
+
+			gotBytes := ac.FindAllTokensBytes([]byte(tc.text), tc.isBoundary)
+			if !reflect.DeepEqual(gotBytes, tc.wantMatches) {
+				t.Errorf("FindAllTokensBytes(%q) got %v, want %v", tc.text, gotBytes, tc.wantMatches)
+			}
+		})
+	}
+}
+
+func TestAhoCorasickTokenBoundary(t *testing.T) {
+	isSep := func(c byte) bool { return c == ',' || c == ' ' }
+	tests := []struct {
+		name        string
+		patterns    []string
+		text        string
+		opts        Options
+		wantMatches []ACMatch
+	}{
+		{
+			name:     "Standard keeps nested tokens only",
+			patterns: []string{"ab", "b", "abc"},
+			text:     "ab,b abc xb",
+			wantMatches: []ACMatch{
+				{PatternIndex: 0, Start: 0, End: 1},
+				{PatternIndex: 1, Start: 3, End: 3},
+				{PatternIndex: 2, Start: 5, End: 7},
+			},
+		},
+		{
+			name:     "Leftmost-longest falls back to a token",
+			patterns: []string{"new", "new york"},
+			text:     "new yorker",
+			opts:     Options{MatchKind: LeftmostLongest},
+			wantMatches: []ACMatch{
+				{PatternIndex: 0, Start: 0, End: 2},
+			},
+		},
+		{
+			name:     "Rune mode with Normalize",
+			patterns: []string{"fi"},
+			text:     "ﬁ ﬁx,Fi",
+			opts: Options{IgnoreCase: true, Normalize: func(r rune) string {
+				if r == 'ﬁ' {
+					return "fi"
+				}
+				return string(r)
+			}},
+			wantMatches: []ACMatch{
+				{PatternIndex: 0, Start: 0, End: 0},
+				{PatternIndex: 0, Start: 5, End: 6},
+			},
+		},
+		{
+			name:        "No token",
+			patterns:    []string{"art"},
+			text:        "start party",
+			wantMatches: nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := tc.opts
+			opts.TokenBoundary = isSep
+			ac := NewWithOptions(tc.patterns, opts)
+
+			if got := ac.FindAll(tc.text); !reflect.DeepEqual(got, tc.wantMatches) {
+				t.Errorf("FindAll(%q) got %v, want %v", tc.text, got, tc.wantMatches)
+			}
+			if got := NewWithOptions(tc.patterns, tc.opts).FindAllTokens(tc.text, isSep); !reflect.DeepEqual(got, tc.wantMatches) {
+				t.Errorf("FindAllTokens(%q) without the option got %v, want %v", tc.text, got, tc.wantMatches)
+			}
+			var funcs []ACMatch
+			ac.FindAllFunc(tc.text, func(m ACMatch) bool {
+				funcs = append(funcs, m)
+				return true
+			})
+			if !reflect.DeepEqual(funcs, tc.wantMatches) {
+				t.Errorf("FindAllFunc(%q) got %v, want %v", tc.text, funcs, tc.wantMatches)
+			}
+			if got, want := ac.Contains(tc.text), len(tc.wantMatches) > 0; got != want {
+				t.Errorf("Contains(%q) = %v, want %v", tc.text, got, want)
+			}
+			if got, want := ac.Count(tc.text), len(tc.wantMatches); got != want {
+				t.Errorf("Count(%q) = %d, want %d", tc.text, got, want)
+			}
+			counts := make([]int, len(tc.patterns))
+			for _, m := range tc.wantMatches {
+				counts[m.PatternIndex]++
+			}
+			if got := ac.CountByPattern(tc.text); !slices.Equal(got, counts) {
+				t.Errorf("CountByPattern(%q) = %v, want %v", tc.text, got, counts)
+			}
+
+			// The Standard matches decide the searches that ignore the MatchKind
+			opts.MatchKind = Standard
+			std := NewWithOptions(tc.patterns, opts).FindAll(tc.text)
+			seen := make([]bool, len(tc.patterns))
+			var longest []ACMatch
+			var prefix ACMatch
+			hasPrefix := false
+			for _, m := range std {
+				seen[m.PatternIndex] = true
+				if n := len(longest); n == 0 || longest[n-1].End != m.End {
+					longest = append(longest, m)
+				}
+				if m.Start == 0 && (!hasPrefix || m.End > prefix.End) {
+					prefix, hasPrefix = m, true
+				}
+			}
+			if got := ac.MatchedPatterns(tc.text); !slices.Equal(got, seen) {
+				t.Errorf("MatchedPatterns(%q) = %v, want %v", tc.text, got, seen)
+			}
+			if got := ac.FindAllLongest(tc.text); !reflect.DeepEqual(got, longest) {
+				t.Errorf("FindAllLongest(%q) got %v, want %v", tc.text, got, longest)
+			}
+			if got, ok := ac.MatchPrefix(tc.text); got != prefix || ok != hasPrefix {
+				t.Errorf("MatchPrefix(%q) = %v, %v; want %v, %v", tc.text, got, ok, prefix, hasPrefix)
+			}
+		})
+	}
+}
@@ -1,84 +1,394 @@
 package boyermoore
 
+import (
+	"encoding/binary"
+	"errors"
+	"slices"
+	"strings"
+	"unicode"
+)
+
 // BoyerMoore represents a pattern matcher using the Boyer-Moore algorithm.
 // It contains the pattern, case sensitivity option, and precomputed
 // bad character & good suffix shift tables.
+//
+// A BoyerMoore is read-only once built: every table, including the one for the reversed
+// pattern, is computed up front, so any number of goroutines may search with the same
+// matcher at once. Only Reset changes it and must not run concurrently with searches;
+// a custom Options.Fold is called from every searching goroutine.
 type BoyerMoore struct {
-	pat        []byte   // pattern (converted to lowercase if ignoreCase is true)
-	ignoreCase bool     // case insensitivity flag
-	bcShift    [256]int // bad character shift table
-	gsShift    []int    // good suffix shift table
+	pat        []byte          // pattern (converted to lowercase if ignoreCase is true)
+	orig       string          // pattern as given, returned by Pattern; empty when pat holds it verbatim
+	ignoreCase bool            // case insensitivity flag
+	fold       func(rune) rune // rune folding applied to pattern and text, nil for ASCII-only folding
+	customFold bool            // fold was supplied through Options.Fold
+	suffix     []int           // scratch buffer for the good suffix preprocessing, kept for Reset
+	horspool   bool            // shift by the byte under the last pattern position only
+	bcShift    [256]int        // bad character shift table (over pat[:len(pat)-1] in Horspool mode)
+	class      *[256]uint8     // CompactTable: class[c] indexes classShift; nil to index bcShift directly
+	classShift []int           // CompactTable: bcShift of each byte class
+	compact    bool            // Options.CompactTable
+	gsShift    []int           // good suffix shift table, nil in Horspool mode
+
+	rev *BoyerMoore // matcher for the reversed pattern, used for right-to-left search
+}
+
+// Match represents the span of a pattern match found in text.
+type Match struct {
+	Start int // start index of the match
+	End   int // end index of the match (inclusive)
+}
+
+// Options configures a BoyerMoore matcher created by NewWithOptions.
+type Options struct {
+	// IgnoreCase makes the search case-insensitive.
+	IgnoreCase bool
+
+	// UnicodeFold folds the pattern and the text rune by rune with unicode.ToLower
+	// when IgnoreCase is set, so that e.g. "café" matches "CAFÉ".
+	// By default only ASCII letters are folded, which lets the search compare the
+	// text in place. With UnicodeFold every search first builds a folded copy of the
+	// text plus a table mapping it back to original offsets, costing an allocation
+	// proportional to the text and roughly an extra pass over it.
+	UnicodeFold bool
+
+	// Fold, if set, replaces the case folding applied when IgnoreCase is set.
+	// It is called on every rune of the pattern and of the text, so it can implement
+	// locale rules such as Turkish, where 'I' folds to 'ı' and 'İ' to 'i' instead of
+	// the ASCII mapping 'I' to 'i'. Bytes of the text are then only compared after
+	// folding, never ASCII-lowercased on top. Fold takes precedence over UnicodeFold
+	// and has the same per-search cost.
+	Fold func(rune) rune
+
+	// Horspool selects the Boyer-Moore-Horspool variant: only the bad character
+	// table is built, and after each comparison the window moves by the shift of
+	// the text byte under the last pattern position. This skips the good suffix
+	// preprocessing and is often faster on natural-language text, but the worst
+	// case degrades from linear to O(len(text) × len(pattern)) on repetitive input.
+	Horspool bool
+
+	// CompactTable looks bad character shifts up through a 256-byte translation table
+	// into one entry per distinct pattern byte, plus one shared by every other byte,
+	// instead of a 256-entry table of ints. It suits small alphabets such as DNA, where
+	// a pattern over "ACGT" needs a five-entry table, and unlike a declared alphabet it
+	// stays correct for stray bytes such as 'N'. The shifts are the same, so results do
+	// not change. Where the full table already stays in the L1 cache, the extra lookup
+	// makes searches some 10-20% slower (see BenchmarkCompactTable); the compact table only
+	// pays off when the cache is contended, e.g. by many matchers searching in turn.
+	CompactTable bool
 }
 
+// ErrEmptyPattern is returned by NewWithError for an empty pattern.
+var ErrEmptyPattern = errors.New("boyermoore: empty pattern")
+
 // New creates a new BoyerMoore matcher for the given pattern.
 // If ignoreCase is true, the search will be case-insensitive.
+// An empty pattern gives a matcher that never matches.
 func New(pattern string, ignoreCase bool) *BoyerMoore {
-	if len(pattern) == 0 {
-		return &BoyerMoore{
-			pat:        make([]byte, 0),
-			ignoreCase: ignoreCase,
-			bcShift:    [256]int{},
-			gsShift:    make([]int, 0),
-		}
+	return NewWithOptions(pattern, Options{IgnoreCase: ignoreCase})
+}
+
+// NewWithError is like New but returns ErrEmptyPattern instead of a matcher
+// that never matches when the pattern is empty.
+func NewWithError(pattern string, ignoreCase bool) (*BoyerMoore, error) {
+	if pattern == "" {
+		return nil, ErrEmptyPattern
 	}
-	p := []byte(pattern)
+	return New(pattern, ignoreCase), nil
+}
 
-	// Convert pattern to lowercase if case-insensitive search is requested
+// NewFromBytes is like New but takes the pattern as a byte slice, without copying it.
+// The matcher takes ownership of pattern: it is lowercased in place if ignoreCase is true,
+// and Reset may later overwrite it, so the caller must not use or modify it afterwards.
+// Only under ignoreCase is a string copy of the pattern kept, for Pattern to return.
+func NewFromBytes(pattern []byte, ignoreCase bool) *BoyerMoore {
+	bm := newMatcher(Options{IgnoreCase: ignoreCase})
 	if ignoreCase {
-		for i := 0; i < len(p); i++ {
-			c := p[i]
-			// Consider only ASCII range ('A'~'Z')
-			if c >= 'A' && c <= 'Z' {
-				p[i] = c + ('a' - 'A')
-			}
-		}
+		bm.orig = string(pattern)
 	}
+	bm.setPatternBytes(pattern)
+	return bm
+}
 
-	bm := &BoyerMoore{
-		pat:        p,
+// NewWithOptions creates a new BoyerMoore matcher for the given pattern
+// configured by opts.
+func NewWithOptions(pattern string, opts Options) *BoyerMoore {
+	bm := newMatcher(opts)
+	bm.setPattern(pattern)
+	return bm
+}
+
+// newMatcher returns a matcher configured by opts, without a pattern yet.
+func newMatcher(opts Options) *BoyerMoore {
+	ignoreCase := opts.IgnoreCase
+	var fold func(rune) rune
+	switch {
+	case ignoreCase && opts.Fold != nil:
+		fold = opts.Fold
+	case ignoreCase && opts.UnicodeFold:
+		fold = unicode.ToLower
+	}
+	customFold := ignoreCase && opts.Fold != nil
+
+	return &BoyerMoore{
 		ignoreCase: ignoreCase,
-		gsShift:    make([]int, len(p)),
+		fold:       fold,
+		customFold: customFold,
+		horspool:   opts.Horspool,
+		compact:    opts.CompactTable,
 	}
+}
 
-	bm.buildBadCharShift()
-	bm.buildGoodSuffixShift()
+// Reset replaces the matcher's pattern, keeping its options, and recomputes the shift
+// tables in place. The pattern, table and scratch buffers are reused when they are large
+// enough, so compiling many patterns through one matcher with Reset allocates far less
+// than calling New for each. Reset must not be called concurrently with searches on bm.
+func (bm *BoyerMoore) Reset(pattern string) {
+	bm.setPattern(pattern)
+}
 
-	return bm
+// Pattern returns the pattern the matcher searches for, as it was given to New or Reset,
+// before any case folding.
+func (bm *BoyerMoore) Pattern() string {
+	if bm.orig == "" {
+		return string(bm.pat)
+	}
+	return bm.orig
+}
+
+// PatternLen returns the length in bytes of the pattern the matcher searches for, which is
+// the length of every match. Under UnicodeFold (or a custom Fold) it is the length of the
+// folded pattern instead, and a match spans the original bytes the folded text came from,
+// which may be more or fewer.
+func (bm *BoyerMoore) PatternLen() int {
+	return len(bm.pat)
+}
+
+// IgnoreCase reports whether the matcher was configured for case-insensitive search.
+func (bm *BoyerMoore) IgnoreCase() bool {
+	return bm.ignoreCase
+}
+
+// Equal reports whether bm and other find the same matches in every text: they have the
+// same normalized pattern (so "Go" and "go" are equal under IgnoreCase), the same IgnoreCase
+// setting and the same folding. Horspool mode and CompactTable are ignored because they only
+// change how fast matches are found. A matcher using a custom Options.Fold is only Equal to itself, since
+// functions cannot be compared.
+func (bm *BoyerMoore) Equal(other *BoyerMoore) bool {
+	if bm == other {
+		return true
+	}
+	if bm == nil || other == nil || bm.customFold || other.customFold {
+		return false
+	}
+	return bm.ignoreCase == other.ignoreCase &&
+		(bm.fold == nil) == (other.fold == nil) &&
+		slices.Equal(bm.pat, other.pat)
+}
+
+// setPattern normalizes pattern into bm.pat and builds the forward and reversed tables,
+// reusing the buffers already held by bm.
+func (bm *BoyerMoore) setPattern(pattern string) {
+	bm.orig = pattern
+	bm.setPatternBytes(append(bm.pat[:0], pattern...))
 }
 
-// FindAll returns all starting indices where the pattern matches in the text.
+// setPatternBytes is setPattern for a pattern bm may keep and modify as its own.
+func (bm *BoyerMoore) setPatternBytes(pattern []byte) {
+	// Convert pattern to lowercase if case-insensitive search is requested
+	if bm.fold != nil {
+		bm.pat = foldBytes(pattern, bm.fold)
+	} else {
+		bm.pat = pattern
+		if bm.ignoreCase {
+			for i := 0; i < len(bm.pat); i++ {
+				c := bm.pat[i]
+				// Consider only ASCII range ('A'~'Z')
+				if c >= 'A' && c <= 'Z' {
+					bm.pat[i] = c + ('a' - 'A')
+				}
+			}
+		}
+	}
+	if len(bm.pat) == 0 {
+		bm.gsShift = bm.gsShift[:0]
+		return
+	}
+	bm.suffix = bm.buildTables(bm.suffix)
+
+	// Reversed pattern tables for right-to-left search
+	if bm.rev == nil {
+		bm.rev = &BoyerMoore{
+			ignoreCase: bm.ignoreCase,
+			horspool:   bm.horspool,
+			compact:    bm.compact,
+		}
+	}
+	r := append(bm.rev.pat[:0], bm.pat...)
+	slices.Reverse(r)
+	bm.rev.pat = r
+	bm.suffix = bm.rev.buildTables(bm.suffix)
+}
+
+// FindAll returns the starting indices of all non-overlapping matches of the pattern in the text.
 // Returns an empty slice if no matches are found.
 func (bm *BoyerMoore) FindAll(txt string) []int {
-	return bm._findAll([]byte(txt))
+	return bm._findAll([]byte(txt), 0, false, 0)
 }
 
-// FindAllBytes returns all starting indices where the pattern matches in the byte slice.
+// FindAllBytes returns the starting indices of all non-overlapping matches of the pattern in the byte slice.
 // Returns an empty slice if no matches are found.
 func (bm *BoyerMoore) FindAllBytes(data []byte) []int {
-	return bm._findAll(data)
+	return bm._findAll(data, 0, false, 0)
+}
+
+// FindAllInto appends the starting indices of all non-overlapping matches of the pattern in the
+// text to dst and returns the extended slice, like append. Passing dst[:0] reuses dst's storage,
+// so a buffer kept across calls lets many texts be searched with no allocation once it is
+// large enough (unless the matcher folds runes). The matches are those FindAll returns.
+func (bm *BoyerMoore) FindAllInto(txt string, dst []int) []int {
+	return bm._findAllInto([]byte(txt), dst)
+}
+
+// FindAllIntoBytes is FindAllInto for a byte slice.
+func (bm *BoyerMoore) FindAllIntoBytes(data []byte, dst []int) []int {
+	return bm._findAllInto(data, dst)
+}
+
+// FindAllMatches returns the span of every non-overlapping match of the pattern in the text.
+// Under UnicodeFold the span covers the original text, which may differ in length from the pattern.
+// Returns an empty slice if no matches are found.
+func (bm *BoyerMoore) FindAllMatches(txt string) []Match {
+	return bm._findAllMatches([]byte(txt))
+}
+
+// FindAllMatchesBytes returns the span of every non-overlapping match of the pattern in the byte slice.
+// Returns an empty slice if no matches are found.
+func (bm *BoyerMoore) FindAllMatchesBytes(data []byte) []Match {
+	return bm._findAllMatches(data)
+}
+
+// FindAllRanges returns every non-overlapping match of the pattern in the text as a half-open
+// [start, end) pair, so txt[r[0]:r[1]] is the matched text. The ranges are those of
+// FindAllMatches with End made exclusive; under UnicodeFold they cover the original text, whose
+// length may differ from the pattern's. Returns nil if no matches are found.
+func (bm *BoyerMoore) FindAllRanges(txt string) [][2]int {
+	return bm._findAllRanges([]byte(txt))
+}
+
+// FindAllRangesBytes returns every non-overlapping match of the pattern in the byte slice as a
+// half-open [start, end) pair. Returns nil if no matches are found.
+func (bm *BoyerMoore) FindAllRangesBytes(data []byte) [][2]int {
+	return bm._findAllRanges(data)
+}
+
+// FindAllOverlapping returns all starting indices where the pattern matches in the text,
+// including matches that overlap a previous one (e.g. "aa" in "aaaa" gives 0, 1 and 2).
+// Returns an empty slice if no matches are found.
+func (bm *BoyerMoore) FindAllOverlapping(txt string) []int {
+	return bm._findAll([]byte(txt), 0, true, 0)
+}
+
+// FindAllOverlappingBytes returns all starting indices where the pattern matches in the byte slice,
+// including matches that overlap a previous one.
+// Returns an empty slice if no matches are found.
+func (bm *BoyerMoore) FindAllOverlappingBytes(data []byte) []int {
+	return bm._findAll(data, 0, true, 0)
+}
+
+// FindAllLimit returns the starting indices of at most limit non-overlapping matches of the pattern in the text.
+// The search stops as soon as limit matches are found. A limit of 0 or less means no limit.
+func (bm *BoyerMoore) FindAllLimit(txt string, limit int) []int {
+	return bm._findAll([]byte(txt), 0, false, limit)
+}
+
+// FindAllLimitBytes returns the starting indices of at most limit non-overlapping matches of the pattern in the byte slice.
+// The search stops as soon as limit matches are found. A limit of 0 or less means no limit.
+func (bm *BoyerMoore) FindAllLimitBytes(data []byte, limit int) []int {
+	return bm._findAll(data, 0, false, limit)
 }
 
 // FindFirst returns the index of the first occurrence of the pattern in the text.
 // Returns -1 if the pattern is not found.
 func (bm *BoyerMoore) FindFirst(txt string) int {
-	res := bm.FindAll(txt)
-	if len(res) > 0 {
-		return res[0]
-	}
-	return -1
+	return bm._findFirst([]byte(txt), 0)
 }
 
 // FindFirstBytes returns the index of the first occurrence of the pattern in the byte slice.
 // Returns -1 if the pattern is not found.
 func (bm *BoyerMoore) FindFirstBytes(data []byte) int {
-	res := bm.FindAllBytes(data)
-	if len(res) > 0 {
-		return res[0]
-	}
-	return -1
+	return bm._findFirst(data, 0)
+}
+
+// FindAllFrom returns the indices of all non-overlapping matches of the pattern in the text
+// that start at or after byte index start. Indices are absolute positions in txt.
+// A negative start is treated as 0; a start beyond the end of the text yields an empty slice.
+func (bm *BoyerMoore) FindAllFrom(txt string, start int) []int {
+	return bm._findAll([]byte(txt), start, false, 0)
+}
+
+// FindAllFromBytes returns the indices of all non-overlapping matches of the pattern in the byte slice
+// that start at or after index start. Indices are absolute positions in data.
+// A negative start is treated as 0; a start beyond the end of the data yields an empty slice.
+func (bm *BoyerMoore) FindAllFromBytes(data []byte, start int) []int {
+	return bm._findAll(data, start, false, 0)
+}
+
+// FindFirstFrom returns the index of the first occurrence of the pattern in the text
+// that starts at or after byte index start. The index is an absolute position in txt.
+// A negative start is treated as 0. Returns -1 if the pattern is not found.
+func (bm *BoyerMoore) FindFirstFrom(txt string, start int) int {
+	return bm._findFirst([]byte(txt), start)
+}
+
+// FindFirstFromBytes returns the index of the first occurrence of the pattern in the byte slice
+// that starts at or after index start. The index is an absolute position in data.
+// A negative start is treated as 0. Returns -1 if the pattern is not found.
+func (bm *BoyerMoore) FindFirstFromBytes(data []byte, start int) int {
+	return bm._findFirst(data, start)
+}
+
+// FindLast returns the index of the last occurrence of the pattern in the text.
+// Returns -1 if the pattern is not found.
+func (bm *BoyerMoore) FindLast(txt string) int {
+	return bm._findLast([]byte(txt))
+}
+
+// FindLastBytes returns the index of the last occurrence of the pattern in the byte slice.
+// Returns -1 if the pattern is not found.
+func (bm *BoyerMoore) FindLastBytes(data []byte) int {
+	return bm._findLast(data)
+}
+
+// FindAllReverse returns the starting indices of the non-overlapping matches of the pattern in
+// the text in descending order, for "find previous" style navigation. The text is scanned from
+// the end, so matches are chosen greedily from the right: for overlapping occurrences they are
+// the mirror image of FindAll's (e.g. "aa" in "aaa" gives 1 where FindAll gives 0).
+// Returns nil if no matches are found.
+func (bm *BoyerMoore) FindAllReverse(txt string) []int {
+	return bm._findAllReverse([]byte(txt), 0)
+}
+
+// FindAllReverseBytes returns the starting indices of the non-overlapping matches of the pattern
+// in the byte slice in descending order, scanning from the end.
+func (bm *BoyerMoore) FindAllReverseBytes(data []byte) []int {
+	return bm._findAllReverse(data, 0)
+}
+
+// FindAllReverseLimit returns the starting indices of the last limit matches FindAllReverse
+// would report, in descending order. The scan stops as soon as limit matches are found, so
+// only the end of the text is read. A limit of 0 or less means no limit.
+func (bm *BoyerMoore) FindAllReverseLimit(txt string, limit int) []int {
+	return bm._findAllReverse([]byte(txt), limit)
+}
+
+// FindAllReverseLimitBytes is FindAllReverseLimit for a byte slice.
+func (bm *BoyerMoore) FindAllReverseLimitBytes(data []byte, limit int) []int {
+	return bm._findAllReverse(data, limit)
 }
 
 // Contains reports whether the pattern appears in the text.
+// It stops at the first match and, like Count, does not allocate unless the matcher folds runes.
 func (bm *BoyerMoore) Contains(txt string) bool {
 	return bm.FindFirst(txt) != -1
 }
@@ -89,63 +399,465 @@ func (bm *BoyerMoore) ContainsBytes(data []byte) bool {
 }
 
 // Count returns the number of non-overlapping occurrences of the pattern in the text.
+// It equals len(FindAll(txt)) but collects no indices, so it does not allocate
+// unless the matcher folds runes.
 func (bm *BoyerMoore) Count(txt string) int {
-	return len(bm.FindAll(txt))
+	return bm.count([]byte(txt), false)
 }
 
 // CountBytes returns the number of non-overlapping occurrences of the pattern in the byte slice.
 func (bm *BoyerMoore) CountBytes(data []byte) int {
-	return len(bm.FindAllBytes(data))
+	return bm.count(data, false)
+}
+
+// CountOverlapping returns the number of occurrences of the pattern in the text,
+// counting matches that overlap a previous one (e.g. "ana" occurs twice in "banana").
+// It equals len(FindAllOverlapping(txt)) but collects no indices.
+func (bm *BoyerMoore) CountOverlapping(txt string) int {
+	return bm.count([]byte(txt), true)
+}
+
+// CountOverlappingBytes returns the number of occurrences of the pattern in the byte slice,
+// counting matches that overlap a previous one.
+func (bm *BoyerMoore) CountOverlappingBytes(data []byte) int {
+	return bm.count(data, true)
+}
+
+// _findAll returns all indices at or after from where the pattern matches in the given byte slice,
+// folding the text first if the matcher uses rune folding.
+// If overlapping is false, the search resumes after the end of each match.
+// If limit is positive, the search stops once limit matches have been found.
+func (bm *BoyerMoore) _findAll(data []byte, from int, overlapping bool, limit int) []int {
+	h := bm.prepare(data)
+	results := bm.searchAll(h.data, h.index(from), overlapping, limit)
+	if h.offs != nil {
+		for i, s := range results {
+			results[i] = h.orig(s)
+		}
+	}
+	return results
+}
+
+// _findAllInto appends the start of every non-overlapping match in the given byte slice to dst.
+func (bm *BoyerMoore) _findAllInto(data []byte, dst []int) []int {
+	h := bm.prepare(data)
+	bm.searchEach(h.data, 0, false, func(s int) bool {
+		dst = append(dst, h.orig(s))
+		return true
+	})
+	return dst
 }
 
-// _findAll is an internal method that implements the Boyer-Moore search algorithm.
-// It returns all indices where the pattern matches in the given byte slice.
-func (bm *BoyerMoore) _findAll(data []byte) []int {
+// _findAllMatches returns the span of every non-overlapping match in the given byte slice.
+func (bm *BoyerMoore) _findAllMatches(data []byte) []Match {
+	m := len(bm.pat)
+	h := bm.prepare(data)
+	starts := bm.searchAll(h.data, 0, false, 0)
+	if len(starts) == 0 {
+		return nil
+	}
+	matches := make([]Match, len(starts))
+	for i, s := range starts {
+		matches[i] = Match{Start: h.orig(s), End: h.orig(s+m) - 1}
+	}
+	return matches
+}
+
+// _findAllRanges is _findAllMatches with exclusive ends.
+func (bm *BoyerMoore) _findAllRanges(data []byte) [][2]int {
+	m := len(bm.pat)
+	h := bm.prepare(data)
+	starts := bm.searchAll(h.data, 0, false, 0)
+	if len(starts) == 0 {
+		return nil
+	}
+	ranges := make([][2]int, len(starts))
+	for i, s := range starts {
+		ranges[i] = [2]int{h.orig(s), h.orig(s + m)}
+	}
+	return ranges
+}
+
+// _findFirst returns the index of the first match at or after from in the given byte slice,
+// folding the text first if the matcher uses rune folding.
+// It returns -1 if the pattern does not occur.
+func (bm *BoyerMoore) _findFirst(data []byte, from int) int {
+	h := bm.prepare(data)
+	if s := bm.searchFirst(h.data, h.index(from)); s >= 0 {
+		return h.orig(s)
+	}
+	return -1
+}
+
+// _findLast returns the index of the last match in the given byte slice,
+// folding the text first if the matcher uses rune folding.
+// It returns -1 if the pattern does not occur.
+func (bm *BoyerMoore) _findLast(data []byte) int {
+	h := bm.prepare(data)
+	if s := bm.searchLast(h.data); s >= 0 {
+		return h.orig(s)
+	}
+	return -1
+}
+
+// _findAllReverse collects the matches of searchEachReverse, at most limit of them if limit is
+// positive, folding the text first if the matcher uses rune folding.
+func (bm *BoyerMoore) _findAllReverse(data []byte, limit int) []int {
+	h := bm.prepare(data)
+	var results []int
+	bm.searchEachReverse(h.data, func(s int) bool {
+		results = append(results, h.orig(s))
+		return len(results) != limit
+	})
+	return results
+}
+
+// searchAll is an internal method that implements the Boyer-Moore search algorithm.
+// It returns all indices at or after from where the pattern matches in the given byte slice.
+// If overlapping is false, the search resumes after the end of each match.
+// If limit is positive, the search stops once limit matches have been found.
+func (bm *BoyerMoore) searchAll(data []byte, from int, overlapping bool, limit int) []int {
 	var results []int
+	bm.searchEach(data, from, overlapping, func(s int) bool {
+		results = append(results, s)
+		return len(results) != limit
+	})
+	return results
+}
+
+// count returns the number of matches in the given byte slice without collecting their indices.
+// In rune folding mode the text is still folded into a copy first.
+func (bm *BoyerMoore) count(data []byte, overlapping bool) int {
+	h := bm.prepare(data)
+	n := 0
+	bm.searchEach(h.data, 0, overlapping, func(int) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// searchEach runs the Boyer-Moore search algorithm and calls fn with each index at or after
+// from where the pattern matches in the given byte slice, stopping as soon as fn returns false.
+// If overlapping is false, the search resumes after the end of each match.
+// After an overlapping match the full Boyer-Moore shift is the period of the pattern, so the
+// bytes the new window shares with the match are known to match again and are not compared
+// (Galil rule); periodic patterns such as "aaaa" in "aaaaaaaa" then cost one comparison per
+// match instead of m, and the search stays linear in the length of the text.
+func (bm *BoyerMoore) searchEach(data []byte, from int, overlapping bool, fn func(s int) bool) {
 	m := len(bm.pat)
 	n := len(data)
 	if m == 0 || n == 0 || m > n {
-		return results
+		return
+	}
+	if m <= shortPatternLen {
+		bm.searchEachShort(data, from, overlapping, fn)
+		return
 	}
 
-	s := 0 // current text position
+	s := max(from, 0) // current text position
+	known := 0        // pattern bytes before known are already known to match at s
 	for s <= n-m {
 		j := m - 1
 		// Check pattern match from right to left
-		for j >= 0 && bm.pat[j] == bm.normChar(data[s+j]) {
+		for j >= known && bm.pat[j] == bm.normChar(data[s+j]) {
 			j--
 		}
 
-		if j < 0 {
+		if j < known {
 			// Pattern fully matched
-			results = append(results, s)
-			// Use bad character shift
-			if s+m < n {
-				s += m - bm.bcShift[bm.normChar(data[s+m])]
+			if !fn(s) {
+				return
+			}
+			if overlapping {
+				shift := bm.matchShift(bm.normChar(data[s+m-1]))
+				s += shift
+				if !bm.horspool {
+					// The Horspool shift need not be a period, so nothing carries over there
+					known = m - shift
+				}
 			} else {
-				s++
+				// Skip past the match
+				s += m
 			}
 		} else {
 			// Mismatch occurred
-			badCharShift := j - bm.bcShift[bm.normChar(data[s+j])]
-			goodSuffixShift := bm.gsShift[j]
-			if badCharShift < 1 {
-				badCharShift = 1
+			s += bm.mismatchShift(j, bm.normChar(data[s+j]), bm.normChar(data[s+m-1]))
+			known = 0
+		}
+	}
+}
+
+// searchFirst is an internal method that runs the Boyer-Moore search algorithm
+// and returns as soon as the first match is found.
+// It returns -1 if the pattern does not occur at or after from in the given byte slice.
+func (bm *BoyerMoore) searchFirst(data []byte, from int) int {
+	m := len(bm.pat)
+	n := len(data)
+	if m == 0 || n == 0 || m > n {
+		return -1
+	}
+	if m <= shortPatternLen {
+		first := -1
+		bm.searchEachShort(data, from, false, func(s int) bool {
+			first = s
+			return false
+		})
+		return first
+	}
+
+	s := max(from, 0) // current text position
+	for s <= n-m {
+		j := m - 1
+		// Check pattern match from right to left
+		for j >= 0 && bm.pat[j] == bm.normChar(data[s+j]) {
+			j--
+		}
+
+		if j < 0 {
+			// Pattern fully matched
+			return s
+		}
+
+		// Mismatch occurred
+		s += bm.mismatchShift(j, bm.normChar(data[s+j]), bm.normChar(data[s+m-1]))
+	}
+	return -1
+}
+
+// searchLast is an internal method that runs the Boyer-Moore search algorithm from right to left.
+// The text is scanned backwards with the reversed pattern's tables, so the search
+// returns as soon as the last match is found.
+// It returns -1 if the pattern does not occur in the given byte slice.
+func (bm *BoyerMoore) searchLast(data []byte) int {
+	last := -1
+	bm.searchEachReverse(data, func(s int) bool {
+		last = s
+		return false
+	})
+	return last
+}
+
+// searchEachReverse calls fn with the start of every non-overlapping match in data, from the
+// end of the text backwards, until fn returns false. It runs the reversed pattern's tables
+// over the text read right to left, so the matches are those FindAll would find in the
+// reversed text: after a match the search resumes before its start.
+func (bm *BoyerMoore) searchEachReverse(data []byte, fn func(int) bool) {
+	m := len(bm.pat)
+	n := len(data)
+	if m == 0 || n == 0 || m > n {
+		return
+	}
+	if m == 1 {
+		c, alt := bm.byteCases(bm.pat[0])
+		for i := n - 1; i >= 0; i-- {
+			if (data[i] == c || data[i] == alt) && !fn(i) {
+				return
 			}
-			if badCharShift > goodSuffixShift {
-				s += badCharShift
-			} else {
-				s += goodSuffixShift
+		}
+		return
+	}
+
+	rev := bm.rev
+	s := 0 // current position in the reversed text
+	for s <= n-m {
+		j := m - 1
+		// Check reversed pattern match from right to left, i.e. the pattern from left to right
+		for j >= 0 && rev.pat[j] == bm.normChar(data[n-1-s-j]) {
+			j--
+		}
+
+		if j < 0 {
+			// Pattern fully matched, convert back to a forward index
+			if !fn(n - s - m) {
+				return
 			}
+			s += m
+			continue
 		}
+
+		// Mismatch occurred
+		s += rev.mismatchShift(j, bm.normChar(data[n-1-s-j]), bm.normChar(data[n-s-m]))
+	}
+}
+
+// shortPatternLen is the longest pattern searched by searchEachShort instead of the shift tables
+const shortPatternLen = 4
+
+// searchEachShort is searchEach for patterns of up to shortPatternLen bytes. Their shifts are
+// too short to pay for themselves, so indexEither, which tests eight bytes per step, looks
+// for the pattern's rarest byte (see byteRarity) and the other bytes of each candidate are
+// then compared directly.
+func (bm *BoyerMoore) searchEachShort(data []byte, from int, overlapping bool, fn func(s int) bool) {
+	m := len(bm.pat)
+	k := 0 // pattern position of the byte scanned for
+	for j := 1; j < m; j++ {
+		if byteRarity(bm.pat[j]) > byteRarity(bm.pat[k]) {
+			k = j
+		}
+	}
+	// anchors[s] is the text byte at position k of the window starting at s
+	anchors := data[k : len(data)-m+1+k]
+	c, alt := bm.byteCases(bm.pat[k])
+	for s := indexEither(anchors, max(from, 0), c, alt); s >= 0; {
+		j := 0
+		for j < m && (j == k || bm.pat[j] == bm.normChar(data[s+j])) {
+			j++
+		}
+		next := s + 1
+		if j == m {
+			if !fn(s) {
+				return
+			}
+			if !overlapping {
+				next = s + m
+			}
+		}
+		s = indexEither(anchors, next, c, alt)
+	}
+}
+
+// commonBytes lists space and the lowercase letters from the most to the least frequent in English text
+const commonBytes = " etaoinshrdlcumwfgypbvkjxqz"
+
+// byteRarity estimates how rare the byte c is in typical text, higher meaning rarer.
+// Uppercase letters, digits, punctuation and non-ASCII bytes all rank as rarer than any
+// lowercase letter.
+func byteRarity(c byte) int {
+	if i := strings.IndexByte(commonBytes, c); i >= 0 {
+		return i
+	}
+	return len(commonBytes)
+}
+
+// byteCases returns the text bytes that match the pattern byte p: p itself and, when ASCII
+// case folding applies, its uppercase form. alt equals c if there is only one.
+func (bm *BoyerMoore) byteCases(p byte) (c, alt byte) {
+	c, alt = p, p
+	if bm.ignoreCase && bm.fold == nil && c >= 'a' && c <= 'z' {
+		alt = c - ('a' - 'A')
+	}
+	return c, alt
+}
+
+// Constants for testing the eight bytes of a word at once
+const (
+	lsbs = 0x0101010101010101 // the lowest bit of every byte
+	msbs = 0x8080808080808080 // the highest bit of every byte
+)
+
+// indexEither returns the first index at or after from holding c or alt, or -1 if there is none.
+// Eight bytes are tested per step by comparing a word of the text against each byte
+// repeated eight times (SWAR), so both cases of a letter are found in a single pass.
+// bytes.IndexByte would be faster still, but passing the text to it stops the compiler
+// from proving that the string search methods never modify the []byte(txt) conversion,
+// and every string search, for any pattern length, would then copy its text.
+func indexEither(data []byte, from int, c, alt byte) int {
+	i := max(from, 0)
+	wc, walt := lsbs*uint64(c), lsbs*uint64(alt)
+	for ; i+8 <= len(data); i += 8 {
+		w := binary.LittleEndian.Uint64(data[i:])
+		if hasZeroByte(w^wc) || hasZeroByte(w^walt) {
+			break
+		}
+	}
+	for ; i < len(data); i++ {
+		if data[i] == c || data[i] == alt {
+			return i
+		}
+	}
+	return -1
+}
+
+// hasZeroByte reports whether any of the eight bytes of w is zero
+func hasZeroByte(w uint64) bool {
+	return (w-lsbs)&^w&msbs != 0
+}
+
+// mismatchShift returns how far to move the window after a mismatch at pattern position j.
+// bad is the mismatched text byte and last the text byte under the last pattern position.
+func (bm *BoyerMoore) mismatchShift(j int, bad, last byte) int {
+	if bm.horspool {
+		return len(bm.pat) - 1 - bm.badChar(last)
+	}
+	badCharShift := j - bm.badChar(bad)
+	goodSuffixShift := bm.gsShift[j]
+	if badCharShift < 1 {
+		badCharShift = 1
+	}
+	return max(badCharShift, goodSuffixShift)
+}
+
+// matchShift returns how far to move the window after a full match without skipping
+// an overlapping one; last is the text byte under the last pattern position.
+// For full Boyer-Moore this is the good suffix shift of a mismatch at position 0, i.e. the
+// period of the pattern: the smallest shift at which the matched text can line up with
+// the pattern again. The bad character of a full match is undefined and must not be used.
+// The Horspool shift only depends on last and is safe after a match as well.
+func (bm *BoyerMoore) matchShift(last byte) int {
+	if bm.horspool {
+		return len(bm.pat) - 1 - bm.badChar(last)
+	}
+	return bm.gsShift[0]
+}
+
+// badChar returns the bad character table entry for c: the last position of c in the
+// pattern (in Horspool mode, in all of it but the last byte), or -1.
+func (bm *BoyerMoore) badChar(c byte) int {
+	if bm.class != nil {
+		return bm.classShift[bm.class[c]]
+	}
+	return bm.bcShift[c]
+}
+
+// buildTables constructs the shift tables used by the selected variant.
+// suffix is scratch space for the good suffix preprocessing; the possibly grown buffer is returned.
+func (bm *BoyerMoore) buildTables(suffix []int) []int {
+	if bm.horspool {
+		bm.buildHorspoolShift()
+		bm.buildClasses()
+		return suffix
+	}
+	bm.buildBadCharShift()
+	bm.buildClasses()
+	return bm.buildGoodSuffixShift(suffix)
+}
+
+// buildClasses derives the CompactTable translation from bcShift: every byte with an entry
+// gets a class of its own and all the others share the last one, whose shift is -1.
+// A pattern using all 256 byte values leaves that class empty, so the count fits in a byte.
+func (bm *BoyerMoore) buildClasses() {
+	if !bm.compact {
+		return
+	}
+	if bm.class == nil {
+		bm.class = new([256]uint8)
+	}
+	n := 0
+	for _, v := range bm.bcShift {
+		if v != -1 {
+			n++
+		}
+	}
+	bm.classShift = bm.classShift[:0]
+	for c, v := range bm.bcShift {
+		if v == -1 {
+			bm.class[c] = uint8(n) // n < 256 here, since this byte has no class of its own
+			continue
+		}
+		bm.class[c] = uint8(len(bm.classShift))
+		bm.classShift = append(bm.classShift, v)
+	}
+	if n < len(bm.bcShift) {
+		bm.classShift = append(bm.classShift, -1)
 	}
-	return results
 }
 
 // normChar normalizes a byte for case-insensitive comparison.
-// If ignoreCase is true, converts ASCII uppercase letters to lowercase.
+// If ignoreCase is true, converts ASCII uppercase letters to lowercase,
+// unless the text has already been folded rune by rune.
 func (bm *BoyerMoore) normChar(c byte) byte {
-	if bm.ignoreCase && c >= 'A' && c <= 'Z' {
+	if bm.ignoreCase && bm.fold == nil && c >= 'A' && c <= 'Z' {
 		return c + ('a' - 'A')
 	}
 	return c
@@ -163,11 +875,24 @@ func (bm *BoyerMoore) buildBadCharShift() {
 	}
 }
 
-// buildGoodSuffixShift constructs the good suffix shift table for the pattern.
-func (bm *BoyerMoore) buildGoodSuffixShift() {
+// buildHorspoolShift constructs the bad character table for Horspool mode.
+// The last pattern byte is left out so that every shift is at least 1.
+func (bm *BoyerMoore) buildHorspoolShift() {
+	for i := range bm.bcShift {
+		bm.bcShift[i] = -1
+	}
+	for i := 0; i < len(bm.pat)-1; i++ {
+		bm.bcShift[bm.pat[i]] = i
+	}
+}
+
+// buildGoodSuffixShift constructs the good suffix shift table for the pattern,
+// reusing the capacity of bm.gsShift. suffix is scratch space for the suffix lengths;
+// the possibly grown buffer is returned.
+func (bm *BoyerMoore) buildGoodSuffixShift(suffix []int) []int {
 	m := len(bm.pat)
-	bm.gsShift = make([]int, m)
-	suffix := make([]int, m)
+	bm.gsShift = slices.Grow(bm.gsShift[:0], m)[:m]
+	suffix = slices.Grow(suffix[:0], m)[:m]
 	suffix[m-1] = m
 	g := m - 1
 	f := m - 1
@@ -205,4 +930,5 @@ func (bm *BoyerMoore) buildGoodSuffixShift() {
 	for i := 0; i < m-1; i++ {
 		bm.gsShift[m-1-suffix[i]] = m - 1 - i
 	}
+	return suffix
 }
@@ -1,7 +1,9 @@
 package boyermoore
 
 import (
+	"fmt"
 	"math/rand"
+	"strings"
 	"testing"
 )
 
@@ -74,6 +76,24 @@ func BenchmarkFindFirst(b *testing.B) {
 	}
 }
 
+func BenchmarkFindFirstEarlyMatch(b *testing.B) {
+	const textLen = 1 << 20 // 1 MB
+	pattern := generateRandomString(16)
+	text := generateRandomString(100) + pattern + generateRandomString(textLen-116)
+	matcher := New(pattern, false)
+
+	b.Run("FindFirst", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			matcher.FindFirst(text)
+		}
+	})
+	b.Run("FindAll", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			matcher.FindAll(text)
+		}
+	})
+}
+
 func BenchmarkContains(b *testing.B) {
 	benchmarks := []struct {
 		name       string
@@ -127,3 +147,253 @@ func BenchmarkCount(b *testing.B) {
 		})
 	}
 }
+
+// BenchmarkCountManyMatches counts a pattern that occurs every few bytes,
+// against collecting the matches with FindAll and taking the length
+func BenchmarkCountManyMatches(b *testing.B) {
+	text := strings.Repeat("abc abd ", 8192)
+	for _, pattern := range []string{"ab", "abc abd"} {
+		matcher := New(pattern, false)
+		b.Run(fmt.Sprintf("Count/len=%d", len(pattern)), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				matcher.Count(text)
+			}
+		})
+		b.Run(fmt.Sprintf("FindAll/len=%d", len(pattern)), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_ = len(matcher.FindAll(text))
+			}
+		})
+	}
+}
+
+func BenchmarkContainsNoMatch(b *testing.B) {
+	text := strings.Repeat("abc abd ", 8192)
+	for _, pattern := range []string{"ab!", "abc abd!"} {
+		matcher := New(pattern, true)
+		b.Run(fmt.Sprintf("len=%d", len(pattern)), func(b *testing.B) {
+			b.SetBytes(int64(len(text)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				matcher.Contains(text)
+			}
+		})
+	}
+}
+
+func BenchmarkFindAllIntoShortTexts(b *testing.B) {
+	texts := make([]string, 1000)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("request %d: GET /api/v1/items?id=%d", i, i*7)
+	}
+	matcher := New("id=", false)
+	b.Run("FindAll", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, text := range texts {
+				_ = len(matcher.FindAll(text))
+			}
+		}
+	})
+	b.Run("FindAllInto", func(b *testing.B) {
+		b.ReportAllocs()
+		var buf []int
+		for i := 0; i < b.N; i++ {
+			for _, text := range texts {
+				buf = matcher.FindAllInto(text, buf[:0])
+			}
+		}
+	})
+}
+
+func BenchmarkHorspool(b *testing.B) {
+	benchmarks := []struct {
+		name       string
+		patternLen int
+		textLen    int
+	}{
+		{"Short Pattern (5) in Long Text (1000)", 5, 1000},
+		{"Medium Pattern (20) in Medium Text (500)", 20, 500},
+		{"Long Pattern (50) in Long Text (2000)", 50, 2000},
+	}
+	variants := []struct {
+		name string
+		opts Options
+	}{
+		{"BoyerMoore", Options{}},
+		{"Horspool", Options{Horspool: true}},
+	}
+
+	for _, bm := range benchmarks {
+		pattern, text := generateBenchmarkData(bm.patternLen, bm.textLen)
+		for _, v := range variants {
+			b.Run(bm.name+"/New/"+v.name, func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					NewWithOptions(pattern, v.opts)
+				}
+			})
+			b.Run(bm.name+"/FindAll/"+v.name, func(b *testing.B) {
+				matcher := NewWithOptions(pattern, v.opts)
+				for i := 0; i < b.N; i++ {
+					matcher.FindAll(text)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkFindAllFunc(b *testing.B) {
+	pattern := "abc"
+	text := strings.Repeat("xxabcxx", 1000)
+	data := []byte(text)
+	matcher := New(pattern, false)
+
+	b.Run("FindAllBytes", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			matcher.FindAllBytes(data)
+		}
+	})
+	b.Run("FindAllFuncBytes", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			n := 0
+			matcher.FindAllFuncBytes(data, func(int) bool {
+				n++
+				return true
+			})
+		}
+	})
+}
+
+func BenchmarkReset(b *testing.B) {
+	patterns := make([]string, 1000)
+	for i := range patterns {
+		patterns[i] = generateRandomString(8 + i%8)
+	}
+
+	b.Run("New", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, p := range patterns {
+				New(p, false)
+			}
+		}
+	})
+	b.Run("Reset", func(b *testing.B) {
+		b.ReportAllocs()
+		bm := New("", false)
+		for i := 0; i < b.N; i++ {
+			for _, p := range patterns {
+				bm.Reset(p)
+			}
+		}
+	})
+}
+
+func BenchmarkSingleByte(b *testing.B) {
+	text := strings.Repeat("the quick brown fox jumps over the lazy dog. ", 1500)
+	benchmarks := []struct {
+		name       string
+		pattern    string
+		ignoreCase bool
+	}{
+		{"Rare byte", "z", false},
+		{"Frequent byte", "o", false},
+		{"Absent byte", "%", false},
+		{"Ignore case", "Z", true},
+	}
+
+	for _, bench := range benchmarks {
+		bm := New(bench.pattern, bench.ignoreCase)
+		b.Run(bench.name+"/FindAll", func(b *testing.B) {
+			b.SetBytes(int64(len(text)))
+			for i := 0; i < b.N; i++ {
+				bm.FindAll(text)
+			}
+		})
+		b.Run(bench.name+"/Contains", func(b *testing.B) {
+			b.SetBytes(int64(len(text)))
+			for i := 0; i < b.N; i++ {
+				bm.Contains(text[:len(text)-2])
+			}
+		})
+	}
+}
+
+func BenchmarkShortPattern(b *testing.B) {
+	english := strings.Repeat("the quick brown fox jumps over the lazy dog. ", 1500)
+	random := generateRandomString(len(english))
+	texts := []struct {
+		name string
+		text string
+	}{
+		{"English", english},
+		{"Random", random},
+	}
+
+	for _, tx := range texts {
+		for m := 2; m <= 6; m++ {
+			pattern := strings.Repeat("xz", 3)[:m] // absent from both texts, so every window is scanned
+			bm := New(pattern, false)
+			b.Run(fmt.Sprintf("%s/len=%d", tx.name, m), func(b *testing.B) {
+				b.SetBytes(int64(len(tx.text)))
+				for i := 0; i < b.N; i++ {
+					bm.FindAll(tx.text)
+				}
+			})
+		}
+		bm := New("Jumps", true)
+		b.Run(tx.name+"/IgnoreCase", func(b *testing.B) {
+			b.SetBytes(int64(len(tx.text)))
+			for i := 0; i < b.N; i++ {
+				bm.FindAll(tx.text)
+			}
+		})
+	}
+}
+
+func BenchmarkPeriodicPattern(b *testing.B) {
+	benchmarks := []struct {
+		name    string
+		pattern string
+		text    string
+	}{
+		{"Run", strings.Repeat("a", 32), strings.Repeat("a", 1<<16)},
+		{"Period3", strings.Repeat("abc", 10), strings.Repeat("abc", 1<<14)},
+	}
+	for _, bb := range benchmarks {
+		bm := New(bb.pattern, false)
+		b.Run(bb.name+"/CountOverlapping", func(b *testing.B) {
+			b.SetBytes(int64(len(bb.text)))
+			for i := 0; i < b.N; i++ {
+				bm.CountOverlapping(bb.text)
+			}
+		})
+	}
+}
+
+// BenchmarkCompactTable searches a long random DNA sequence with and without the compact
+// bad character table
+func BenchmarkCompactTable(b *testing.B) {
+	rng := rand.New(rand.NewSource(1))
+	dna := make([]byte, 1<<20)
+	for i := range dna {
+		dna[i] = "ACGT"[rng.Intn(4)]
+	}
+	text := string(dna)
+	for _, m := range []int{8, 16, 64} {
+		pattern := text[len(text)/2 : len(text)/2+m]
+		for _, opts := range []Options{{}, {CompactTable: true}, {Horspool: true}, {Horspool: true, CompactTable: true}} {
+			bm := NewWithOptions(pattern, opts)
+			b.Run(fmt.Sprintf("len=%d/Horspool=%v/CompactTable=%v", m, opts.Horspool, opts.CompactTable), func(b *testing.B) {
+				b.SetBytes(int64(len(text)))
+				for i := 0; i < b.N; i++ {
+					bm.Count(text)
+				}
+			})
+		}
+	}
+}
@@ -1,7 +1,14 @@
 package boyermoore
 
 import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+	"unicode"
 )
 
 func TestStringSearch(t *testing.T) {
@@ -164,6 +171,602 @@ func TestByteSearch(t *testing.T) {
 	}
 }
 
+func TestOverlappingSearch(t *testing.T) {
+	tests := []struct {
+		name            string
+		pattern         string
+		text            string
+		ignoreCase      bool
+		wantAll         []int
+		wantOverlapping []int
+	}{
+		{
+			name:            "Repeated single character",
+			pattern:         "aa",
+			text:            "aaaa",
+			wantAll:         []int{0, 2},
+			wantOverlapping: []int{0, 1, 2},
+		},
+		{
+			name:            "Self-overlapping pattern",
+			pattern:         "ana",
+			text:            "banana",
+			wantAll:         []int{1},
+			wantOverlapping: []int{1, 3},
+		},
+		{
+			name:            "DNA motif",
+			pattern:         "ATA",
+			text:            "GATATATAC",
+			wantAll:         []int{1, 5},
+			wantOverlapping: []int{1, 3, 5},
+		},
+		{
+			name:            "Palindromic pattern",
+			pattern:         "aba",
+			text:            "abababa xaba",
+			wantAll:         []int{0, 4, 9},
+			wantOverlapping: []int{0, 2, 4, 9},
+		},
+		{
+			name:            "Ignore case",
+			pattern:         "Aa",
+			text:            "aAaA",
+			ignoreCase:      true,
+			wantAll:         []int{0, 2},
+			wantOverlapping: []int{0, 1, 2},
+		},
+		{
+			name:            "No overlap possible",
+			pattern:         "AB",
+			text:            "ABABAB",
+			wantAll:         []int{0, 2, 4},
+			wantOverlapping: []int{0, 2, 4},
+		},
+		{
+			name:            "Empty pattern",
+			pattern:         "",
+			text:            "aaaa",
+			wantAll:         []int{},
+			wantOverlapping: []int{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bm := New(tc.pattern, tc.ignoreCase)
+
+			gotAll := bm.FindAll(tc.text)
+			if !equalIntSlices(gotAll, tc.wantAll) {
+				t.Errorf("FindAll(%q) = %v; want %v", tc.text, gotAll, tc.wantAll)
+			}
+
+			gotOverlapping := bm.FindAllOverlapping(tc.text)
+			if !equalIntSlices(gotOverlapping, tc.wantOverlapping) {
+				t.Errorf("FindAllOverlapping(%q) = %v; want %v", tc.text, gotOverlapping, tc.wantOverlapping)
+			}
+
+			gotOverlappingBytes := bm.FindAllOverlappingBytes([]byte(tc.text))
+			if !equalIntSlices(gotOverlappingBytes, tc.wantOverlapping) {
+				t.Errorf("FindAllOverlappingBytes(%q) = %v; want %v", tc.text, gotOverlappingBytes, tc.wantOverlapping)
+			}
+
+			if got := bm.Count(tc.text); got != len(tc.wantAll) {
+				t.Errorf("Count(%q) = %d; want %d", tc.text, got, len(tc.wantAll))
+			}
+			if got := bm.CountOverlapping(tc.text); got != len(tc.wantOverlapping) {
+				t.Errorf("CountOverlapping(%q) = %d; want %d", tc.text, got, len(tc.wantOverlapping))
+			}
+			if got := bm.CountOverlappingBytes([]byte(tc.text)); got != len(tc.wantOverlapping) {
+				t.Errorf("CountOverlappingBytes(%q) = %d; want %d", tc.text, got, len(tc.wantOverlapping))
+			}
+		})
+	}
+}
+
+func TestPostMatchShift(t *testing.T) {
+	tests := []struct {
+		name            string
+		pattern         string
+		text            string
+		wantAll         []int
+		wantOverlapping []int
+	}{
+		{"Periodic pattern", "abab", "ababab", []int{0}, []int{0, 2}},
+		{"Periodic pattern repeated", "abab", "abababababab", []int{0, 4, 8}, []int{0, 2, 4, 6, 8}},
+		{"Border shorter than half", "abcab", "abcabcab", []int{0}, []int{0, 3}},
+		{"Aperiodic pattern", "abc", "abcabcabc", []int{0, 3, 6}, []int{0, 3, 6}},
+		{"Single repeated byte", "aaa", "aaaaaa", []int{0, 3}, []int{0, 1, 2, 3}},
+		{"Match at end of text", "abab", "xxabab", []int{2}, []int{2}},
+		{"Mismatch right after match", "abab", "ababxabab", []int{0, 5}, []int{0, 5}},
+	}
+
+	for _, tc := range tests {
+		for _, horspool := range []bool{false, true} {
+			t.Run(tc.name, func(t *testing.T) {
+				bm := NewWithOptions(tc.pattern, Options{Horspool: horspool})
+
+				gotAll := bm.FindAll(tc.text)
+				if !equalIntSlices(gotAll, tc.wantAll) {
+					t.Errorf("Horspool=%v: FindAll(%q) = %v; want %v", horspool, tc.text, gotAll, tc.wantAll)
+				}
+
+				gotOverlapping := bm.FindAllOverlapping(tc.text)
+				if !equalIntSlices(gotOverlapping, tc.wantOverlapping) {
+					t.Errorf("Horspool=%v: FindAllOverlapping(%q) = %v; want %v", horspool, tc.text, gotOverlapping, tc.wantOverlapping)
+				}
+			})
+		}
+	}
+}
+
+func TestHorspool(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	randString := func(n int) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = "abAB"[rng.IntN(4)]
+		}
+		return string(b)
+	}
+
+	for i := 0; i < 2000; i++ {
+		pattern := randString(1 + rng.IntN(5))
+		text := randString(rng.IntN(40))
+		ignoreCase := rng.IntN(2) == 0
+
+		full := New(pattern, ignoreCase)
+		hp := NewWithOptions(pattern, Options{IgnoreCase: ignoreCase, Horspool: true})
+
+		if got, want := hp.FindAll(text), full.FindAll(text); !equalIntSlices(got, want) {
+			t.Fatalf("FindAll(%q) for %q = %v; want %v", text, pattern, got, want)
+		}
+		if got, want := hp.FindAllOverlapping(text), full.FindAllOverlapping(text); !equalIntSlices(got, want) {
+			t.Fatalf("FindAllOverlapping(%q) for %q = %v; want %v", text, pattern, got, want)
+		}
+		if got, want := hp.FindLast(text), full.FindLast(text); got != want {
+			t.Fatalf("FindLast(%q) for %q = %d; want %d", text, pattern, got, want)
+		}
+	}
+}
+
+func TestFindAllLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		text    string
+		limit   int
+		want    []int
+	}{
+		{"Limit below match count", "AB", "ABABABAB", 2, []int{0, 2}},
+		{"Limit equal to match count", "AB", "ABABAB", 3, []int{0, 2, 4}},
+		{"Limit above match count", "AB", "ABAB", 10, []int{0, 2}},
+		{"Limit of one", "AB", "ZZABAB", 1, []int{2}},
+		{"Zero means no limit", "AB", "ABABAB", 0, []int{0, 2, 4}},
+		{"Negative means no limit", "AB", "ABABAB", -1, []int{0, 2, 4}},
+		{"No match", "AB", "ZZZ", 2, []int{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bm := New(tc.pattern, false)
+
+			got := bm.FindAllLimit(tc.text, tc.limit)
+			if !equalIntSlices(got, tc.want) {
+				t.Errorf("FindAllLimit(%q, %d) = %v; want %v", tc.text, tc.limit, got, tc.want)
+			}
+
+			gotBytes := bm.FindAllLimitBytes([]byte(tc.text), tc.limit)
+			if !equalIntSlices(gotBytes, tc.want) {
+				t.Errorf("FindAllLimitBytes(%q, %d) = %v; want %v", tc.text, tc.limit, gotBytes, tc.want)
+			}
+		})
+	}
+}
+
+func TestSearchFrom(t *testing.T) {
+	tests := []struct {
+		name      string
+		pattern   string
+		text      string
+		start     int
+		wantAll   []int
+		wantFirst int
+	}{
+		{"Start at zero", "AB", "ABZABZAB", 0, []int{0, 3, 6}, 0},
+		{"Start inside first match", "AB", "ABZABZAB", 1, []int{3, 6}, 3},
+		{"Start at a match", "AB", "ABZABZAB", 3, []int{3, 6}, 3},
+		{"Start after last match", "AB", "ABZABZAB", 7, []int{}, -1},
+		{"Start at end of text", "AB", "ABZABZAB", 8, []int{}, -1},
+		{"Start beyond text", "AB", "ABZABZAB", 100, []int{}, -1},
+		{"Negative start", "AB", "ABZABZAB", -5, []int{0, 3, 6}, 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bm := New(tc.pattern, false)
+
+			gotAll := bm.FindAllFrom(tc.text, tc.start)
+			if !equalIntSlices(gotAll, tc.wantAll) {
+				t.Errorf("FindAllFrom(%q, %d) = %v; want %v", tc.text, tc.start, gotAll, tc.wantAll)
+			}
+
+			gotAllBytes := bm.FindAllFromBytes([]byte(tc.text), tc.start)
+			if !equalIntSlices(gotAllBytes, tc.wantAll) {
+				t.Errorf("FindAllFromBytes(%q, %d) = %v; want %v", tc.text, tc.start, gotAllBytes, tc.wantAll)
+			}
+
+			gotFirst := bm.FindFirstFrom(tc.text, tc.start)
+			if gotFirst != tc.wantFirst {
+				t.Errorf("FindFirstFrom(%q, %d) = %d; want %d", tc.text, tc.start, gotFirst, tc.wantFirst)
+			}
+
+			gotFirstBytes := bm.FindFirstFromBytes([]byte(tc.text), tc.start)
+			if gotFirstBytes != tc.wantFirst {
+				t.Errorf("FindFirstFromBytes(%q, %d) = %d; want %d", tc.text, tc.start, gotFirstBytes, tc.wantFirst)
+			}
+		})
+	}
+}
+
+func TestCompactTable(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	gen := func(alphabet string, n int) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = alphabet[rng.IntN(len(alphabet))]
+		}
+		return string(b)
+	}
+	all := make([]byte, 256)
+	for i := range all {
+		all[i] = byte(i)
+	}
+
+	for _, opts := range []Options{{}, {Horspool: true}, {IgnoreCase: true}} {
+		plainOpts := opts
+		opts.CompactTable = true
+		for range 300 {
+			// Mostly DNA, with the occasional N or lowercase base
+			text := gen("ACGTACGTACGTNa", 1+rng.IntN(200))
+			pattern := gen("ACGTACGTN", 1+rng.IntN(12))
+			compact, plain := NewWithOptions(pattern, opts), NewWithOptions(pattern, plainOpts)
+			if got, want := compact.FindAllOverlapping(text), plain.FindAllOverlapping(text); !slices.Equal(got, want) {
+				t.Fatalf("%+v: FindAllOverlapping(%q) for %q = %v; want %v", opts, text, pattern, got, want)
+			}
+			if got, want := compact.FindLast(text), plain.FindLast(text); got != want {
+				t.Fatalf("%+v: FindLast(%q) for %q = %d; want %d", opts, text, pattern, got, want)
+			}
+		}
+
+		// With every byte value in the pattern each byte has a class of its own, except that
+		// under IgnoreCase uppercase letters are never looked up and share the absent class
+		pattern := string(all)
+		text := gen("xyz", 100) + pattern + string(all[:200])
+		compact := NewWithOptions(pattern, opts)
+		if got, want := compact.FindAll(text), NewWithOptions(pattern, plainOpts).FindAll(text); !slices.Equal(got, want) {
+			t.Errorf("%+v: FindAll for a pattern of all 256 bytes = %v; want %v", opts, got, want)
+		}
+		wantClasses := 256
+		if opts.IgnoreCase {
+			wantClasses = 256 - 26 + 1
+		}
+		if len(compact.classShift) != wantClasses {
+			t.Errorf("%+v: %d classes for a pattern of all 256 bytes; want %d", opts, len(compact.classShift), wantClasses)
+		}
+	}
+
+	// Reset rebuilds the classes
+	bm := NewWithOptions("AC", Options{CompactTable: true})
+	bm.Reset("GGTT")
+	if got := bm.FindAll("ACGGTTAC"); !slices.Equal(got, []int{2}) {
+		t.Errorf("FindAll after Reset = %v; want [2]", got)
+	}
+}
+
+func TestFindLast(t *testing.T) {
+	tests := []struct {
+		name       string
+		pattern    string
+		text       string
+		ignoreCase bool
+		want       int
+	}{
+		{"Single match", "ABC", "ZZZABCZZZ", false, 3},
+		{"Multiple matches", "AB", "ABZABZAB", false, 6},
+		{"Overlapping matches", "aa", "aaaa", false, 2},
+		{"Match at start only", "ABC", "ABCZZZZZ", false, 0},
+		{"Match near end", "ZZ", "ABCDEFGHZZ", false, 8},
+		{"Ignore case", "AbC", "abcZZABCzz", true, 5},
+		{"Whole text", "ABC", "ABC", false, 0},
+		{"No match", "ABC", "ZZZABZ", false, -1},
+		{"Empty pattern", "", "ABC", false, -1},
+		{"Pattern longer than text", "ABCDEFG", "ABC", false, -1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bm := New(tc.pattern, tc.ignoreCase)
+
+			got := bm.FindLast(tc.text)
+			if got != tc.want {
+				t.Errorf("FindLast(%q) = %d; want %d", tc.text, got, tc.want)
+			}
+
+			gotBytes := bm.FindLastBytes([]byte(tc.text))
+			if gotBytes != tc.want {
+				t.Errorf("FindLastBytes(%q) = %d; want %d", tc.text, gotBytes, tc.want)
+			}
+		})
+	}
+}
+
+// turkishFold lowercases with Turkish rules for the dotted and dotless i.
+func turkishFold(r rune) rune {
+	switch r {
+	case 'I':
+		return 'ı'
+	case 'İ':
+		return 'i'
+	}
+	return unicode.ToLower(r)
+}
+
+func TestFindAllReverse(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		text    string
+		opts    Options
+		want    []int
+	}{
+		{"Multiple matches", "AB", "ABZABZAB", Options{}, []int{6, 3, 0}},
+		{"Overlapping matches are chosen from the right", "aa", "aaaaa", Options{}, []int{3, 1}},
+		{"Single byte", "a", "abca", Options{}, []int{3, 0}},
+		{"Ignore case", "AbC", "abcZZABCzz", Options{IgnoreCase: true}, []int{5, 0}},
+		{"Horspool", "abab", "abababab", Options{Horspool: true}, []int{4, 0}},
+		{"Unicode fold", "ⱥb", "xȺBxⱥb", Options{IgnoreCase: true, UnicodeFold: true}, []int{5, 1}},
+		{"No match", "ABC", "ZZZABZ", Options{}, nil},
+		{"Empty pattern", "", "ABC", Options{}, nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bm := NewWithOptions(tc.pattern, tc.opts)
+			if got := bm.FindAllReverse(tc.text); !slices.Equal(got, tc.want) {
+				t.Errorf("FindAllReverse(%q) = %v; want %v", tc.text, got, tc.want)
+			}
+			if got := bm.FindAllReverseBytes([]byte(tc.text)); !slices.Equal(got, tc.want) {
+				t.Errorf("FindAllReverseBytes(%q) = %v; want %v", tc.text, got, tc.want)
+			}
+			for limit := 0; limit <= len(tc.want)+1; limit++ {
+				want := tc.want
+				if limit > 0 && limit < len(want) {
+					want = want[:limit]
+				}
+				if got := bm.FindAllReverseLimit(tc.text, limit); !slices.Equal(got, want) {
+					t.Errorf("FindAllReverseLimit(%q, %d) = %v; want %v", tc.text, limit, got, want)
+				}
+				if got := bm.FindAllReverseLimitBytes([]byte(tc.text), limit); !slices.Equal(got, want) {
+					t.Errorf("FindAllReverseLimitBytes(%q, %d) = %v; want %v", tc.text, limit, got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestFindAllReverseRandom checks FindAllReverse against FindAll run on the reversed text
+func TestFindAllReverseRandom(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+	gen := func(n int) []byte {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = "abAB"[rng.IntN(4)]
+		}
+		return b
+	}
+	for _, opts := range []Options{{}, {IgnoreCase: true}, {Horspool: true}} {
+		for range 500 {
+			pattern, text := gen(1+rng.IntN(5)), gen(rng.IntN(60))
+			rp, rt := slices.Clone(pattern), slices.Clone(text)
+			slices.Reverse(rp)
+			slices.Reverse(rt)
+
+			var want []int
+			for _, s := range NewWithOptions(string(rp), opts).FindAllBytes(rt) {
+				want = append(want, len(text)-s-len(pattern))
+			}
+			if got := NewWithOptions(string(pattern), opts).FindAllReverseBytes(text); !slices.Equal(got, want) {
+				t.Fatalf("%+v: FindAllReverse(%q) for %q = %v; want %v", opts, text, pattern, got, want)
+			}
+		}
+	}
+}
+
+func TestUnicodeFold(t *testing.T) {
+	tests := []struct {
+		name      string
+		pattern   string
+		text      string
+		opts      Options
+		wantAll   []int
+		wantFirst int
+		wantLast  int
+	}{
+		{
+			name:      "Accented letters",
+			pattern:   "café",
+			text:      "un CAFÉ et un Café",
+			opts:      Options{IgnoreCase: true, UnicodeFold: true},
+			wantAll:   []int{3, 15},
+			wantFirst: 3,
+			wantLast:  15,
+		},
+		{
+			name:      "ASCII-only default does not fold accents",
+			pattern:   "café",
+			text:      "un CAFÉ et un Café",
+			opts:      Options{IgnoreCase: true},
+			wantAll:   []int{15},
+			wantFirst: 15,
+			wantLast:  15,
+		},
+		{
+			name:      "Folded rune longer than original",
+			pattern:   "ⱥb",
+			text:      "xȺBxⱥb",
+			opts:      Options{IgnoreCase: true, UnicodeFold: true},
+			wantAll:   []int{1, 5},
+			wantFirst: 1,
+			wantLast:  5,
+		},
+		{
+			name:      "Folded rune shorter than original",
+			pattern:   "k",
+			text:      "\u212a and k",
+			opts:      Options{IgnoreCase: true, UnicodeFold: true},
+			wantAll:   []int{0, 8},
+			wantFirst: 0,
+			wantLast:  8,
+		},
+		{
+			name:      "UnicodeFold without IgnoreCase is case-sensitive",
+			pattern:   "café",
+			text:      "CAFÉ café",
+			opts:      Options{UnicodeFold: true},
+			wantAll:   []int{6},
+			wantFirst: 6,
+			wantLast:  6,
+		},
+		{
+			name:      "Custom Turkish fold keeps dotless i apart",
+			pattern:   "ılık",
+			text:      "ILIK ilik",
+			opts:      Options{IgnoreCase: true, Fold: turkishFold},
+			wantAll:   []int{0},
+			wantFirst: 0,
+			wantLast:  0,
+		},
+		{
+			name:      "Custom Turkish fold maps dotted capital to i",
+			pattern:   "istanbul",
+			text:      "ISTANBUL İSTANBUL",
+			opts:      Options{IgnoreCase: true, Fold: turkishFold},
+			wantAll:   []int{9},
+			wantFirst: 9,
+			wantLast:  9,
+		},
+		{
+			name:      "Custom fold overrides UnicodeFold",
+			pattern:   "ılık",
+			text:      "ılık ILIK",
+			opts:      Options{IgnoreCase: true, UnicodeFold: true, Fold: turkishFold},
+			wantAll:   []int{0, 7},
+			wantFirst: 0,
+			wantLast:  7,
+		},
+		{
+			name:      "Custom fold without IgnoreCase is case-sensitive",
+			pattern:   "ılık",
+			text:      "ILIK ılık",
+			opts:      Options{Fold: turkishFold},
+			wantAll:   []int{5},
+			wantFirst: 5,
+			wantLast:  5,
+		},
+		{
+			name:      "Invalid UTF-8 is left untouched",
+			pattern:   "\xffA",
+			text:      "\xffa\xfeA",
+			opts:      Options{IgnoreCase: true, UnicodeFold: true},
+			wantAll:   []int{0},
+			wantFirst: 0,
+			wantLast:  0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bm := NewWithOptions(tc.pattern, tc.opts)
+
+			gotAll := bm.FindAll(tc.text)
+			if !equalIntSlices(gotAll, tc.wantAll) {
+				t.Errorf("FindAll(%q) = %v; want %v", tc.text, gotAll, tc.wantAll)
+			}
+
+			gotFirst := bm.FindFirst(tc.text)
+			if gotFirst != tc.wantFirst {
+				t.Errorf("FindFirst(%q) = %d; want %d", tc.text, gotFirst, tc.wantFirst)
+			}
+
+			gotLast := bm.FindLast(tc.text)
+			if gotLast != tc.wantLast {
+				t.Errorf("FindLast(%q) = %d; want %d", tc.text, gotLast, tc.wantLast)
+			}
+
+			if len(tc.wantAll) > 1 {
+				gotFrom := bm.FindAllFrom(tc.text, tc.wantAll[0]+1)
+				if !equalIntSlices(gotFrom, tc.wantAll[1:]) {
+					t.Errorf("FindAllFrom(%q, %d) = %v; want %v", tc.text, tc.wantAll[0]+1, gotFrom, tc.wantAll[1:])
+				}
+			}
+		})
+	}
+}
+
+func TestFindAllMatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		text    string
+		opts    Options
+		want    []Match
+	}{
+		{
+			name:    "Basic match",
+			pattern: "ABC",
+			text:    "ZZZABCZZZABC",
+			want:    []Match{{Start: 3, End: 5}, {Start: 9, End: 11}},
+		},
+		{
+			name:    "Ignore case",
+			pattern: "AbC",
+			text:    "zzabcZZABC",
+			opts:    Options{IgnoreCase: true},
+			want:    []Match{{Start: 2, End: 4}, {Start: 7, End: 9}},
+		},
+		{
+			name:    "Unicode fold with differing lengths",
+			pattern: "ⱥb",
+			text:    "xȺBxⱥb",
+			opts:    Options{IgnoreCase: true, UnicodeFold: true},
+			want:    []Match{{Start: 1, End: 3}, {Start: 5, End: 8}},
+		},
+		{
+			name:    "No match",
+			pattern: "ABC",
+			text:    "ZZZ",
+			want:    nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bm := NewWithOptions(tc.pattern, tc.opts)
+
+			got := bm.FindAllMatches(tc.text)
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("FindAllMatches(%q) = %v; want %v", tc.text, got, tc.want)
+			}
+
+			gotBytes := bm.FindAllMatchesBytes([]byte(tc.text))
+			if !slices.Equal(gotBytes, tc.want) {
+				t.Errorf("FindAllMatchesBytes(%q) = %v; want %v", tc.text, gotBytes, tc.want)
+			}
+		})
+	}
+}
+
 func equalIntSlices(a, b []int) bool {
 	if len(a) != len(b) {
 		return false
@@ -175,3 +778,656 @@ func equalIntSlices(a, b []int) bool {
 	}
 	return true
 }
+
+func TestFindAllRanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		text    string
+		opts    Options
+		want    [][2]int
+	}{
+		{"Basic match", "ABC", "ZZZABCZZZABC", Options{}, [][2]int{{3, 6}, {9, 12}}},
+		{"Non-overlapping", "aa", "aaaaa", Options{}, [][2]int{{0, 2}, {2, 4}}},
+		{"Ignore case", "AbC", "zzabcZZABC", Options{IgnoreCase: true}, [][2]int{{2, 5}, {7, 10}}},
+		{"Unicode fold with differing lengths", "ⱥb", "xȺBxⱥb", Options{IgnoreCase: true, UnicodeFold: true}, [][2]int{{1, 4}, {5, 9}}},
+		{"No match", "ABC", "ZZZ", Options{}, nil},
+		{"Empty pattern", "", "ZZZ", Options{}, nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bm := NewWithOptions(tc.pattern, tc.opts)
+
+			got := bm.FindAllRanges(tc.text)
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("FindAllRanges(%q) = %v; want %v", tc.text, got, tc.want)
+			}
+			if gotBytes := bm.FindAllRangesBytes([]byte(tc.text)); !slices.Equal(gotBytes, tc.want) {
+				t.Errorf("FindAllRangesBytes(%q) = %v; want %v", tc.text, gotBytes, tc.want)
+			}
+
+			// Each range slices out the matched text, and agrees with FindAllMatches
+			matches := bm.FindAllMatches(tc.text)
+			for i, r := range got {
+				if matched := tc.text[r[0]:r[1]]; !bm.MatchesPrefix(matched) || !bm.MatchesSuffix(matched) {
+					t.Errorf("text[%d:%d] = %q does not match the pattern", r[0], r[1], matched)
+				}
+				if m := matches[i]; r != [2]int{m.Start, m.End + 1} {
+					t.Errorf("range %v differs from FindAllMatches span %v", r, m)
+				}
+			}
+		})
+	}
+}
+
+func TestNewWithError(t *testing.T) {
+	if _, err := NewWithError("", false); !errors.Is(err, ErrEmptyPattern) {
+		t.Errorf("NewWithError(\"\") error = %v; want %v", err, ErrEmptyPattern)
+	}
+
+	bm, err := NewWithError("AbC", true)
+	if err != nil {
+		t.Fatalf("NewWithError(\"AbC\") returned error: %v", err)
+	}
+	if got, want := bm.FindAll("abc ABC"), []int{0, 4}; !equalIntSlices(got, want) {
+		t.Errorf("FindAll = %v; want %v", got, want)
+	}
+}
+
+func TestNewFromBytes(t *testing.T) {
+	text := "ABC abc aBc xyz"
+	for _, tc := range []struct {
+		pattern    string
+		ignoreCase bool
+	}{
+		{"abc", false},
+		{"AbC", true},
+		{"x", true},
+		{"", false},
+	} {
+		want := New(tc.pattern, tc.ignoreCase).FindAll(text)
+		if got := NewFromBytes([]byte(tc.pattern), tc.ignoreCase).FindAll(text); !equalIntSlices(got, want) {
+			t.Errorf("NewFromBytes(%q, %v).FindAll(%q) = %v; want %v", tc.pattern, tc.ignoreCase, text, got, want)
+		}
+	}
+
+	// The pattern buffer is used as is, not copied
+	p := []byte("AbC")
+	NewFromBytes(p, true)
+	if string(p) != "abc" {
+		t.Errorf("pattern after NewFromBytes = %q; want it lowercased in place to %q", p, "abc")
+	}
+
+	pattern := strings.Repeat("needle", 10)
+	b := []byte(pattern)
+	fromString := testing.AllocsPerRun(10, func() { New(pattern, false) })
+	fromBytes := testing.AllocsPerRun(10, func() { NewFromBytes(b, false) })
+	if fromBytes != fromString-1 {
+		t.Errorf("NewFromBytes allocated %v times; want one fewer than New's %v", fromBytes, fromString)
+	}
+}
+
+func TestPatternAccessors(t *testing.T) {
+	tests := []struct {
+		name       string
+		bm         *BoyerMoore
+		pattern    string
+		ignoreCase bool
+		patternLen int
+	}{
+		{"Case-sensitive", New("AbC", false), "AbC", false, 3},
+		{"Ignore case keeps the original casing", New("AbC", true), "AbC", true, 3},
+		{"Unicode fold", NewWithOptions("CAFÉ", Options{IgnoreCase: true, UnicodeFold: true}), "CAFÉ", true, 5},
+		{"UnicodeFold without IgnoreCase", NewWithOptions("CAFÉ", Options{UnicodeFold: true}), "CAFÉ", false, 5},
+		{"Unicode fold shortens the pattern", NewWithOptions("\u212A", Options{IgnoreCase: true, UnicodeFold: true}), "\u212A", true, 1},
+		{"From bytes", NewFromBytes([]byte("AbC"), false), "AbC", false, 3},
+		{"From bytes ignoring case", NewFromBytes([]byte("AbC"), true), "AbC", true, 3},
+		{"Empty", New("", true), "", true, 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.bm.Pattern(); got != tc.pattern {
+				t.Errorf("Pattern() = %q; want %q", got, tc.pattern)
+			}
+			if got := tc.bm.IgnoreCase(); got != tc.ignoreCase {
+				t.Errorf("IgnoreCase() = %v; want %v", got, tc.ignoreCase)
+			}
+			if got := tc.bm.PatternLen(); got != tc.patternLen {
+				t.Errorf("PatternLen() = %d; want %d", got, tc.patternLen)
+			}
+		})
+	}
+
+	// Reset replaces the pattern reported
+	bm := NewFromBytes([]byte("first"), true)
+	bm.Reset("SECOND")
+	if got := bm.Pattern(); got != "SECOND" {
+		t.Errorf("Pattern() after Reset = %q; want %q", got, "SECOND")
+	}
+}
+
+func TestEqual(t *testing.T) {
+	fold := func(r rune) rune { return r }
+	custom := NewWithOptions("abc", Options{IgnoreCase: true, Fold: fold})
+	tests := []struct {
+		name string
+		a, b *BoyerMoore
+		want bool
+	}{
+		{"Same pattern", New("abc", false), New("abc", false), true},
+		{"Different pattern", New("abc", false), New("abd", false), false},
+		{"Case differs", New("ABC", false), New("abc", false), false},
+		{"Case folded away", New("ABC", true), New("abc", true), true},
+		{"IgnoreCase differs", New("abc", true), New("abc", false), false},
+		{"From bytes", NewFromBytes([]byte("AbC"), true), New("aBc", true), true},
+		{"Horspool is ignored", NewWithOptions("abc", Options{Horspool: true}), New("abc", false), true},
+		{"Unicode fold", NewWithOptions("CAFÉ", Options{IgnoreCase: true, UnicodeFold: true}), NewWithOptions("café", Options{IgnoreCase: true, UnicodeFold: true}), true},
+		{"Unicode fold against ASCII folding", NewWithOptions("abc", Options{IgnoreCase: true, UnicodeFold: true}), New("abc", true), false},
+		{"Custom fold is only equal to itself", custom, custom, true},
+		{"Custom fold", custom, NewWithOptions("abc", Options{IgnoreCase: true, Fold: fold}), false},
+		{"Nil", nil, nil, true},
+		{"Nil against a matcher", New("", false), nil, false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.a.Equal(tc.b); got != tc.want {
+				t.Errorf("a.Equal(b) = %v; want %v", got, tc.want)
+			}
+			if got := tc.b.Equal(tc.a); got != tc.want {
+				t.Errorf("b.Equal(a) = %v; want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestConcurrentSearch shares each matcher between many goroutines; run with -race
+// to check that searching never writes to the matcher.
+func TestConcurrentSearch(t *testing.T) {
+	text := strings.Repeat("xx needle NEEDLE Needle née NÉE ", 200)
+	matchers := map[string]*BoyerMoore{
+		"Case-sensitive": New("needle", false),
+		"Ignore case":    New("needle", true),
+		"Horspool":       NewWithOptions("needle", Options{IgnoreCase: true, Horspool: true}),
+		"Compact table":  NewWithOptions("needle", Options{CompactTable: true}),
+		"Unicode fold":   NewWithOptions("NÉE", Options{IgnoreCase: true, UnicodeFold: true}),
+		"Custom fold":    NewWithOptions("née", Options{IgnoreCase: true, Fold: unicode.ToLower}),
+	}
+
+	for name, bm := range matchers {
+		t.Run(name, func(t *testing.T) {
+			wantAll := bm.FindAll(text)
+			wantFirst := bm.FindFirst(text)
+			wantReverse := bm.FindAllReverse(text)
+
+			var wg sync.WaitGroup
+			for g := 0; g < 16; g++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := 0; i < 20; i++ {
+						if got := bm.FindAll(text); !slices.Equal(got, wantAll) {
+							t.Errorf("FindAll = %v; want %v", got, wantAll)
+							return
+						}
+						if got := bm.FindAllBytes([]byte(text)); !slices.Equal(got, wantAll) {
+							t.Errorf("FindAllBytes = %v; want %v", got, wantAll)
+							return
+						}
+						if got := bm.FindFirst(text); got != wantFirst {
+							t.Errorf("FindFirst = %d; want %d", got, wantFirst)
+							return
+						}
+						if got := bm.Count(text); got != len(wantAll) {
+							t.Errorf("Count = %d; want %d", got, len(wantAll))
+							return
+						}
+						if got := bm.FindAllReverse(text); !slices.Equal(got, wantReverse) {
+							t.Errorf("FindAllReverse = %v; want %v", got, wantReverse)
+							return
+						}
+					}
+				}()
+			}
+			wg.Wait()
+		})
+	}
+}
+
+func TestReset(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     Options
+		patterns []string
+		text     string
+	}{
+		{name: "Default", patterns: []string{"abcab", "ab", "", "abababab", "b"}, text: "abcababababab xab"},
+		{name: "Ignore case", opts: Options{IgnoreCase: true}, patterns: []string{"AB", "aBaB", "cAB"}, text: "ABcabABAB"},
+		{name: "Horspool", opts: Options{Horspool: true}, patterns: []string{"aba", "b", "abab"}, text: "ababab ba"},
+		{name: "Unicode fold", opts: Options{IgnoreCase: true, UnicodeFold: true}, patterns: []string{"café", "É"}, text: "CAFÉ é"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bm := NewWithOptions("placeholder pattern", tc.opts)
+			for _, p := range tc.patterns {
+				bm.Reset(p)
+				want := NewWithOptions(p, tc.opts)
+
+				if got, w := bm.FindAll(tc.text), want.FindAll(tc.text); !equalIntSlices(got, w) {
+					t.Errorf("after Reset(%q): FindAll = %v; want %v", p, got, w)
+				}
+				if got, w := bm.FindAllOverlapping(tc.text), want.FindAllOverlapping(tc.text); !equalIntSlices(got, w) {
+					t.Errorf("after Reset(%q): FindAllOverlapping = %v; want %v", p, got, w)
+				}
+				if got, w := bm.FindLast(tc.text), want.FindLast(tc.text); got != w {
+					t.Errorf("after Reset(%q): FindLast = %d; want %d", p, got, w)
+				}
+			}
+		})
+	}
+}
+
+// naiveIndexAll returns the starts of the matches of pattern in text found with strings.Index,
+// after lowercasing ASCII letters of both if ignoreCase is set. If overlapping is false,
+// each search resumes after the previous match.
+func naiveIndexAll(text, pattern string, ignoreCase, overlapping bool) []int {
+	results := []int{}
+	if pattern == "" {
+		return results
+	}
+	if ignoreCase {
+		text, pattern = lowerASCII(text), lowerASCII(pattern)
+	}
+	for from := 0; from <= len(text); {
+		i := strings.Index(text[from:], pattern)
+		if i < 0 {
+			break
+		}
+		results = append(results, from+i)
+		if overlapping {
+			from += i + 1
+		} else {
+			from += i + len(pattern)
+		}
+	}
+	return results
+}
+
+// lowerASCII lowercases only the ASCII letters of s, like the matchers' ignoreCase mode
+func lowerASCII(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if c >= 'A' && c <= 'Z' {
+			b[i] = c + ('a' - 'A')
+		}
+	}
+	return string(b)
+}
+
+func FuzzBoyerMoore(f *testing.F) {
+	f.Add("ushers and hers", "he", false)
+	f.Add("aaaaa", "aa", false)
+	f.Add("abcabcab", "abcab", false)
+	f.Add("ZZabcZZABCZZAbc", "AbC", true)
+	f.Add("[@`{", "@", true) // bytes next to the ASCII letter ranges
+	f.Add("\xff\xc3\xa9", "\xc3", false)
+
+	f.Fuzz(func(t *testing.T, text, pattern string, ignoreCase bool) {
+		wantAll := naiveIndexAll(text, pattern, ignoreCase, false)
+		wantOverlapping := naiveIndexAll(text, pattern, ignoreCase, true)
+		wantFirst, wantLast := -1, -1
+		if len(wantOverlapping) > 0 {
+			wantFirst, wantLast = wantOverlapping[0], wantOverlapping[len(wantOverlapping)-1]
+		}
+
+		for _, horspool := range []bool{false, true} {
+			bm := NewWithOptions(pattern, Options{IgnoreCase: ignoreCase, Horspool: horspool})
+			if got := bm.FindAll(text); !equalIntSlices(got, wantAll) {
+				t.Errorf("horspool=%v: FindAll(%q, %q) = %v; want %v", horspool, text, pattern, got, wantAll)
+			}
+			if got := bm.FindAllOverlapping(text); !equalIntSlices(got, wantOverlapping) {
+				t.Errorf("horspool=%v: FindAllOverlapping(%q, %q) = %v; want %v", horspool, text, pattern, got, wantOverlapping)
+			}
+			if got := bm.FindFirst(text); got != wantFirst {
+				t.Errorf("horspool=%v: FindFirst(%q, %q) = %d; want %d", horspool, text, pattern, got, wantFirst)
+			}
+			if got := bm.FindLast(text); got != wantLast {
+				t.Errorf("horspool=%v: FindLast(%q, %q) = %d; want %d", horspool, text, pattern, got, wantLast)
+			}
+			if got := bm.Count(text); got != len(wantAll) {
+				t.Errorf("horspool=%v: Count(%q, %q) = %d; want %d", horspool, text, pattern, got, len(wantAll))
+			}
+			if got := bm.Contains(text); got != (wantFirst >= 0) {
+				t.Errorf("horspool=%v: Contains(%q, %q) = %v; want %v", horspool, text, pattern, got, wantFirst >= 0)
+			}
+		}
+	})
+}
+
+func TestSingleBytePattern(t *testing.T) {
+	tests := []struct {
+		name       string
+		pattern    string
+		text       string
+		ignoreCase bool
+		want       []int
+	}{
+		{"Across word boundaries", "x", "0123456x89abcdefx-------x", false, []int{7, 16, 24}},
+		{"Tail shorter than a word", "z", "abcdefghijz", false, []int{10}},
+		{"Ignore case finds both cases", "q", "Qq......Q.......q", true, []int{0, 1, 8, 16}},
+		{"Uppercase pattern folds", "Q", "qQ", true, []int{0, 1}},
+		{"Non-letter ignores case", "@", "`@[@", true, []int{1, 3}},
+		{"Case sensitive", "a", "AaAAAAAAAAAa", false, []int{1, 11}},
+		{"High bytes", "\x80", "\x7f\x80\xff\x80\x00\x01\x02\x03\x80", false, []int{1, 3, 8}},
+		{"No match", "x", "abcdefghijklmnop", false, []int{}},
+		{"Empty text", "x", "", false, []int{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bm := New(tc.pattern, tc.ignoreCase)
+			if got := bm.FindAll(tc.text); !equalIntSlices(got, tc.want) {
+				t.Errorf("FindAll(%q) = %v; want %v", tc.text, got, tc.want)
+			}
+			if got := bm.FindAllOverlapping(tc.text); !equalIntSlices(got, tc.want) {
+				t.Errorf("FindAllOverlapping(%q) = %v; want %v", tc.text, got, tc.want)
+			}
+			wantFirst, wantLast := -1, -1
+			if len(tc.want) > 0 {
+				wantFirst, wantLast = tc.want[0], tc.want[len(tc.want)-1]
+			}
+			if got := bm.FindFirst(tc.text); got != wantFirst {
+				t.Errorf("FindFirst(%q) = %d; want %d", tc.text, got, wantFirst)
+			}
+			if got := bm.FindLast(tc.text); got != wantLast {
+				t.Errorf("FindLast(%q) = %d; want %d", tc.text, got, wantLast)
+			}
+			if len(tc.want) > 1 {
+				if got := bm.FindAllFrom(tc.text, tc.want[0]+1); !equalIntSlices(got, tc.want[1:]) {
+					t.Errorf("FindAllFrom(%q, %d) = %v; want %v", tc.text, tc.want[0]+1, got, tc.want[1:])
+				}
+				if got := bm.FindAllLimit(tc.text, 1); !equalIntSlices(got, tc.want[:1]) {
+					t.Errorf("FindAllLimit(%q, 1) = %v; want %v", tc.text, got, tc.want[:1])
+				}
+			}
+		})
+	}
+}
+
+// TestSingleBytePatternRandom checks the single-byte fast path against strings.Index on texts
+// long enough to run through many eight-byte words
+func TestSingleBytePatternRandom(t *testing.T) {
+	rng := rand.New(rand.NewPCG(5, 6))
+	const alphabet = "aAbB\x00\x80\xff"
+	for iter := 0; iter < 2000; iter++ {
+		b := make([]byte, rng.IntN(100))
+		for i := range b {
+			b[i] = alphabet[rng.IntN(len(alphabet))]
+		}
+		text := string(b)
+		pattern := string(alphabet[rng.IntN(len(alphabet))])
+		ignoreCase := rng.IntN(2) == 0
+
+		want := naiveIndexAll(text, pattern, ignoreCase, false)
+		if got := New(pattern, ignoreCase).FindAll(text); !equalIntSlices(got, want) {
+			t.Fatalf("FindAll(%q) with pattern %q, ignoreCase=%v = %v; want %v", text, pattern, ignoreCase, got, want)
+		}
+	}
+}
+
+func TestShortPattern(t *testing.T) {
+	tests := []struct {
+		name        string
+		pattern     string
+		text        string
+		ignoreCase  bool
+		want        []int
+		overlapping []int
+	}{
+		{"Rare byte last", "e qX", "the qX ee qx e qXe qX", false, []int{2, 13, 17}, []int{2, 13, 17}},
+		{"Rare byte in the middle", "aZa", "aZaZaZa aza", false, []int{0, 4}, []int{0, 2, 4}},
+		{"Match at the end", "oz", "ooooooooooooz", false, []int{11}, []int{11}},
+		{"Ignore case", "tHx", "THX thx tHX", true, []int{0, 4, 8}, []int{0, 4, 8}},
+		{"Text shorter than pattern", "abcd", "abc", false, []int{}, []int{}},
+		{"Repeated bytes", "aa", "aaaaa", false, []int{0, 2}, []int{0, 1, 2, 3}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bm := New(tc.pattern, tc.ignoreCase)
+			if got := bm.FindAll(tc.text); !equalIntSlices(got, tc.want) {
+				t.Errorf("FindAll(%q) = %v; want %v", tc.text, got, tc.want)
+			}
+			if got := bm.FindAllOverlapping(tc.text); !equalIntSlices(got, tc.overlapping) {
+				t.Errorf("FindAllOverlapping(%q) = %v; want %v", tc.text, got, tc.overlapping)
+			}
+		})
+	}
+}
+
+func TestShortPatternRandom(t *testing.T) {
+	rng := rand.New(rand.NewPCG(7, 8))
+	const alphabet = "aAbB eZ\x00\xff"
+	randString := func(n int) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = alphabet[rng.IntN(len(alphabet))]
+		}
+		return string(b)
+	}
+	for iter := 0; iter < 2000; iter++ {
+		text := randString(rng.IntN(100))
+		pattern := randString(2 + rng.IntN(shortPatternLen-1))
+		ignoreCase := rng.IntN(2) == 0
+
+		bm := New(pattern, ignoreCase)
+		want := naiveIndexAll(text, pattern, ignoreCase, false)
+		if got := bm.FindAll(text); !equalIntSlices(got, want) {
+			t.Fatalf("FindAll(%q) with pattern %q, ignoreCase=%v = %v; want %v", text, pattern, ignoreCase, got, want)
+		}
+		want = naiveIndexAll(text, pattern, ignoreCase, true)
+		if got := bm.FindAllOverlapping(text); !equalIntSlices(got, want) {
+			t.Fatalf("FindAllOverlapping(%q) with pattern %q, ignoreCase=%v = %v; want %v", text, pattern, ignoreCase, got, want)
+		}
+	}
+}
+
+func TestPeriodicPatternRandom(t *testing.T) {
+	// Periodic patterns over periodic texts exercise the bytes carried over between
+	// overlapping matches, with the odd mismatch breaking the runs
+	rng := rand.New(rand.NewPCG(9, 10))
+	const alphabet = "aAbc"
+	randString := func(n int) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = alphabet[rng.IntN(len(alphabet))]
+		}
+		return string(b)
+	}
+	for iter := 0; iter < 2000; iter++ {
+		period := randString(1 + rng.IntN(3))
+		pattern := strings.Repeat(period, 12)[:shortPatternLen+1+rng.IntN(8)]
+		text := []byte(strings.Repeat(period, 1+rng.IntN(40)))
+		for k := rng.IntN(3); k > 0 && len(text) > 0; k-- {
+			text[rng.IntN(len(text))] = alphabet[rng.IntN(len(alphabet))]
+		}
+		ignoreCase := rng.IntN(2) == 0
+
+		for _, opts := range []Options{{IgnoreCase: ignoreCase}, {IgnoreCase: ignoreCase, Horspool: true}} {
+			bm := NewWithOptions(pattern, opts)
+			want := naiveIndexAll(string(text), pattern, ignoreCase, true)
+			if got := bm.FindAllOverlapping(string(text)); !equalIntSlices(got, want) {
+				t.Fatalf("FindAllOverlapping(%q) with pattern %q, %+v = %v; want %v", text, pattern, opts, got, want)
+			}
+			if got := bm.CountOverlapping(string(text)); got != len(want) {
+				t.Fatalf("CountOverlapping(%q) with pattern %q, %+v = %d; want %d", text, pattern, opts, got, len(want))
+			}
+			want = naiveIndexAll(string(text), pattern, ignoreCase, false)
+			if got := bm.FindAll(string(text)); !equalIntSlices(got, want) {
+				t.Fatalf("FindAll(%q) with pattern %q, %+v = %v; want %v", text, pattern, opts, got, want)
+			}
+		}
+	}
+}
+
+func TestCountDoesNotAllocate(t *testing.T) {
+	text := strings.Repeat("abc abd ", 100)
+	for _, pattern := range []string{"ab", "abc abd"} {
+		bm := New(pattern, false)
+		want := len(bm.FindAll(text))
+		wantOverlapping := len(bm.FindAllOverlapping(text))
+		allocs := testing.AllocsPerRun(10, func() {
+			if got := bm.Count(text); got != want {
+				t.Fatalf("Count(%q) = %d; want %d", pattern, got, want)
+			}
+			if got := bm.CountOverlappingBytes([]byte(text)); got != wantOverlapping {
+				t.Fatalf("CountOverlappingBytes(%q) = %d; want %d", pattern, got, wantOverlapping)
+			}
+		})
+		if allocs != 0 {
+			t.Errorf("Count(%q) allocated %v times per run; want 0", pattern, allocs)
+		}
+	}
+}
+
+// TestEmptyPatternNeverMatches locks down the policy shared by every package in the module:
+// an empty pattern never matches, whatever the text and options, rather than matching at every position.
+func TestEmptyPatternNeverMatches(t *testing.T) {
+	checks := []struct {
+		name    string
+		matches func(bm *BoyerMoore, text string) bool
+	}{
+		{"FindAll", func(bm *BoyerMoore, text string) bool { return len(bm.FindAll(text)) > 0 }},
+		{"FindAllBytes", func(bm *BoyerMoore, text string) bool { return len(bm.FindAllBytes([]byte(text))) > 0 }},
+		{"FindAllOverlapping", func(bm *BoyerMoore, text string) bool { return len(bm.FindAllOverlapping(text)) > 0 }},
+		{"FindAllMatches", func(bm *BoyerMoore, text string) bool { return len(bm.FindAllMatches(text)) > 0 }},
+		{"FindAllRunes", func(bm *BoyerMoore, text string) bool { return len(bm.FindAllRunes(text)) > 0 }},
+		{"FindAllInto", func(bm *BoyerMoore, text string) bool { return len(bm.FindAllInto(text, nil)) > 0 }},
+		{"FindAllFrom", func(bm *BoyerMoore, text string) bool { return len(bm.FindAllFrom(text, 0)) > 0 }},
+		{"FindAllLimit", func(bm *BoyerMoore, text string) bool { return len(bm.FindAllLimit(text, 1)) > 0 }},
+		{"FindAllWholeWord", func(bm *BoyerMoore, text string) bool { return len(bm.FindAllWholeWord(text)) > 0 }},
+		{"FindAllParallel", func(bm *BoyerMoore, text string) bool { return len(bm.FindAllParallel(text, 2)) > 0 }},
+		{"FindLines", func(bm *BoyerMoore, text string) bool { return len(bm.FindLines(text)) > 0 }},
+		{"FindFirst", func(bm *BoyerMoore, text string) bool { return bm.FindFirst(text) != -1 }},
+		{"FindFirstFrom", func(bm *BoyerMoore, text string) bool { return bm.FindFirstFrom(text, 0) != -1 }},
+		{"FindLast", func(bm *BoyerMoore, text string) bool { return bm.FindLast(text) != -1 }},
+		{"Contains", func(bm *BoyerMoore, text string) bool { return bm.Contains(text) }},
+		{"Count", func(bm *BoyerMoore, text string) bool { return bm.Count(text) != 0 }},
+		{"CountOverlapping", func(bm *BoyerMoore, text string) bool { return bm.CountOverlapping(text) != 0 }},
+		{"ReplaceAll", func(bm *BoyerMoore, text string) bool { return bm.ReplaceAll(text, "x") != text }},
+		{"All", func(bm *BoyerMoore, text string) bool {
+			for range bm.All(text) {
+				return true
+			}
+			return false
+		}},
+		{"FindReader", func(bm *BoyerMoore, text string) bool {
+			first, err := bm.FindReader(strings.NewReader(text))
+			return first != -1 || err != nil
+		}},
+		{"FindAllContext", func(bm *BoyerMoore, text string) bool {
+			got, err := bm.FindAllContext(context.Background(), text)
+			return len(got) > 0 || err != nil
+		}},
+	}
+	options := []Options{{}, {IgnoreCase: true}, {IgnoreCase: true, UnicodeFold: true}, {Horspool: true}}
+
+	for _, opts := range options {
+		bm := NewWithOptions("", opts)
+		for _, text := range []string{"", "a", "abc", "naïve\n"} {
+			for _, c := range checks {
+				if c.matches(bm, text) {
+					t.Errorf("%s(%q) with %+v reported a match of the empty pattern", c.name, text, opts)
+				}
+			}
+		}
+	}
+}
+
+func TestFindAllInto(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		opts    Options
+		text    string
+	}{
+		{"No match", "xyz", Options{}, "abcabc"},
+		{"Non-overlapping", "aa", Options{}, "aaaaa"},
+		{"Short pattern", "ab", Options{}, "abcabdab"},
+		{"Long pattern", "needle", Options{}, "a needle, another needle"},
+		{"Ignore case", "NeEdLe", Options{IgnoreCase: true}, "needle NEEDLE nEEDLe"},
+		{"Unicode fold", "straße", Options{IgnoreCase: true, UnicodeFold: true}, "STRASSE Straße straße"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bm := NewWithOptions(tc.pattern, tc.opts)
+			want := bm.FindAll(tc.text)
+
+			if got := bm.FindAllInto(tc.text, nil); !slices.Equal(got, want) {
+				t.Errorf("FindAllInto(%q, nil) = %v; want %v", tc.text, got, want)
+			}
+
+			// Matches are appended after what dst already holds
+			prefix := []int{-1, -2}
+			got := bm.FindAllIntoBytes([]byte(tc.text), prefix)
+			if wantAppended := append([]int{-1, -2}, want...); !slices.Equal(got, wantAppended) {
+				t.Errorf("FindAllIntoBytes(%q, %v) = %v; want %v", tc.text, prefix, got, wantAppended)
+			}
+
+			// Passing dst[:0] reuses its storage
+			buf := got[:0]
+			if again := bm.FindAllInto(tc.text, buf); len(want) > 0 && &again[0] != &got[0] {
+				t.Errorf("FindAllInto(%q, dst[:0]) did not reuse dst", tc.text)
+			}
+		})
+	}
+}
+
+func TestFindAllIntoDoesNotAllocate(t *testing.T) {
+	texts := []string{"abc abd", "no match", "ab ab ab ab ab ab ab ab"}
+	for _, pattern := range []string{"ab", "abc abd"} {
+		bm := New(pattern, false)
+		buf := make([]int, 0, 16)
+		allocs := testing.AllocsPerRun(10, func() {
+			for _, text := range texts {
+				buf = bm.FindAllInto(text, buf[:0])
+				if want := bm.Count(text); len(buf) != want {
+					t.Fatalf("FindAllInto(%q) found %d matches; want %d", text, len(buf), want)
+				}
+			}
+		})
+		if allocs != 0 {
+			t.Errorf("FindAllInto(%q) into a large enough buffer allocated %v times per run; want 0", pattern, allocs)
+		}
+	}
+}
+
+func TestContainsDoesNotAllocate(t *testing.T) {
+	texts := []string{
+		strings.Repeat("abc abd ", 100) + "needle",
+		strings.Repeat("abc abd ", 100),
+	}
+	for _, pattern := range []string{"ne", "needle", "NEEDLE"} {
+		for _, opts := range []Options{{}, {IgnoreCase: true}, {Horspool: true}} {
+			bm := NewWithOptions(pattern, opts)
+			for _, text := range texts {
+				want := bm.FindFirst(text) != -1
+				allocs := testing.AllocsPerRun(10, func() {
+					if got := bm.Contains(text); got != want {
+						t.Fatalf("Contains(%q) = %v; want %v", pattern, got, want)
+					}
+					if got := bm.ContainsBytes([]byte(text)); got != want {
+						t.Fatalf("ContainsBytes(%q) = %v; want %v", pattern, got, want)
+					}
+				})
+				if allocs != 0 {
+					t.Errorf("Contains(%q) with %+v allocated %v times per run; want 0", pattern, opts, allocs)
+				}
+			}
+		}
+	}
+}
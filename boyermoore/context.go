@@ -0,0 +1,46 @@
+package boyermoore
+
+import "context"
+
+// contextCheckInterval is the number of text positions searched between two checks of the context
+const contextCheckInterval = 64 * 1024
+
+// FindAllContext is FindAll for searches that must be abandoned once ctx is done, e.g. over a
+// large in-memory buffer while serving a request. The text is searched in windows of 64 KiB
+// of match start positions and ctx is checked before each one, which keeps the overhead
+// negligible while bounding the work done after cancellation. On cancellation it returns
+// nil and ctx.Err(). Under rune folding the text is folded as a whole before the search starts.
+func (bm *BoyerMoore) FindAllContext(ctx context.Context, txt string) ([]int, error) {
+	return bm._findAllContext(ctx, []byte(txt))
+}
+
+// FindAllContextBytes is FindAllBytes for searches that must be abandoned once ctx is done.
+// On cancellation it returns nil and ctx.Err().
+func (bm *BoyerMoore) FindAllContextBytes(ctx context.Context, data []byte) ([]int, error) {
+	return bm._findAllContext(ctx, data)
+}
+
+// _findAllContext runs searchEach over successive windows of the haystack. Each window holds
+// contextCheckInterval start positions plus the m-1 bytes a match at the last one needs, and
+// the next window starts after both that position and the end of the last match found.
+func (bm *BoyerMoore) _findAllContext(ctx context.Context, data []byte) ([]int, error) {
+	var results []int
+	m := len(bm.pat)
+	h := bm.prepare(data)
+	for from := 0; ; {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if m == 0 || from > len(h.data)-m {
+			return results, nil
+		}
+		next := from + contextCheckInterval
+		window := h.data[:min(next+m-1, len(h.data))]
+		bm.searchEach(window, from, false, func(s int) bool {
+			results = append(results, h.orig(s))
+			next = max(next, s+m)
+			return true
+		})
+		from = next
+	}
+}
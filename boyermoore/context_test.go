@@ -0,0 +1,88 @@
+package boyermoore
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestFindAllContext(t *testing.T) {
+	tests := []struct {
+		name       string
+		pattern    string
+		text       string
+		ignoreCase bool
+	}{
+		{"Matches across windows", "abcab", strings.Repeat("xabcabcab", contextCheckInterval/4), false},
+		{"Short pattern", "ab", strings.Repeat("aab", contextCheckInterval/2), false},
+		{"Match straddling a window", "wxyz", strings.Repeat("-", contextCheckInterval-2) + "wxyz--wxyz", false},
+		{"Ignore case", "QuiCk", strings.Repeat("the quick brown fox ", contextCheckInterval/8), true},
+		{"No match", "absent", strings.Repeat("present ", 1000), false},
+		{"Empty pattern", "", "abc", false},
+		{"Empty text", "abc", "", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bm := New(tc.pattern, tc.ignoreCase)
+			want := bm.FindAll(tc.text)
+
+			got, err := bm.FindAllContext(context.Background(), tc.text)
+			if err != nil {
+				t.Fatalf("FindAllContext() error: %v", err)
+			}
+			if !equalIntSlices(got, want) {
+				t.Errorf("FindAllContext() returned %d matches, FindAll %d", len(got), len(want))
+			}
+			if got, _ := bm.FindAllContextBytes(context.Background(), []byte(tc.text)); !equalIntSlices(got, want) {
+				t.Errorf("FindAllContextBytes() returned %d matches, FindAll %d", len(got), len(want))
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			if got, err := bm.FindAllContext(ctx, tc.text); !errors.Is(err, context.Canceled) || got != nil {
+				t.Errorf("FindAllContext() on a canceled context = %v, %v; want nil, %v", got, err, context.Canceled)
+			}
+		})
+	}
+}
+
+func TestFindAllContextUnicodeFold(t *testing.T) {
+	bm := NewWithOptions("straße", Options{UnicodeFold: true})
+	text := strings.Repeat("Straße STRASSE ", contextCheckInterval/8)
+	got, err := bm.FindAllContext(context.Background(), text)
+	if err != nil {
+		t.Fatalf("FindAllContext() error: %v", err)
+	}
+	if want := bm.FindAll(text); !equalIntSlices(got, want) {
+		t.Errorf("FindAllContext() returned %d matches, FindAll %d", len(got), len(want))
+	}
+}
+
+// expiringContext reports context.Canceled from the given Err call on
+type expiringContext struct {
+	context.Context
+	calls, cancelAt int
+}
+
+func (c *expiringContext) Err() error {
+	c.calls++
+	if c.calls >= c.cancelAt {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestFindAllContextCanceledMidSearch(t *testing.T) {
+	bm := New("needle", false)
+	text := strings.Repeat("needle in a haystack ", contextCheckInterval/2)
+	ctx := &expiringContext{Context: context.Background(), cancelAt: 3}
+	got, err := bm.FindAllContext(ctx, text)
+	if !errors.Is(err, context.Canceled) || got != nil {
+		t.Fatalf("FindAllContext() = %d matches, %v; want nil, %v", len(got), err, context.Canceled)
+	}
+	if ctx.calls != 3 {
+		t.Errorf("context checked %d times; want the search to stop at the third check", ctx.calls)
+	}
+}
@@ -0,0 +1,41 @@
+package boyermoore
+
+// Cursor steps through the non-overlapping matches of a pattern in one text, one call at a
+// time, for "find next" style workflows. Successive calls to Next return the same starts that
+// FindAll would, in order; Seek moves the cursor elsewhere in the text.
+// A Cursor is not safe for concurrent use, but any number of cursors may share a matcher.
+type Cursor struct {
+	bm  *BoyerMoore
+	h   haystack
+	pos int // haystack index the next match may start at
+}
+
+// NewCursor returns a Cursor positioned at the start of the text.
+// Under UnicodeFold (or a custom Fold) the text is folded once here rather than on every Next.
+func (bm *BoyerMoore) NewCursor(txt string) *Cursor {
+	return &Cursor{bm: bm, h: bm.prepare([]byte(txt))}
+}
+
+// NewCursorBytes returns a Cursor positioned at the start of the byte slice.
+// The slice must not be modified while the cursor is in use.
+func (bm *BoyerMoore) NewCursorBytes(data []byte) *Cursor {
+	return &Cursor{bm: bm, h: bm.prepare(data)}
+}
+
+// Next returns the start of the next match and moves the cursor past its end, so that
+// matches never overlap. The second result is false once there are no more matches.
+func (c *Cursor) Next() (int, bool) {
+	s := c.bm.searchFirst(c.h.data, c.pos)
+	if s < 0 {
+		c.pos = len(c.h.data)
+		return -1, false
+	}
+	c.pos = s + len(c.bm.pat)
+	return c.h.orig(s), true
+}
+
+// Seek moves the cursor so that the next call to Next reports the first match starting at or
+// after offset, an index into the original text. A negative offset is treated as 0.
+func (c *Cursor) Seek(offset int) {
+	c.pos = c.h.index(max(offset, 0))
+}
@@ -0,0 +1,86 @@
+package boyermoore
+
+import (
+	"slices"
+	"testing"
+)
+
+func collectCursor(c *Cursor) []int {
+	var got []int
+	for {
+		s, ok := c.Next()
+		if !ok {
+			return got
+		}
+		got = append(got, s)
+	}
+}
+
+func TestCursor(t *testing.T) {
+	tests := []struct {
+		name string
+		bm   *BoyerMoore
+		text string
+		want []int
+	}{
+		{"no match", New("xyz", false), "abcabc", nil},
+		{"empty pattern", New("", false), "abc", nil},
+		{"non-overlapping", New("aa", false), "aaaaa", []int{0, 2}},
+		{"long pattern", New("hello", false), "hello, hello world hello", []int{0, 7, 19}},
+		{"ignore case", New("Go", true), "go GO gO", []int{0, 3, 6}},
+		{"unicode fold", NewWithOptions("straße", Options{IgnoreCase: true, UnicodeFold: true}), "STRASSE Straße straße", []int{8, 16}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := collectCursor(tt.bm.NewCursor(tt.text))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Next() sequence = %v, want %v", got, tt.want)
+			}
+			if want := tt.bm.FindAll(tt.text); !slices.Equal(got, want) {
+				t.Errorf("Next() sequence = %v, FindAll = %v", got, want)
+			}
+			if got := collectCursor(tt.bm.NewCursorBytes([]byte(tt.text))); !slices.Equal(got, tt.want) {
+				t.Errorf("NewCursorBytes Next() sequence = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCursorExhausted(t *testing.T) {
+	c := New("ab", false).NewCursor("abab")
+	collectCursor(c)
+	for range 2 {
+		if s, ok := c.Next(); ok || s != -1 {
+			t.Fatalf("Next() after the last match = (%d, %v), want (-1, false)", s, ok)
+		}
+	}
+}
+
+func TestCursorSeek(t *testing.T) {
+	bm := New("ab", false)
+	c := bm.NewCursor("ab ab ab ab")
+	if s, _ := c.Next(); s != 0 {
+		t.Fatalf("first Next() = %d, want 0", s)
+	}
+
+	c.Seek(4)
+	if got := collectCursor(c); !slices.Equal(got, []int{6, 9}) {
+		t.Errorf("after Seek(4): %v, want [6 9]", got)
+	}
+	c.Seek(-1)
+	if got := collectCursor(c); !slices.Equal(got, []int{0, 3, 6, 9}) {
+		t.Errorf("after Seek(-1): %v, want [0 3 6 9]", got)
+	}
+	c.Seek(100)
+	if s, ok := c.Next(); ok {
+		t.Errorf("after Seek past the end Next() = %d, want no match", s)
+	}
+
+	// offsets are in the original text even when the haystack is folded
+	u := NewWithOptions("ǆ", Options{IgnoreCase: true, UnicodeFold: true}).NewCursor("Ǆ x ǅ x ǆ")
+	u.Seek(1)
+	if got := collectCursor(u); !slices.Equal(got, []int{5, 10}) {
+		t.Errorf("folded Seek(1): %v, want [5 10]", got)
+	}
+}
@@ -0,0 +1,79 @@
+package boyermoore
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// haystack is the byte sequence the search loops actually scan.
+// Without rune folding it is the caller's data itself; with rune folding it is
+// a folded copy of the data together with the mapping back to original offsets.
+type haystack struct {
+	data []byte
+	offs []int // offs[i] is the original offset of the rune that produced data[i]; nil if data is the original
+}
+
+// prepare returns the haystack to search for the given text.
+func (bm *BoyerMoore) prepare(data []byte) haystack {
+	if bm.fold == nil {
+		return haystack{data: data}
+	}
+	return foldText(data, bm.fold)
+}
+
+// index converts an offset in the original text to the first haystack index at or after it.
+func (h haystack) index(from int) int {
+	if h.offs == nil || from <= 0 {
+		return from
+	}
+	return sort.SearchInts(h.offs, from)
+}
+
+// orig converts a haystack index (or the index one past a match) back to an offset in the original text.
+func (h haystack) orig(i int) int {
+	if h.offs == nil {
+		return i
+	}
+	return h.offs[i]
+}
+
+// foldText folds data rune by rune and records, for every folded byte, the offset
+// of the original rune it came from. Invalid UTF-8 bytes are copied unchanged.
+// offs gets one trailing entry equal to len(data) so the end of a match can be mapped too.
+func foldText(data []byte, fold func(rune) rune) haystack {
+	h := haystack{
+		data: make([]byte, 0, len(data)),
+		offs: make([]int, 0, len(data)+1),
+	}
+	for i := 0; i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		n := len(h.data)
+		if r == utf8.RuneError && size == 1 {
+			h.data = append(h.data, data[i])
+		} else {
+			h.data = utf8.AppendRune(h.data, fold(r))
+		}
+		for ; n < len(h.data); n++ {
+			h.offs = append(h.offs, i)
+		}
+		i += size
+	}
+	h.offs = append(h.offs, len(data))
+	return h
+}
+
+// foldBytes folds p rune by rune and returns the folded bytes.
+// Invalid UTF-8 bytes are copied unchanged.
+func foldBytes(p []byte, fold func(rune) rune) []byte {
+	out := make([]byte, 0, len(p))
+	for i := 0; i < len(p); {
+		r, size := utf8.DecodeRune(p[i:])
+		if r == utf8.RuneError && size == 1 {
+			out = append(out, p[i])
+		} else {
+			out = utf8.AppendRune(out, fold(r))
+		}
+		i += size
+	}
+	return out
+}
@@ -0,0 +1,44 @@
+package boyermoore
+
+import "iter"
+
+// All returns an iterator over the starting indices of all non-overlapping matches of the pattern in the text.
+// Matches are found lazily, one at a time, so breaking out of the loop stops the search.
+// The text is searched in place, except under UnicodeFold (or a custom Fold), where a folded
+// copy of the whole text and a map back to its offsets are built when iteration starts.
+func (bm *BoyerMoore) All(txt string) iter.Seq[int] {
+	return bm.all([]byte(txt))
+}
+
+// AllBytes returns an iterator over the starting indices of all non-overlapping matches of the pattern in the byte slice.
+// Matches are found lazily, one at a time, so breaking out of the loop stops the search.
+// As with All, a folded copy is built under UnicodeFold or a custom Fold.
+func (bm *BoyerMoore) AllBytes(data []byte) iter.Seq[int] {
+	return bm.all(data)
+}
+
+// all yields each match by resuming the first-match search after the previous match.
+func (bm *BoyerMoore) all(data []byte) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		m := len(bm.pat)
+		h := bm.prepare(data)
+		for s := bm.searchFirst(h.data, 0); s >= 0; s = bm.searchFirst(h.data, s+m) {
+			if !yield(h.orig(s)) {
+				return
+			}
+		}
+	}
+}
+
+// FindAllFunc calls fn with the starting index of each non-overlapping match of the pattern
+// in the text, in order, without collecting them into a slice.
+// The search stops early if fn returns false.
+func (bm *BoyerMoore) FindAllFunc(txt string, fn func(start int) bool) {
+	bm.all([]byte(txt))(fn)
+}
+
+// FindAllFuncBytes calls fn with the starting index of each non-overlapping match of the pattern
+// in the byte slice, in order. The search stops early if fn returns false.
+func (bm *BoyerMoore) FindAllFuncBytes(data []byte, fn func(start int) bool) {
+	bm.all(data)(fn)
+}
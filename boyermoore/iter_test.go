@@ -0,0 +1,106 @@
+package boyermoore
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAll(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		text    string
+		opts    Options
+	}{
+		{"Basic match", "ABC", "ZZZABCZZZABC", Options{}},
+		{"No match", "ABC", "ZZZABZ", Options{}},
+		{"Non-overlapping", "aa", "aaaaa", Options{}},
+		{"Ignore case", "AbC", "zzabcZZABC", Options{IgnoreCase: true}},
+		{"Unicode fold", "ⱥb", "xȺBxⱥb", Options{IgnoreCase: true, UnicodeFold: true}},
+		{"Empty pattern", "", "ABC", Options{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bm := NewWithOptions(tc.pattern, tc.opts)
+			want := bm.FindAll(tc.text)
+
+			got := slices.Collect(bm.All(tc.text))
+			if !equalIntSlices(got, want) {
+				t.Errorf("All(%q) = %v; want %v", tc.text, got, want)
+			}
+
+			gotBytes := slices.Collect(bm.AllBytes([]byte(tc.text)))
+			if !equalIntSlices(gotBytes, want) {
+				t.Errorf("AllBytes(%q) = %v; want %v", tc.text, gotBytes, want)
+			}
+		})
+	}
+}
+
+func TestAllBreak(t *testing.T) {
+	bm := New("ab", false)
+	var got []int
+	for s := range bm.All("ababababab") {
+		got = append(got, s)
+		if len(got) == 2 {
+			break
+		}
+	}
+	if want := []int{0, 2}; !equalIntSlices(got, want) {
+		t.Errorf("All with break = %v; want %v", got, want)
+	}
+}
+
+func TestFindAllFunc(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		text    string
+		opts    Options
+	}{
+		{"Basic match", "ABC", "ZZZABCZZZABC", Options{}},
+		{"No match", "ABC", "ZZZABZ", Options{}},
+		{"Non-overlapping", "aa", "aaaaa", Options{}},
+		{"Ignore case", "AbC", "zzabcZZABC", Options{IgnoreCase: true}},
+		{"Unicode fold", "ⱥb", "xȺBxⱥb", Options{IgnoreCase: true, UnicodeFold: true}},
+		{"Empty pattern", "", "ABC", Options{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bm := NewWithOptions(tc.pattern, tc.opts)
+			want := bm.FindAll(tc.text)
+
+			got := []int{}
+			bm.FindAllFunc(tc.text, func(start int) bool {
+				got = append(got, start)
+				return true
+			})
+			if !equalIntSlices(got, want) {
+				t.Errorf("FindAllFunc(%q) = %v; want %v", tc.text, got, want)
+			}
+
+			gotBytes := []int{}
+			bm.FindAllFuncBytes([]byte(tc.text), func(start int) bool {
+				gotBytes = append(gotBytes, start)
+				return true
+			})
+			if !equalIntSlices(gotBytes, want) {
+				t.Errorf("FindAllFuncBytes(%q) = %v; want %v", tc.text, gotBytes, want)
+			}
+		})
+	}
+}
+
+func TestFindAllFuncStopsEarly(t *testing.T) {
+	bm := New("ab", false)
+	var got []int
+	bm.FindAllFunc("ababababab", func(start int) bool {
+		got = append(got, start)
+		return len(got) < 2
+	})
+	if want := []int{0, 2}; !equalIntSlices(got, want) {
+		t.Errorf("FindAllFunc with early stop = %v; want %v", got, want)
+	}
+}
@@ -0,0 +1,77 @@
+package boyermoore
+
+import "bytes"
+
+// LineMatch locates a match of the pattern on a line of text.
+type LineMatch struct {
+	Line      int // 1-based line number
+	LineStart int // byte offset of the first byte of the line in the text
+	Column    int // byte offset of the match from LineStart, 0 for a match at the start of the line
+}
+
+// FindLines returns one LineMatch for each line of the text that contains the pattern, in order,
+// like grep -n. Lines are separated by '\n', which is not part of any line, so a pattern
+// containing '\n' never matches. A trailing '\r' is kept as part of its line.
+// Returns nil if no line matches.
+func (bm *BoyerMoore) FindLines(txt string) []LineMatch {
+	return bm._findLines([]byte(txt))
+}
+
+// FindLinesBytes returns one LineMatch for each line of the byte slice that contains the pattern.
+// Returns nil if no line matches.
+func (bm *BoyerMoore) FindLinesBytes(data []byte) []LineMatch {
+	return bm._findLines(data)
+}
+
+// _findLines searches the whole text at once rather than line by line, so the shifts can carry
+// the search across short lines. After a match the search resumes at the next line, and a
+// candidate that spans a line break is skipped by resuming one position later.
+func (bm *BoyerMoore) _findLines(data []byte) []LineMatch {
+	m := len(bm.pat)
+	if m == 0 || bytes.IndexByte(bm.pat, '\n') >= 0 {
+		return nil
+	}
+
+	var results []LineMatch
+	h := bm.prepare(data)
+	line, counted := 1, 0 // line is the number of the line holding data[counted]
+	for s := bm.searchFirst(h.data, 0); s >= 0; {
+		start, end := h.orig(s), h.orig(s+m)
+		if bytes.IndexByte(data[start:end], '\n') >= 0 {
+			s = bm.searchFirst(h.data, s+1)
+			continue
+		}
+
+		line += bytes.Count(data[counted:start], []byte{'\n'})
+		counted = start
+		lineStart := bytes.LastIndexByte(data[:start], '\n') + 1
+		results = append(results, LineMatch{Line: line, LineStart: lineStart, Column: start - lineStart})
+
+		nl := bytes.IndexByte(data[end:], '\n')
+		if nl < 0 {
+			break
+		}
+		s = bm.searchFirst(h.data, h.index(end+nl+1))
+	}
+	return results
+}
+
+// FindAllInLines searches each line on its own and returns every non-overlapping match,
+// in order, without joining the lines into one buffer. A match never spans two lines.
+// Line is the index of the line in lines plus one, and Column the match's offset within it.
+// LineStart is the total length of the preceding lines, which is the line's offset in
+// their concatenation, e.g. in the original text when the lines keep their line endings
+// as returned by bufio.Reader.ReadBytes or bytes.SplitAfter.
+// Unlike FindLines, a line with several matches yields several LineMatch values.
+// Returns nil if there are no matches.
+func (bm *BoyerMoore) FindAllInLines(lines [][]byte) []LineMatch {
+	var results []LineMatch
+	lineStart := 0
+	for i, line := range lines {
+		for _, col := range bm._findAll(line, 0, false, 0) {
+			results = append(results, LineMatch{Line: i + 1, LineStart: lineStart, Column: col})
+		}
+		lineStart += len(line)
+	}
+	return results
+}
@@ -0,0 +1,158 @@
+package boyermoore
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFindLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		text    string
+		opts    Options
+		want    []LineMatch
+	}{
+		{
+			name:    "One entry per matching line",
+			pattern: "go",
+			text:    "go go\nnothing\nlet's go\n",
+			want: []LineMatch{
+				{Line: 1, LineStart: 0, Column: 0},
+				{Line: 3, LineStart: 14, Column: 6},
+			},
+		},
+		{
+			name:    "Last line without newline",
+			pattern: "end",
+			text:    "a\nb\nthe end",
+			want:    []LineMatch{{Line: 3, LineStart: 4, Column: 4}},
+		},
+		{
+			name:    "Matches do not span lines",
+			pattern: "ab",
+			text:    "a\nb\nab",
+			want:    []LineMatch{{Line: 3, LineStart: 4, Column: 0}},
+		},
+		{
+			name:    "Carriage return stays in the line",
+			pattern: "x\r",
+			text:    "x\r\nyx\r\n",
+			want: []LineMatch{
+				{Line: 1, LineStart: 0, Column: 0},
+				{Line: 2, LineStart: 3, Column: 1},
+			},
+		},
+		{
+			name:    "Empty lines are counted",
+			pattern: "a",
+			text:    "\n\n\na\n",
+			want:    []LineMatch{{Line: 4, LineStart: 3, Column: 0}},
+		},
+		{
+			name:    "Unicode fold reports original offsets",
+			pattern: "CAFÉ",
+			text:    "À la\ncafé",
+			opts:    Options{IgnoreCase: true, UnicodeFold: true},
+			want:    []LineMatch{{Line: 2, LineStart: 6, Column: 0}},
+		},
+		{"Pattern with newline", "a\nb", "a\nb", Options{}, nil},
+		{"No match", "zzz", "abc\ndef", Options{}, nil},
+		{"Empty pattern", "", "abc", Options{}, nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bm := NewWithOptions(tc.pattern, tc.opts)
+			if got := bm.FindLines(tc.text); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("FindLines(%q) = %v; want %v", tc.text, got, tc.want)
+			}
+			if got := bm.FindLinesBytes([]byte(tc.text)); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("FindLinesBytes(%q) = %v; want %v", tc.text, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestFindLinesMatchesPerLineSearch checks FindLines against searching each line on its own
+func TestFindLinesMatchesPerLineSearch(t *testing.T) {
+	text := strings.Repeat("abcab\nbab\n\naab aba\nb\n", 3)
+	for _, pattern := range []string{"a", "ab", "ba", "b", "aba", "abcab"} {
+		bm := New(pattern, false)
+		var want []LineMatch
+		lineStart := 0
+		for i, line := range strings.Split(text, "\n") {
+			if col := bm.FindFirst(line); col >= 0 {
+				want = append(want, LineMatch{Line: i + 1, LineStart: lineStart, Column: col})
+			}
+			lineStart += len(line) + 1
+		}
+		if got := bm.FindLines(text); !reflect.DeepEqual(got, want) {
+			t.Errorf("FindLines() with pattern %q = %v; want %v", pattern, got, want)
+		}
+	}
+}
+
+func TestFindAllInLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		lines   []string
+		opts    Options
+		want    []LineMatch
+	}{
+		{
+			name:    "Every match on every line",
+			pattern: "ab",
+			lines:   []string{"abab\n", "x\n", "ab"},
+			want: []LineMatch{
+				{Line: 1, LineStart: 0, Column: 0},
+				{Line: 1, LineStart: 0, Column: 2},
+				{Line: 3, LineStart: 7, Column: 0},
+			},
+		},
+		{
+			name:    "No match across lines",
+			pattern: "ab",
+			lines:   []string{"a", "b"},
+			want:    nil,
+		},
+		{
+			name:    "Non-overlapping within a line",
+			pattern: "aa",
+			lines:   []string{"aaa", "aaaa"},
+			want: []LineMatch{
+				{Line: 1, LineStart: 0, Column: 0},
+				{Line: 2, LineStart: 3, Column: 0},
+				{Line: 2, LineStart: 3, Column: 2},
+			},
+		},
+		{
+			name:    "Unicode fold reports original offsets",
+			pattern: "é",
+			lines:   []string{"ÀÉ", "é"},
+			opts:    Options{IgnoreCase: true, UnicodeFold: true},
+			want: []LineMatch{
+				{Line: 1, LineStart: 0, Column: 2},
+				{Line: 2, LineStart: 4, Column: 0},
+			},
+		},
+		{"Empty lines", "a", []string{"", ""}, Options{}, nil},
+		{"No lines", "a", nil, Options{}, nil},
+		{"Empty pattern", "", []string{"abc"}, Options{}, nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lines := make([][]byte, len(tc.lines))
+			for i, l := range tc.lines {
+				lines[i] = []byte(l)
+			}
+			bm := NewWithOptions(tc.pattern, tc.opts)
+			if got := bm.FindAllInLines(lines); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("FindAllInLines(%q) = %v; want %v", tc.lines, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package boyermoore
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"unicode"
+)
+
+// binaryMagic and binaryVersion start every serialized matcher.
+const (
+	binaryMagic   = "BMOR"
+	binaryVersion = 2 // version 2 added the pattern as given, after the normalized one
+)
+
+// flag bits of the serialized options
+const (
+	flagIgnoreCase = 1 << iota
+	flagUnicodeFold
+	flagHorspool
+	flagCompactTable
+)
+
+// ErrCustomFold is returned by MarshalBinary for matchers built with Options.Fold,
+// since a function cannot be serialized.
+var ErrCustomFold = errors.New("boyermoore: cannot serialize a matcher with a custom Fold")
+
+// ErrInvalidBinary is returned by UnmarshalBinary for data that is not a valid serialized matcher.
+var ErrInvalidBinary = errors.New("boyermoore: invalid serialized matcher")
+
+// MarshalBinary serializes the matcher: its options, the normalized pattern, the pattern as
+// given (for Pattern) and the precomputed shift tables, forward and reversed.
+//
+// UnmarshalBinary rebuilds the tables from the pattern to check the stored ones, so loading
+// costs as much as calling New, O(len(pattern) + 256). The format is for storing or sending
+// a configured matcher, not for speeding up start-up.
+func (bm *BoyerMoore) MarshalBinary() ([]byte, error) {
+	if bm.customFold {
+		return nil, ErrCustomFold
+	}
+	buf := []byte(binaryMagic)
+	buf = append(buf, binaryVersion)
+
+	var flags uint64
+	if bm.ignoreCase {
+		flags |= flagIgnoreCase
+	}
+	if bm.fold != nil {
+		flags |= flagUnicodeFold
+	}
+	if bm.horspool {
+		flags |= flagHorspool
+	}
+	if bm.compact {
+		flags |= flagCompactTable
+	}
+	buf = binary.AppendUvarint(buf, flags)
+
+	buf = binary.AppendUvarint(buf, uint64(len(bm.pat)))
+	buf = append(buf, bm.pat...)
+	buf = binary.AppendUvarint(buf, uint64(len(bm.orig)))
+	buf = append(buf, bm.orig...)
+	if len(bm.pat) == 0 {
+		return buf, nil
+	}
+	for _, t := range []*BoyerMoore{bm, bm.rev} {
+		for _, v := range t.bcShift {
+			buf = binary.AppendVarint(buf, int64(v))
+		}
+		for _, v := range t.gsShift {
+			buf = binary.AppendUvarint(buf, uint64(v))
+		}
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary restores a matcher serialized by MarshalBinary, replacing the receiver's contents.
+// The header is validated, the pattern must be normalized for the stored options, and every
+// table entry must equal the one rebuilt from the pattern; malformed data yields an error
+// wrapping ErrInvalidBinary and leaves the receiver unchanged. Data written before the pattern
+// as given was stored (version 1) still loads, and Pattern then returns the normalized pattern.
+func (bm *BoyerMoore) UnmarshalBinary(data []byte) error {
+	if len(data) < len(binaryMagic)+1 || string(data[:len(binaryMagic)]) != binaryMagic {
+		return fmt.Errorf("%w: bad magic header", ErrInvalidBinary)
+	}
+	version := data[len(binaryMagic)]
+	if version < 1 || version > binaryVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidBinary, version)
+	}
+	data = data[len(binaryMagic)+1:]
+
+	flags, n := binary.Uvarint(data)
+	if n <= 0 || flags&^(flagIgnoreCase|flagUnicodeFold|flagHorspool|flagCompactTable) != 0 {
+		return fmt.Errorf("%w: bad options", ErrInvalidBinary)
+	}
+	data = data[n:]
+	m, n := binary.Uvarint(data)
+	if n <= 0 || m > uint64(len(data)-n) {
+		return fmt.Errorf("%w: bad pattern length", ErrInvalidBinary)
+	}
+	data = data[n:]
+
+	res := &BoyerMoore{
+		ignoreCase: flags&flagIgnoreCase != 0,
+		horspool:   flags&flagHorspool != 0,
+		compact:    flags&flagCompactTable != 0,
+	}
+	if flags&flagUnicodeFold != 0 {
+		res.fold = unicode.ToLower
+	}
+	// Build the tables as New would, then require the stored ones to match them
+	res.setPatternBytes(append([]byte{}, data[:m]...))
+	if !bytes.Equal(res.pat, data[:m]) {
+		return fmt.Errorf("%w: pattern not normalized for its options", ErrInvalidBinary)
+	}
+	data = data[m:]
+	if version >= 2 {
+		k, n := binary.Uvarint(data)
+		if n <= 0 || k > uint64(len(data)-n) {
+			return fmt.Errorf("%w: bad pattern length", ErrInvalidBinary)
+		}
+		res.orig = string(data[n : n+int(k)])
+		data = data[n+int(k):]
+	}
+
+	if m > 0 {
+		for _, t := range []*BoyerMoore{res, res.rev} {
+			for _, want := range t.bcShift {
+				v, n := binary.Varint(data)
+				if n <= 0 || v != int64(want) {
+					return fmt.Errorf("%w: bad character table does not match the pattern", ErrInvalidBinary)
+				}
+				data = data[n:]
+			}
+			for _, want := range t.gsShift {
+				v, n := binary.Uvarint(data)
+				if n <= 0 || v != uint64(want) {
+					return fmt.Errorf("%w: good suffix table does not match the pattern", ErrInvalidBinary)
+				}
+				data = data[n:]
+			}
+		}
+	}
+	if len(data) != 0 {
+		return fmt.Errorf("%w: trailing data", ErrInvalidBinary)
+	}
+
+	*bm = *res
+	return nil
+}
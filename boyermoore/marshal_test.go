@@ -0,0 +1,143 @@
+package boyermoore
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMarshalBinary(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		opts    Options
+		text    string
+	}{
+		{name: "Basic", pattern: "ABC", text: "ZZABCZZABCABC"},
+		{name: "Periodic", pattern: "abab", text: "abababab ababab"},
+		{name: "Ignore case", pattern: "AbC", opts: Options{IgnoreCase: true}, text: "abc ABC aBc"},
+		{name: "Horspool", pattern: "abcab", opts: Options{Horspool: true}, text: "abcabcab xabcab"},
+		{name: "Compact table", pattern: "ACGTTA", opts: Options{CompactTable: true}, text: "ACGTTACGTTANACGTTA"},
+		{name: "Compact Horspool table", pattern: "ACGTTA", opts: Options{CompactTable: true, Horspool: true}, text: "ACGTTACGTTANACGTTA"},
+		{name: "Unicode fold", pattern: "café", opts: Options{IgnoreCase: true, UnicodeFold: true}, text: "CAFÉ café Café"},
+		{name: "Empty pattern", pattern: "", text: "ABC"},
+		{name: "Long pattern", pattern: strings.Repeat("ab", 500) + "c", text: strings.Repeat("ab", 1200) + "c"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			orig := NewWithOptions(tc.pattern, tc.opts)
+			data, err := orig.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary returned error: %v", err)
+			}
+			var loaded BoyerMoore
+			if err := loaded.UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary returned error: %v", err)
+			}
+
+			if got, want := loaded.FindAll(tc.text), orig.FindAll(tc.text); !equalIntSlices(got, want) {
+				t.Errorf("FindAll = %v; want %v", got, want)
+			}
+			if got, want := loaded.FindAllOverlapping(tc.text), orig.FindAllOverlapping(tc.text); !equalIntSlices(got, want) {
+				t.Errorf("FindAllOverlapping = %v; want %v", got, want)
+			}
+			if got, want := loaded.FindAllMatches(tc.text), orig.FindAllMatches(tc.text); !reflect.DeepEqual(got, want) {
+				t.Errorf("FindAllMatches = %v; want %v", got, want)
+			}
+			if got, want := loaded.FindLast(tc.text), orig.FindLast(tc.text); got != want {
+				t.Errorf("FindLast = %d; want %d", got, want)
+			}
+			if got := loaded.Pattern(); got != tc.pattern {
+				t.Errorf("Pattern = %q; want %q", got, tc.pattern)
+			}
+			if !loaded.Equal(orig) {
+				t.Errorf("loaded matcher is not Equal to the original")
+			}
+		})
+	}
+}
+
+func TestUnmarshalBinaryVersion1(t *testing.T) {
+	data, err := New("AbC", true).MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary returned error: %v", err)
+	}
+	// Drop the pattern as given, which follows the one-byte flags, the one-byte length and
+	// the 3-byte normalized pattern, to get what version 1 wrote
+	head := len(binaryMagic) + 1 + 1 + 1 + 3
+	v1 := append(append([]byte(nil), data[:head]...), data[head+1+3:]...)
+	v1[len(binaryMagic)] = 1
+
+	var bm BoyerMoore
+	if err := bm.UnmarshalBinary(v1); err != nil {
+		t.Fatalf("UnmarshalBinary of version 1 data returned error: %v", err)
+	}
+	if got, want := bm.FindAll("abc ABC"), []int{0, 4}; !equalIntSlices(got, want) {
+		t.Errorf("FindAll = %v; want %v", got, want)
+	}
+	// Version 1 only kept the normalized pattern
+	if got := bm.Pattern(); got != "abc" {
+		t.Errorf("Pattern = %q; want %q", got, "abc")
+	}
+}
+
+func TestUnmarshalBinaryInvalid(t *testing.T) {
+	valid, err := New("abcab", true).MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary returned error: %v", err)
+	}
+
+	badVersion := append([]byte(nil), valid...)
+	badVersion[len(binaryMagic)] = binaryVersion + 1
+
+	// tampered serializes a matcher for "abcd" after edit corrupts it; every entry
+	// stays in range, so only rebuilding the tables can tell
+	tampered := func(opts Options, edit func(bm *BoyerMoore)) []byte {
+		bm := NewWithOptions("abcd", opts)
+		edit(bm)
+		data, err := bm.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary returned error: %v", err)
+		}
+		return data
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "Empty", data: nil},
+		{name: "Bad magic", data: append([]byte("MOOR"), valid[len(binaryMagic):]...)},
+		{name: "Unknown version", data: badVersion},
+		{name: "Trailing data", data: append(append([]byte(nil), valid...), 0)},
+		{name: "Truncated header", data: valid[:len(binaryMagic)+2]},
+		{name: "Truncated tables", data: valid[:len(valid)-1]},
+		{name: "Wrong good suffix shift", data: tampered(Options{}, func(bm *BoyerMoore) { bm.gsShift[0] = 1 })},
+		{name: "Wrong reversed good suffix shift", data: tampered(Options{}, func(bm *BoyerMoore) { bm.rev.gsShift[0] = 1 })},
+		{name: "Wrong bad character shift", data: tampered(Options{}, func(bm *BoyerMoore) { bm.bcShift['c'] = 0 })},
+		{name: "Wrong Horspool shift", data: tampered(Options{Horspool: true}, func(bm *BoyerMoore) { bm.bcShift['x'] = 2 })},
+		{name: "Pattern not folded", data: tampered(Options{IgnoreCase: true}, func(bm *BoyerMoore) { bm.pat[0] = 'A' })},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bm := New("x", false)
+			if err := bm.UnmarshalBinary(tc.data); !errors.Is(err, ErrInvalidBinary) {
+				t.Fatalf("UnmarshalBinary error = %v; want %v", err, ErrInvalidBinary)
+			}
+			// A failed load leaves the receiver untouched
+			if got := bm.Count("xx"); got != 2 {
+				t.Errorf("Count after failed UnmarshalBinary = %d; want 2", got)
+			}
+		})
+	}
+}
+
+func TestMarshalBinaryCustomFold(t *testing.T) {
+	bm := NewWithOptions("abc", Options{IgnoreCase: true, Fold: func(r rune) rune { return r }})
+	if _, err := bm.MarshalBinary(); !errors.Is(err, ErrCustomFold) {
+		t.Errorf("MarshalBinary error = %v; want %v", err, ErrCustomFold)
+	}
+}
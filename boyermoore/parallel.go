@@ -0,0 +1,91 @@
+package boyermoore
+
+import (
+	"runtime"
+	"sync"
+)
+
+// FindAllParallel returns the same indices as FindAll, splitting the search across up to
+// workers goroutines. Each goroutine scans its own chunk of the text, extended by
+// len(pattern)-1 bytes so that matches straddling a boundary are seen by exactly the
+// chunk they start in. The matcher is read-only during a search, so this is safe.
+// With workers <= 0, runtime.GOMAXPROCS(0) goroutines are used. The count is capped at
+// len(txt)/len(pattern), so every chunk is at least as long as the pattern; with a single
+// worker left, or workers == 1, the search runs on the calling goroutine.
+// Splitting only pays off for large texts; for small ones the goroutine overhead dominates.
+func (bm *BoyerMoore) FindAllParallel(txt string, workers int) []int {
+	return bm._findAllParallel([]byte(txt), workers)
+}
+
+// FindAllParallelBytes returns the same indices as FindAllBytes, splitting the search across
+// up to workers goroutines.
+func (bm *BoyerMoore) FindAllParallelBytes(data []byte, workers int) []int {
+	return bm._findAllParallel(data, workers)
+}
+
+// _findAllParallel collects every occurrence, overlapping ones included, chunk by chunk and
+// then keeps the leftmost occurrence at or after the end of the previous kept one. This is
+// exactly what the sequential non-overlapping scan reports, whatever the chunk boundaries.
+func (bm *BoyerMoore) _findAllParallel(data []byte, workers int) []int {
+	m := len(bm.pat)
+	h := bm.prepare(data)
+	n := len(h.data)
+	if workers = parallelWorkers(workers, n, m); workers <= 1 {
+		// Search the haystack already prepared rather than folding the text again
+		results := bm.searchAll(h.data, 0, false, 0)
+		if h.offs != nil {
+			for i, s := range results {
+				results[i] = h.orig(s)
+			}
+		}
+		return results
+	}
+
+	chunk := (n + workers - 1) / workers
+	parts := make([][]int, 0, workers)
+	for lo := 0; lo < n; lo += chunk {
+		parts = append(parts, nil)
+	}
+
+	var wg sync.WaitGroup
+	for w := range parts {
+		lo := w * chunk
+		hi := min(lo+chunk+m-1, n)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			starts := bm.searchAll(h.data[lo:hi], 0, true, 0)
+			for i := range starts {
+				starts[i] += lo
+			}
+			parts[w] = starts
+		}()
+	}
+	wg.Wait()
+
+	results := []int{}
+	next := 0 // first index a kept match may start at
+	for _, starts := range parts {
+		for _, s := range starts {
+			if s >= next {
+				results = append(results, h.orig(s))
+				next = s + m
+			}
+		}
+	}
+	return results
+}
+
+// parallelWorkers returns how many goroutines search a haystack of n bytes for a pattern of
+// m bytes when workers were requested: GOMAXPROCS for workers <= 0, and never more than n/m,
+// which keeps each chunk at least a pattern long so its m-1 bytes of overlap stay a minority.
+// Zero means there is nothing to search.
+func parallelWorkers(workers, n, m int) int {
+	if m == 0 {
+		return 0
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return min(workers, n/m)
+}
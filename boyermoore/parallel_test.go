@@ -0,0 +1,121 @@
+package boyermoore
+
+import (
+	"math/rand/v2"
+	"runtime"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+func TestFindAllParallel(t *testing.T) {
+	// With 4 workers over 40 bytes, chunks start at 0, 10, 20 and 30
+	straddling := strings.Repeat("x", 8) + "abcd" + strings.Repeat("x", 16) + "abcd" + strings.Repeat("x", 8)
+
+	tests := []struct {
+		name    string
+		pattern string
+		text    string
+		opts    Options
+		workers int
+		want    []int
+	}{
+		{name: "Straddling boundaries", pattern: "abcd", text: straddling, workers: 4, want: []int{8, 28}},
+		{name: "Match in every chunk", pattern: "ab", text: strings.Repeat("ab", 8), workers: 3, want: []int{0, 2, 4, 6, 8, 10, 12, 14}},
+		{name: "Overlap resolved like FindAll", pattern: "aaa", text: strings.Repeat("a", 25), workers: 4, want: []int{0, 3, 6, 9, 12, 15, 18, 21}},
+		{name: "More workers than bytes", pattern: "ab", text: "xxabxxab", workers: 100, want: []int{2, 6}},
+		{name: "Single worker", pattern: "ab", text: "xxabxxab", workers: 1, want: []int{2, 6}},
+		{name: "Ignore case", pattern: "AB", text: "ab xx AB xx aB", opts: Options{IgnoreCase: true}, workers: 3, want: []int{0, 6, 12}},
+		{name: "Unicode fold", pattern: "é", text: "É é xx É", opts: Options{IgnoreCase: true, UnicodeFold: true}, workers: 3, want: []int{0, 3, 9}},
+		{name: "Unicode fold single worker", pattern: "é", text: "É é xx É", opts: Options{IgnoreCase: true, UnicodeFold: true}, workers: 1, want: []int{0, 3, 9}},
+		{name: "Unicode fold text too short to split", pattern: "éé", text: "xÉé", opts: Options{IgnoreCase: true, UnicodeFold: true}, workers: 4, want: []int{1}},
+		{name: "No match", pattern: "zz", text: strings.Repeat("ab", 20), workers: 4, want: []int{}},
+		{name: "Empty pattern", pattern: "", text: "abc", workers: 2, want: []int{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bm := NewWithOptions(tc.pattern, tc.opts)
+			if got := bm.FindAllParallel(tc.text, tc.workers); !equalIntSlices(got, tc.want) {
+				t.Errorf("FindAllParallel(%q, %d) = %v; want %v", tc.text, tc.workers, got, tc.want)
+			}
+			if got := bm.FindAllParallelBytes([]byte(tc.text), tc.workers); !equalIntSlices(got, tc.want) {
+				t.Errorf("FindAllParallelBytes(%q, %d) = %v; want %v", tc.text, tc.workers, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFindAllParallelWorkers(t *testing.T) {
+	procs := runtime.GOMAXPROCS(0)
+	tests := []struct {
+		name          string
+		workers, n, m int
+		want          int
+	}{
+		{name: "Requested count", workers: 4, n: 1000, m: 10, want: 4},
+		{name: "Huge count capped by chunk length", workers: 1 << 20, n: 1000, m: 10, want: 100},
+		{name: "Text shorter than two patterns", workers: 8, n: 15, m: 10, want: 1},
+		{name: "Text shorter than the pattern", workers: 8, n: 5, m: 10, want: 0},
+		{name: "Zero means GOMAXPROCS", workers: 0, n: 1 << 20, m: 1, want: procs},
+		{name: "Negative means GOMAXPROCS", workers: -3, n: 1 << 20, m: 1, want: procs},
+		{name: "Empty pattern", workers: 4, n: 1000, m: 0, want: 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := parallelWorkers(tc.workers, tc.n, tc.m); got != tc.want {
+				t.Errorf("parallelWorkers(%d, %d, %d) = %d; want %d", tc.workers, tc.n, tc.m, got, tc.want)
+			}
+		})
+	}
+
+	// A huge or non-positive worker count still finds what FindAll does
+	bm := New("ab", false)
+	text := strings.Repeat("xab", 50)
+	want := bm.FindAll(text)
+	for _, workers := range []int{1 << 20, 0, -1} {
+		if got := bm.FindAllParallel(text, workers); !equalIntSlices(got, want) {
+			t.Errorf("FindAllParallel(%q, %d) = %v; want %v", text, workers, got, want)
+		}
+	}
+}
+
+// TestFindAllParallelFoldsOnce checks that the sequential fallback searches the text
+// folded for the split decision instead of folding it again
+func TestFindAllParallelFoldsOnce(t *testing.T) {
+	calls := 0
+	bm := NewWithOptions("b", Options{IgnoreCase: true, Fold: func(r rune) rune {
+		calls++
+		return unicode.ToLower(r)
+	}})
+	text := "aBcb"
+	for _, workers := range []int{1, 4} {
+		calls = 0
+		if got, want := bm.FindAllParallel(text, workers), []int{1, 3}; !equalIntSlices(got, want) {
+			t.Errorf("FindAllParallel(%q, %d) = %v; want %v", text, workers, got, want)
+		}
+		if calls != len(text) {
+			t.Errorf("FindAllParallel(%q, %d) folded %d runes; want %d", text, workers, calls, len(text))
+		}
+	}
+}
+
+func TestFindAllParallelRandom(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	randString := func(n int) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = "ab"[rng.IntN(2)]
+		}
+		return string(b)
+	}
+
+	for i := 0; i < 500; i++ {
+		bm := New(randString(1+rng.IntN(4)), false)
+		text := randString(rng.IntN(200))
+		workers := 1 + rng.IntN(8)
+		if got, want := bm.FindAllParallel(text, workers), bm.FindAll(text); !equalIntSlices(got, want) {
+			t.Fatalf("FindAllParallel(%q, %d) = %v; want %v", text, workers, got, want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package boyermoore
+
+import "unicode/utf8"
+
+// MatchesPrefix reports whether txt begins with the pattern, with case folded as in every
+// other search, like strings.HasPrefix for the matcher's pattern. Only the first len(pattern)
+// bytes of txt are read (or, when the matcher folds runes, as many runes as it takes to fold
+// that many bytes), so it costs O(m) rather than a scan of the text.
+func (bm *BoyerMoore) MatchesPrefix(txt string) bool {
+	return bm.matchesPrefix([]byte(txt))
+}
+
+// MatchesPrefixBytes reports whether the byte slice begins with the pattern.
+func (bm *BoyerMoore) MatchesPrefixBytes(data []byte) bool {
+	return bm.matchesPrefix(data)
+}
+
+// MatchesSuffix reports whether txt ends with the pattern, with case handled as in every
+// other search, like strings.HasSuffix. Like MatchesPrefix it reads only the end of txt.
+func (bm *BoyerMoore) MatchesSuffix(txt string) bool {
+	return bm.matchesSuffix([]byte(txt))
+}
+
+// MatchesSuffixBytes reports whether the byte slice ends with the pattern.
+func (bm *BoyerMoore) MatchesSuffixBytes(data []byte) bool {
+	return bm.matchesSuffix(data)
+}
+
+// matchesPrefix compares the pattern with the start of data, folding data one rune at a
+// time when the matcher folds runes so that no folded copy of the text is built.
+func (bm *BoyerMoore) matchesPrefix(data []byte) bool {
+	m := len(bm.pat)
+	if m == 0 {
+		return false
+	}
+	if bm.fold == nil {
+		if len(data) < m {
+			return false
+		}
+		for j := 0; j < m; j++ {
+			if bm.normChar(data[j]) != bm.pat[j] {
+				return false
+			}
+		}
+		return true
+	}
+
+	var buf [utf8.UTFMax]byte
+	j := 0
+	for i := 0; j < m && i < len(data); {
+		r, size := utf8.DecodeRune(data[i:])
+		folded := bm.foldRune(buf[:0], r, size, data[i])
+		for k := 0; k < len(folded) && j < m; k++ {
+			if folded[k] != bm.pat[j] {
+				return false
+			}
+			j++
+		}
+		i += size
+	}
+	return j == m
+}
+
+// matchesSuffix is matchesPrefix comparing the pattern backwards with the end of data.
+func (bm *BoyerMoore) matchesSuffix(data []byte) bool {
+	m := len(bm.pat)
+	if m == 0 {
+		return false
+	}
+	if bm.fold == nil {
+		if len(data) < m {
+			return false
+		}
+		off := len(data) - m
+		for j := 0; j < m; j++ {
+			if bm.normChar(data[off+j]) != bm.pat[j] {
+				return false
+			}
+		}
+		return true
+	}
+
+	var buf [utf8.UTFMax]byte
+	j := m
+	for i := len(data); j > 0 && i > 0; {
+		r, size := utf8.DecodeLastRune(data[:i])
+		folded := bm.foldRune(buf[:0], r, size, data[i-1])
+		for k := len(folded) - 1; k >= 0 && j > 0; k-- {
+			j--
+			if folded[k] != bm.pat[j] {
+				return false
+			}
+		}
+		i -= size
+	}
+	return j == 0
+}
+
+// foldRune appends to dst the folded form of the rune r that was decoded from size bytes,
+// the way foldText folds it: an invalid byte, passed as raw, is copied unchanged.
+func (bm *BoyerMoore) foldRune(dst []byte, r rune, size int, raw byte) []byte {
+	if r == utf8.RuneError && size == 1 {
+		return append(dst, raw)
+	}
+	return utf8.AppendRune(dst, bm.fold(r))
+}
@@ -0,0 +1,81 @@
+package boyermoore
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+func TestMatchesPrefixSuffix(t *testing.T) {
+	unicodeFold := Options{IgnoreCase: true, UnicodeFold: true}
+	tests := []struct {
+		name           string
+		bm             *BoyerMoore
+		text           string
+		prefix, suffix bool
+	}{
+		{"Both", New("ab", false), "ab", true, true},
+		{"Prefix only", New("ab", false), "abc", true, false},
+		{"Suffix only", New("bc", false), "abc", false, true},
+		{"Middle only", New("b", false), "abc", false, false},
+		{"Text shorter than pattern", New("abcd", false), "abc", false, false},
+		{"Empty text", New("a", false), "", false, false},
+		{"Empty pattern", New("", false), "abc", false, false},
+		{"Case-sensitive", New("AB", false), "abAB", false, true},
+		{"Ignore case", New("Ab", true), "aBxAB", true, true},
+		{"Unicode fold", NewWithOptions("CAFÉ", unicodeFold), "café au lait au CAFÉ", true, true},
+		{"Unicode fold needs IgnoreCase", NewWithOptions("É", Options{UnicodeFold: true}), "é", false, false},
+		{"Fold changes the byte length", NewWithOptions("ik", unicodeFold), "İKİ İK", true, true},
+		{"Pattern ends inside a folded rune", NewWithOptions("a\xc3", unicodeFold), "AÉ", true, false},
+		{"Invalid UTF-8", NewWithOptions("\xffA", unicodeFold), "\xffa \xffA", true, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.bm.MatchesPrefix(tc.text); got != tc.prefix {
+				t.Errorf("MatchesPrefix(%q) = %v; want %v", tc.text, got, tc.prefix)
+			}
+			if got := tc.bm.MatchesPrefixBytes([]byte(tc.text)); got != tc.prefix {
+				t.Errorf("MatchesPrefixBytes(%q) = %v; want %v", tc.text, got, tc.prefix)
+			}
+			if got := tc.bm.MatchesSuffix(tc.text); got != tc.suffix {
+				t.Errorf("MatchesSuffix(%q) = %v; want %v", tc.text, got, tc.suffix)
+			}
+			if got := tc.bm.MatchesSuffixBytes([]byte(tc.text)); got != tc.suffix {
+				t.Errorf("MatchesSuffixBytes(%q) = %v; want %v", tc.text, got, tc.suffix)
+			}
+		})
+	}
+}
+
+// TestMatchesPrefixSuffixRandom checks the incremental folding against the folded haystack
+// every search scans
+func TestMatchesPrefixSuffixRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	alphabet := []string{"a", "A", "é", "É", "İ", "i", "ß", "\xff", "\xc3"}
+	gen := func(n int) string {
+		var b []byte
+		for range n {
+			b = append(b, alphabet[rng.Intn(len(alphabet))]...)
+		}
+		return string(b)
+	}
+	for _, opts := range []Options{{}, {IgnoreCase: true}, {IgnoreCase: true, UnicodeFold: true}} {
+		for range 2000 {
+			bm := NewWithOptions(gen(1+rng.Intn(3)), opts)
+			text := gen(rng.Intn(5))
+			h := bm.prepare([]byte(text)).data
+			if bm.fold == nil {
+				for i, c := range h {
+					h[i] = bm.normChar(c)
+				}
+			}
+			if got, want := bm.MatchesPrefix(text), bytes.HasPrefix(h, bm.pat); got != want {
+				t.Errorf("%+v: New(%q).MatchesPrefix(%q) = %v; want %v", opts, bm.Pattern(), text, got, want)
+			}
+			if got, want := bm.MatchesSuffix(text), bytes.HasSuffix(h, bm.pat); got != want {
+				t.Errorf("%+v: New(%q).MatchesSuffix(%q) = %v; want %v", opts, bm.Pattern(), text, got, want)
+			}
+		}
+	}
+}
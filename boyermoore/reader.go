@@ -0,0 +1,97 @@
+package boyermoore
+
+import (
+	"io"
+	"unicode/utf8"
+)
+
+// readChunkSize is the number of bytes requested from the reader per read.
+const readChunkSize = 32 * 1024
+
+// FindReader returns the absolute byte offset of the first occurrence of the pattern in the stream.
+// Returns -1 and a nil error if the stream ends without a match.
+// Errors other than io.EOF returned by the reader are passed through.
+func (bm *BoyerMoore) FindReader(r io.Reader) (int, error) {
+	first := -1
+	err := bm.FindAllReader(r, func(offset int) bool {
+		first = offset
+		return false
+	})
+	return first, err
+}
+
+// FindAllReader calls fn with the absolute byte offset of every non-overlapping match of the pattern in the stream.
+// The search stops early if fn returns false.
+// Matches straddling two reads are found by carrying the last len(pattern)-1 bytes over to the next read
+// (more with UnicodeFold, where a folded pattern byte may stand for a longer original rune).
+// Short reads, reads returning zero bytes with a nil error, and data returned together with io.EOF
+// are all handled, so decompressing readers such as a gzip.Reader can be searched directly:
+//
+//	zr, err := gzip.NewReader(f)
+//	if err != nil {
+//		return err
+//	}
+//	err = bm.FindAllReader(zr, func(offset int) bool {
+//		fmt.Println(offset) // offset into the decompressed data
+//		return true
+//	})
+//
+// Returns nil when the stream is exhausted, or the first error other than io.EOF returned by the reader.
+func (bm *BoyerMoore) FindAllReader(r io.Reader, fn func(offset int) bool) error {
+	m := len(bm.pat)
+	if m == 0 {
+		return nil
+	}
+
+	span := bm.matchSpan()
+	size := readChunkSize
+	if size < 2*span {
+		size = 2 * span
+	}
+	buf := make([]byte, 0, size)
+	base := 0 // absolute offset of buf[0]
+	from := 0 // position in buf where the next match may start
+
+	for {
+		n, err := r.Read(buf[len(buf):cap(buf)])
+		buf = buf[:len(buf)+n]
+
+		if n > 0 {
+			h := bm.prepare(buf)
+			for _, s := range bm.searchAll(h.data, h.index(from), false, 0) {
+				if !fn(base + h.orig(s)) {
+					return nil
+				}
+				from = h.orig(s + m)
+			}
+
+			// Keep the bytes that may still begin a match,
+			// never re-examining bytes covered by a reported match
+			keep := len(buf) - (span - 1)
+			if keep < from {
+				keep = from
+			}
+			if keep > 0 {
+				base += keep
+				buf = buf[:copy(buf, buf[keep:])]
+			}
+			from = 0
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// matchSpan returns the longest stretch of input a match can cover. With rune folding
+// a folded byte may come from a longer original rune.
+func (bm *BoyerMoore) matchSpan() int {
+	if bm.fold != nil {
+		return utf8.UTFMax * len(bm.pat)
+	}
+	return len(bm.pat)
+}
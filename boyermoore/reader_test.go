@@ -0,0 +1,216 @@
+package boyermoore
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestReaderSearch(t *testing.T) {
+	long := strings.Repeat("x", readChunkSize-2) + "needle" + strings.Repeat("y", 10) + "needle"
+
+	tests := []struct {
+		name       string
+		pattern    string
+		text       string
+		ignoreCase bool
+		wantAll    []int
+		wantFirst  int
+	}{
+		{
+			name:      "Basic match",
+			pattern:   "ABC",
+			text:      "ZZZABCZZZABC",
+			wantAll:   []int{3, 9},
+			wantFirst: 3,
+		},
+		{
+			name:      "No match",
+			pattern:   "ABC",
+			text:      "ZZZABZ",
+			wantAll:   []int{},
+			wantFirst: -1,
+		},
+		{
+			name:      "Non-overlapping",
+			pattern:   "aa",
+			text:      "aaaaa",
+			wantAll:   []int{0, 2},
+			wantFirst: 0,
+		},
+		{
+			name:       "Ignore case",
+			pattern:    "AbC",
+			text:       "zzabcZZABC",
+			ignoreCase: true,
+			wantAll:    []int{2, 7},
+			wantFirst:  2,
+		},
+		{
+			name:      "Match straddling chunk boundary",
+			pattern:   "needle",
+			text:      long,
+			wantAll:   []int{readChunkSize - 2, readChunkSize + 14},
+			wantFirst: readChunkSize - 2,
+		},
+		{
+			name:      "Empty pattern",
+			pattern:   "",
+			text:      "ABC",
+			wantAll:   []int{},
+			wantFirst: -1,
+		},
+		{
+			name:      "Empty text",
+			pattern:   "ABC",
+			text:      "",
+			wantAll:   []int{},
+			wantFirst: -1,
+		},
+	}
+
+	readers := map[string]func(string) io.Reader{
+		"full":     func(s string) io.Reader { return strings.NewReader(s) },
+		"one byte": func(s string) io.Reader { return iotest.OneByteReader(strings.NewReader(s)) },
+		"half":     func(s string) io.Reader { return iotest.HalfReader(strings.NewReader(s)) },
+		"data+EOF": func(s string) io.Reader { return iotest.DataErrReader(strings.NewReader(s)) },
+		"zero":     func(s string) io.Reader { return &zeroReader{r: iotest.HalfReader(strings.NewReader(s))} },
+	}
+
+	for _, tc := range tests {
+		for rname, newReader := range readers {
+			t.Run(tc.name+"/"+rname, func(t *testing.T) {
+				bm := New(tc.pattern, tc.ignoreCase)
+
+				gotAll := []int{}
+				err := bm.FindAllReader(newReader(tc.text), func(offset int) bool {
+					gotAll = append(gotAll, offset)
+					return true
+				})
+				if err != nil {
+					t.Fatalf("FindAllReader returned error: %v", err)
+				}
+				if !equalIntSlices(gotAll, tc.wantAll) {
+					t.Errorf("FindAllReader = %v; want %v", gotAll, tc.wantAll)
+				}
+
+				gotFirst, err := bm.FindReader(newReader(tc.text))
+				if err != nil {
+					t.Fatalf("FindReader returned error: %v", err)
+				}
+				if gotFirst != tc.wantFirst {
+					t.Errorf("FindReader = %d; want %d", gotFirst, tc.wantFirst)
+				}
+			})
+		}
+	}
+}
+
+func TestReaderSearchUnicodeFold(t *testing.T) {
+	bm := NewWithOptions("ⱥb", Options{IgnoreCase: true, UnicodeFold: true})
+	text := "xȺBxⱥbȺ"
+
+	got := []int{}
+	err := bm.FindAllReader(iotest.OneByteReader(strings.NewReader(text)), func(offset int) bool {
+		got = append(got, offset)
+		return true
+	})
+	if err != nil {
+		t.Fatalf("FindAllReader returned error: %v", err)
+	}
+	if want := []int{1, 5}; !equalIntSlices(got, want) {
+		t.Errorf("FindAllReader = %v; want %v", got, want)
+	}
+}
+
+func TestReaderSearchStopsEarly(t *testing.T) {
+	bm := New("ab", false)
+	calls := 0
+	err := bm.FindAllReader(strings.NewReader("ababab"), func(offset int) bool {
+		calls++
+		return false
+	})
+	if err != nil {
+		t.Fatalf("FindAllReader returned error: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("callback called %d times; want 1", calls)
+	}
+}
+
+func TestReaderSearchError(t *testing.T) {
+	wantErr := errors.New("read failed")
+	r := io.MultiReader(strings.NewReader("zzz"), iotest.ErrReader(wantErr))
+
+	pos, err := New("abc", false).FindReader(r)
+	if !errors.Is(err, wantErr) {
+		t.Errorf("FindReader error = %v; want %v", err, wantErr)
+	}
+	if pos != -1 {
+		t.Errorf("FindReader = %d; want -1", pos)
+	}
+}
+
+// zeroReader returns zero bytes and a nil error before every read of r.
+type zeroReader struct {
+	r    io.Reader
+	skip bool
+}
+
+func (z *zeroReader) Read(p []byte) (int, error) {
+	z.skip = !z.skip
+	if z.skip {
+		return 0, nil
+	}
+	return z.r.Read(p)
+}
+
+func TestReaderSearchGzip(t *testing.T) {
+	// Matches land on both sides of the carry-over between reads
+	var text strings.Builder
+	want := []int{}
+	for i := 0; i < 5; i++ {
+		text.WriteString(strings.Repeat("x", readChunkSize-3))
+		want = append(want, text.Len())
+		text.WriteString("needle")
+	}
+
+	var compressed bytes.Buffer
+	zw := gzip.NewWriter(&compressed)
+	if _, err := zw.Write([]byte(text.String())); err != nil {
+		t.Fatalf("gzip write: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("gzip close: %v", err)
+	}
+
+	readers := map[string]func(io.Reader) io.Reader{
+		"gzip":          func(r io.Reader) io.Reader { return r },
+		"gzip one byte": iotest.OneByteReader,
+		"gzip zero":     func(r io.Reader) io.Reader { return &zeroReader{r: r} },
+	}
+	bm := New("needle", false)
+	for name, wrap := range readers {
+		t.Run(name, func(t *testing.T) {
+			zr, err := gzip.NewReader(bytes.NewReader(compressed.Bytes()))
+			if err != nil {
+				t.Fatalf("gzip.NewReader: %v", err)
+			}
+			got := []int{}
+			err = bm.FindAllReader(wrap(zr), func(offset int) bool {
+				got = append(got, offset)
+				return true
+			})
+			if err != nil {
+				t.Fatalf("FindAllReader returned error: %v", err)
+			}
+			if !equalIntSlices(got, want) {
+				t.Errorf("FindAllReader = %v; want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package boyermoore
+
+// ReplaceAll returns a copy of the text with every non-overlapping match of the pattern replaced by repl.
+// The text around the matches is kept as is, even in case-insensitive mode, and repl is inserted verbatim.
+// An empty pattern returns the text unchanged.
+func (bm *BoyerMoore) ReplaceAll(txt, repl string) string {
+	return string(bm.replace([]byte(txt), []byte(repl), 0))
+}
+
+// ReplaceAllBytes returns a copy of the byte slice with every non-overlapping match of the pattern replaced by repl.
+// An empty pattern returns an unchanged copy of the data.
+func (bm *BoyerMoore) ReplaceAllBytes(data, repl []byte) []byte {
+	return bm.replace(data, repl, 0)
+}
+
+// ReplaceFirst returns a copy of the text with the first match of the pattern replaced by repl.
+// An empty pattern returns the text unchanged.
+func (bm *BoyerMoore) ReplaceFirst(txt, repl string) string {
+	return string(bm.replace([]byte(txt), []byte(repl), 1))
+}
+
+// ReplaceFirstBytes returns a copy of the byte slice with the first match of the pattern replaced by repl.
+// An empty pattern returns an unchanged copy of the data.
+func (bm *BoyerMoore) ReplaceFirstBytes(data, repl []byte) []byte {
+	return bm.replace(data, repl, 1)
+}
+
+// Highlight returns a copy of the text with open inserted before and close after every
+// non-overlapping match of the pattern, e.g. Highlight(txt, "<b>", "</b>").
+// The matched text keeps its original casing. Adjacent matches get a pair of markers each.
+// An empty pattern returns the text unchanged.
+func (bm *BoyerMoore) Highlight(txt, open, close string) string {
+	return string(bm.HighlightBytes([]byte(txt), []byte(open), []byte(close)))
+}
+
+// HighlightBytes returns a copy of the byte slice with open inserted before and close after
+// every non-overlapping match of the pattern.
+// An empty pattern returns an unchanged copy of the data.
+func (bm *BoyerMoore) HighlightBytes(data, open, close []byte) []byte {
+	return bm.rewrite(data, 0, len(open)+len(close), func(out, match []byte) []byte {
+		out = append(out, open...)
+		out = append(out, match...)
+		return append(out, close...)
+	})
+}
+
+// replace substitutes repl for up to limit non-overlapping matches (all if limit is 0)
+// and returns the result as a new byte slice.
+func (bm *BoyerMoore) replace(data, repl []byte, limit int) []byte {
+	return bm.rewrite(data, limit, len(repl)-len(bm.pat), func(out, _ []byte) []byte {
+		return append(out, repl...)
+	})
+}
+
+// rewrite copies data, passing up to limit non-overlapping matches (all if limit is 0) to
+// emit, which appends their replacement to out. grow is the expected change in length per match.
+func (bm *BoyerMoore) rewrite(data []byte, limit, grow int, emit func(out, match []byte) []byte) []byte {
+	m := len(bm.pat)
+	h := bm.prepare(data)
+	starts := bm.searchAll(h.data, 0, false, limit)
+
+	out := make([]byte, 0, len(data)+len(starts)*max(grow, 0))
+	last := 0
+	for _, s := range starts {
+		start, end := h.orig(s), h.orig(s+m)
+		out = append(out, data[last:start]...)
+		out = emit(out, data[start:end])
+		last = end
+	}
+	return append(out, data[last:]...)
+}
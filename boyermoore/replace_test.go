@@ -0,0 +1,143 @@
+package boyermoore
+
+import (
+	"testing"
+)
+
+func TestReplace(t *testing.T) {
+	tests := []struct {
+		name      string
+		pattern   string
+		text      string
+		repl      string
+		opts      Options
+		wantAll   string
+		wantFirst string
+	}{
+		{
+			name:      "Basic replace",
+			pattern:   "cat",
+			text:      "a cat and a cat",
+			repl:      "dog",
+			wantAll:   "a dog and a dog",
+			wantFirst: "a dog and a cat",
+		},
+		{
+			name:      "Shorter replacement",
+			pattern:   "abc",
+			text:      "abcXabc",
+			repl:      "-",
+			wantAll:   "-X-",
+			wantFirst: "-Xabc",
+		},
+		{
+			name:      "Empty replacement",
+			pattern:   "ab",
+			text:      "ZabZabZ",
+			repl:      "",
+			wantAll:   "ZZZ",
+			wantFirst: "ZZabZ",
+		},
+		{
+			name:      "Non-overlapping matches",
+			pattern:   "aa",
+			text:      "aaaaa",
+			repl:      "b",
+			wantAll:   "bba",
+			wantFirst: "baaa",
+		},
+		{
+			name:      "Ignore case keeps surrounding text",
+			pattern:   "CAT",
+			text:      "The Cat saw a cAT",
+			repl:      "Dog",
+			opts:      Options{IgnoreCase: true},
+			wantAll:   "The Dog saw a Dog",
+			wantFirst: "The Dog saw a cAT",
+		},
+		{
+			name:      "Unicode fold with differing lengths",
+			pattern:   "ⱥb",
+			text:      "xȺBxⱥb",
+			repl:      "!",
+			opts:      Options{IgnoreCase: true, UnicodeFold: true},
+			wantAll:   "x!x!",
+			wantFirst: "x!xⱥb",
+		},
+		{
+			name:      "No match",
+			pattern:   "cat",
+			text:      "dog",
+			repl:      "x",
+			wantAll:   "dog",
+			wantFirst: "dog",
+		},
+		{
+			name:      "Empty pattern",
+			pattern:   "",
+			text:      "abc",
+			repl:      "x",
+			wantAll:   "abc",
+			wantFirst: "abc",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bm := NewWithOptions(tc.pattern, tc.opts)
+
+			gotAll := bm.ReplaceAll(tc.text, tc.repl)
+			if gotAll != tc.wantAll {
+				t.Errorf("ReplaceAll(%q, %q) = %q; want %q", tc.text, tc.repl, gotAll, tc.wantAll)
+			}
+
+			gotFirst := bm.ReplaceFirst(tc.text, tc.repl)
+			if gotFirst != tc.wantFirst {
+				t.Errorf("ReplaceFirst(%q, %q) = %q; want %q", tc.text, tc.repl, gotFirst, tc.wantFirst)
+			}
+
+			gotAllBytes := bm.ReplaceAllBytes([]byte(tc.text), []byte(tc.repl))
+			if string(gotAllBytes) != tc.wantAll {
+				t.Errorf("ReplaceAllBytes(%q, %q) = %q; want %q", tc.text, tc.repl, gotAllBytes, tc.wantAll)
+			}
+
+			gotFirstBytes := bm.ReplaceFirstBytes([]byte(tc.text), []byte(tc.repl))
+			if string(gotFirstBytes) != tc.wantFirst {
+				t.Errorf("ReplaceFirstBytes(%q, %q) = %q; want %q", tc.text, tc.repl, gotFirstBytes, tc.wantFirst)
+			}
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		text    string
+		opts    Options
+		want    string
+	}{
+		{"Basic", "cat", "a cat here", Options{}, "a <b>cat</b> here"},
+		{"Adjacent matches", "ab", "abab", Options{}, "<b>ab</b><b>ab</b>"},
+		{"Matches at both ends", "x", "xyx", Options{}, "<b>x</b>y<b>x</b>"},
+		{"Whole text", "abc", "abc", Options{}, "<b>abc</b>"},
+		{"Non-overlapping matches", "aa", "aaa", Options{}, "<b>aa</b>a"},
+		{"Keeps original casing", "go", "Go GO", Options{IgnoreCase: true}, "<b>Go</b> <b>GO</b>"},
+		{"Unicode fold", "café", "CAFÉ!", Options{IgnoreCase: true, UnicodeFold: true}, "<b>CAFÉ</b>!"},
+		{"No match", "zz", "abc", Options{}, "abc"},
+		{"Empty pattern", "", "abc", Options{}, "abc"},
+		{"Empty text", "a", "", Options{}, ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bm := NewWithOptions(tc.pattern, tc.opts)
+			if got := bm.Highlight(tc.text, "<b>", "</b>"); got != tc.want {
+				t.Errorf("Highlight(%q) = %q; want %q", tc.text, got, tc.want)
+			}
+			if got := bm.HighlightBytes([]byte(tc.text), []byte("<b>"), []byte("</b>")); string(got) != tc.want {
+				t.Errorf("HighlightBytes(%q) = %q; want %q", tc.text, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package boyermoore
+
+import "unicode/utf8"
+
+// RuneMatch locates a match of the pattern both in bytes and in runes, the latter being
+// what e.g. a text editor needs to place a cursor on a character.
+type RuneMatch struct {
+	Start     int // byte offset of the first byte of the match
+	End       int // byte offset of the last byte of the match (inclusive)
+	RuneStart int // index of the match's first rune, i.e. the number of runes before Start
+	RuneEnd   int // index of the match's last rune (inclusive)
+}
+
+// FindAllRunes returns every non-overlapping match of the pattern in the text with both its
+// byte span and its rune indices. Runes are counted as utf8.RuneCount does, so each byte of
+// invalid UTF-8 counts as one rune, as does each byte of a multi-byte character that a match
+// starts or ends inside of. Returns nil if no matches are found.
+func (bm *BoyerMoore) FindAllRunes(txt string) []RuneMatch {
+	return bm._findAllRunes([]byte(txt))
+}
+
+// FindAllRunesBytes returns every non-overlapping match of the pattern in the byte slice with
+// both its byte span and its rune indices. Returns nil if no matches are found.
+func (bm *BoyerMoore) FindAllRunesBytes(data []byte) []RuneMatch {
+	return bm._findAllRunes(data)
+}
+
+// _findAllRunes counts runes incrementally: only the bytes between the end of the previous
+// match and the end of the current one are counted, so the whole text is counted once
+// however many matches there are.
+func (bm *BoyerMoore) _findAllRunes(data []byte) []RuneMatch {
+	matches := bm._findAllMatches(data)
+	if len(matches) == 0 {
+		return nil
+	}
+	results := make([]RuneMatch, len(matches))
+	counted, runes := 0, 0 // runes is the number of runes in data[:counted]
+	for i, m := range matches {
+		runes += utf8.RuneCount(data[counted:m.Start])
+		start := runes
+		runes += utf8.RuneCount(data[m.Start : m.End+1])
+		counted = m.End + 1
+		results[i] = RuneMatch{Start: m.Start, End: m.End, RuneStart: start, RuneEnd: runes - 1}
+	}
+	return results
+}
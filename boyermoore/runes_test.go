@@ -0,0 +1,101 @@
+package boyermoore
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestFindAllRunes(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		text    string
+		opts    Options
+		want    []RuneMatch
+	}{
+		{
+			name:    "ASCII text",
+			pattern: "ab",
+			text:    "xabxab",
+			want:    []RuneMatch{{1, 2, 1, 2}, {4, 5, 4, 5}},
+		},
+		{
+			name:    "Multi-byte text before and inside matches",
+			pattern: "wörld",
+			text:    "héllo wörld, wörld",
+			want:    []RuneMatch{{7, 12, 6, 10}, {15, 20, 13, 17}},
+		},
+		{
+			name:    "Pattern of one multi-byte rune",
+			pattern: "日",
+			text:    "日本日",
+			want:    []RuneMatch{{0, 2, 0, 0}, {6, 8, 2, 2}},
+		},
+		{
+			name:    "Unicode folding changes the byte length",
+			pattern: "key",
+			text:    "a \u212aey", // KELVIN SIGN (3 bytes) folds to 'k'
+			opts:    Options{IgnoreCase: true, UnicodeFold: true},
+			want:    []RuneMatch{{2, 6, 2, 4}},
+		},
+		{
+			name:    "Invalid UTF-8 counts one rune per byte",
+			pattern: "b",
+			text:    "a\xff\xfeb",
+			want:    []RuneMatch{{3, 3, 3, 3}},
+		},
+		{
+			name:    "No match",
+			pattern: "z",
+			text:    "héllo",
+			want:    nil,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bm := NewWithOptions(tc.pattern, tc.opts)
+			if got := bm.FindAllRunes(tc.text); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("FindAllRunes(%q) = %v; want %v", tc.text, got, tc.want)
+			}
+			if got := bm.FindAllRunesBytes([]byte(tc.text)); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("FindAllRunesBytes(%q) = %v; want %v", tc.text, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestFindAllRunesRandom checks the incremental count against counting from the start of the text
+func TestFindAllRunesRandom(t *testing.T) {
+	rng := rand.New(rand.NewPCG(9, 10))
+	alphabet := []string{"a", "b", "é", "日", "\xff"}
+	for iter := 0; iter < 500; iter++ {
+		var sb strings.Builder
+		for i := rng.IntN(40); i > 0; i-- {
+			sb.WriteString(alphabet[rng.IntN(len(alphabet))])
+		}
+		text := sb.String()
+		pattern := alphabet[rng.IntN(len(alphabet))] + alphabet[rng.IntN(len(alphabet))]
+
+		bm := New(pattern, false)
+		got := bm.FindAllRunes(text)
+		starts := bm.FindAll(text)
+		if len(got) != len(starts) {
+			t.Fatalf("FindAllRunes(%q) with pattern %q found %d matches; FindAll %d", text, pattern, len(got), len(starts))
+		}
+		for i, s := range starts {
+			want := RuneMatch{
+				Start:     s,
+				End:       s + len(pattern) - 1,
+				RuneStart: utf8.RuneCountInString(text[:s]),
+				RuneEnd:   utf8.RuneCountInString(text[:s+len(pattern)]) - 1,
+			}
+			if got[i] != want {
+				t.Fatalf("FindAllRunes(%q) with pattern %q: match %d = %v; want %v", text, pattern, i, got[i], want)
+			}
+		}
+	}
+}
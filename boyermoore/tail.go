@@ -0,0 +1,55 @@
+package boyermoore
+
+// Tail searches data that arrives in pieces, such as a log file being followed, without
+// rescanning what it has already seen. Each call to Append searches only the new bytes plus
+// the last few bytes before them that may still begin a match, and reports the absolute
+// offsets of the matches found there. Across all calls the offsets are the same as FindAll
+// would return for the concatenated data.
+// A Tail is not safe for concurrent use, but any number of tails may share a matcher.
+type Tail struct {
+	bm   *BoyerMoore
+	buf  []byte // bytes kept from earlier appends that may still begin a match
+	base int    // absolute offset of buf[0]
+	from int    // position in buf where the next match may start
+}
+
+// NewTail returns a Tail that has not seen any data yet.
+func (bm *BoyerMoore) NewTail() *Tail {
+	return &Tail{bm: bm}
+}
+
+// Append adds more to the end of the data and returns the absolute offsets of the
+// non-overlapping matches it completes, in order. A match reported by an earlier call
+// is never reported again, and no match may start inside it.
+// The bytes of more are copied as needed, so the caller may reuse the slice afterwards.
+func (t *Tail) Append(more []byte) []int {
+	m := len(t.bm.pat)
+	if m == 0 {
+		t.base += len(more)
+		return nil
+	}
+
+	t.buf = append(t.buf, more...)
+	var found []int
+	h := t.bm.prepare(t.buf)
+	for _, s := range t.bm.searchAll(h.data, h.index(t.from), false, 0) {
+		found = append(found, t.base+h.orig(s))
+		t.from = h.orig(s + m)
+	}
+
+	// Keep the bytes that may still begin a match,
+	// never re-examining bytes covered by a reported match
+	keep := max(len(t.buf)-(t.bm.matchSpan()-1), t.from)
+	if keep > 0 {
+		t.base += keep
+		t.buf = t.buf[:copy(t.buf, t.buf[keep:])]
+	}
+	t.from = 0
+	return found
+}
+
+// Offset returns the total number of bytes appended so far,
+// i.e. the absolute offset the next appended byte will have.
+func (t *Tail) Offset() int {
+	return t.base + len(t.buf)
+}
@@ -0,0 +1,68 @@
+package boyermoore
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTail(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		text    string
+		opts    Options
+		want    []int
+	}{
+		{"Basic match", "ABC", "ZZZABCZZZABC", Options{}, []int{3, 9}},
+		{"No match", "ABC", "ZZZABZ", Options{}, nil},
+		{"Non-overlapping", "aa", "aaaaa", Options{}, []int{0, 2}},
+		{"Single byte", "a", "abca", Options{}, []int{0, 3}},
+		{"Ignore case", "AbC", "zzabcZZABC", Options{IgnoreCase: true}, []int{2, 7}},
+		{"Unicode fold", "ⱥb", "xȺBxⱥb", Options{IgnoreCase: true, UnicodeFold: true}, []int{1, 5}},
+		{"Empty pattern", "", "ABC", Options{}, nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bm := NewWithOptions(tc.pattern, tc.opts)
+			// Feed the text in pieces of every size, down to one byte at a time,
+			// which also splits multi-byte runes across appends
+			for size := 1; size <= len(tc.text); size++ {
+				tail := bm.NewTail()
+				var got []int
+				for i := 0; i < len(tc.text); i += size {
+					got = append(got, tail.Append([]byte(tc.text[i:min(i+size, len(tc.text))]))...)
+				}
+				if !slices.Equal(got, tc.want) {
+					t.Errorf("pieces of %d: Append found %v; want %v", size, got, tc.want)
+				}
+				if off := tail.Offset(); off != len(tc.text) {
+					t.Errorf("pieces of %d: Offset() = %d; want %d", size, off, len(tc.text))
+				}
+			}
+		})
+	}
+}
+
+func TestTailEmptyAppend(t *testing.T) {
+	tail := New("needle", false).NewTail()
+	for _, piece := range []string{"xxnee", "", "dle", "", "needle"} {
+		tail.Append([]byte(piece))
+	}
+	if got := tail.Append(nil); got != nil {
+		t.Errorf("Append(nil) = %v; want nil", got)
+	}
+	if got := tail.Append([]byte("xneedle")); !slices.Equal(got, []int{15}) {
+		t.Errorf("Append = %v; want [15]", got)
+	}
+}
+
+func TestTailReusedSlice(t *testing.T) {
+	tail := New("abc", false).NewTail()
+	piece := []byte("xa")
+	tail.Append(piece)
+	copy(piece, "bc") // the tail must have copied the previous contents
+	if got := tail.Append(piece); !slices.Equal(got, []int{1}) {
+		t.Errorf("Append = %v; want [1]", got)
+	}
+}
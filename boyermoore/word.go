@@ -0,0 +1,52 @@
+package boyermoore
+
+// IsWordByte reports whether c is a word character, i.e. one of [A-Za-z0-9_].
+// It is the default predicate used by FindAllWholeWord.
+func IsWordByte(c byte) bool {
+	return c == '_' ||
+		(c >= 'a' && c <= 'z') ||
+		(c >= 'A' && c <= 'Z') ||
+		(c >= '0' && c <= '9')
+}
+
+// FindAllWholeWord returns the starting indices of all non-overlapping matches of the pattern in the text
+// that are not preceded or followed by a word character (as defined by IsWordByte).
+// Returns an empty slice if no matches are found.
+func (bm *BoyerMoore) FindAllWholeWord(txt string) []int {
+	return bm._findAllWord([]byte(txt), IsWordByte)
+}
+
+// FindAllWholeWordBytes returns the starting indices of all non-overlapping whole-word matches of the pattern in the byte slice.
+// Returns an empty slice if no matches are found.
+func (bm *BoyerMoore) FindAllWholeWordBytes(data []byte) []int {
+	return bm._findAllWord(data, IsWordByte)
+}
+
+// FindAllWordFunc is like FindAllWholeWord but uses isWord to decide which bytes are word characters.
+func (bm *BoyerMoore) FindAllWordFunc(txt string, isWord func(byte) bool) []int {
+	return bm._findAllWord([]byte(txt), isWord)
+}
+
+// FindAllWordFuncBytes is like FindAllWholeWordBytes but uses isWord to decide which bytes are word characters.
+func (bm *BoyerMoore) FindAllWordFuncBytes(data []byte, isWord func(byte) bool) []int {
+	return bm._findAllWord(data, isWord)
+}
+
+// _findAllWord returns the non-overlapping matches whose neighbouring bytes are not word characters.
+// A candidate rejected at s does not hide a whole-word match starting inside it, so the search
+// then resumes at s+1 rather than after the candidate.
+func (bm *BoyerMoore) _findAllWord(data []byte, isWord func(byte) bool) []int {
+	var results []int
+	m := len(bm.pat)
+	h := bm.prepare(data)
+	for s := bm.searchFirst(h.data, 0); s >= 0; {
+		start, end := h.orig(s), h.orig(s+m)
+		if (start == 0 || !isWord(data[start-1])) && (end == len(data) || !isWord(data[end])) {
+			results = append(results, start)
+			s = bm.searchFirst(h.data, s+m)
+		} else {
+			s = bm.searchFirst(h.data, s+1)
+		}
+	}
+	return results
+}
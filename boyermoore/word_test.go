@@ -0,0 +1,60 @@
+package boyermoore
+
+import (
+	"testing"
+)
+
+func TestFindAllWholeWord(t *testing.T) {
+	tests := []struct {
+		name       string
+		pattern    string
+		text       string
+		ignoreCase bool
+		want       []int
+	}{
+		{"Skips matches inside words", "cat", "cat category concat cat", false, []int{0, 20}},
+		{"Punctuation is a boundary", "cat", "(cat), cat.", false, []int{1, 7}},
+		{"Digits and underscore are word characters", "cat", "cat1 _cat cat", false, []int{10}},
+		{"Whole text", "cat", "cat", false, []int{0}},
+		{"Ignore case", "Cat", "CAT concat cAt", true, []int{0, 11}},
+		{"Rejected candidate does not hide a later one", "aa", "aaa aa", false, []int{4}},
+		{"Non-ASCII bytes are boundaries", "cat", "écat", false, []int{2}},
+		{"No match", "cat", "category", false, []int{}},
+		{"Empty pattern", "", "a b", false, []int{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bm := New(tc.pattern, tc.ignoreCase)
+
+			got := bm.FindAllWholeWord(tc.text)
+			if !equalIntSlices(got, tc.want) {
+				t.Errorf("FindAllWholeWord(%q) = %v; want %v", tc.text, got, tc.want)
+			}
+
+			gotBytes := bm.FindAllWholeWordBytes([]byte(tc.text))
+			if !equalIntSlices(gotBytes, tc.want) {
+				t.Errorf("FindAllWholeWordBytes(%q) = %v; want %v", tc.text, gotBytes, tc.want)
+			}
+		})
+	}
+}
+
+func TestFindAllWordFunc(t *testing.T) {
+	// Treat '-' as part of words as well
+	isWord := func(c byte) bool { return c == '-' || IsWordByte(c) }
+	bm := New("cat", false)
+
+	text := "cat-like cat"
+	want := []int{9}
+
+	got := bm.FindAllWordFunc(text, isWord)
+	if !equalIntSlices(got, want) {
+		t.Errorf("FindAllWordFunc(%q) = %v; want %v", text, got, want)
+	}
+
+	gotBytes := bm.FindAllWordFuncBytes([]byte(text), isWord)
+	if !equalIntSlices(gotBytes, want) {
+		t.Errorf("FindAllWordFuncBytes(%q) = %v; want %v", text, gotBytes, want)
+	}
+}
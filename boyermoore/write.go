@@ -0,0 +1,56 @@
+package boyermoore
+
+import (
+	"io"
+	"strconv"
+)
+
+// writeBufSize is the number of bytes WriteMatches collects before each write.
+const writeBufSize = 4 * 1024
+
+// WriteMatches writes the starting index of every non-overlapping match of the pattern in
+// the text to w as decimal text, with sep between consecutive indices and nothing after the last.
+// Output is collected in a small buffer, so w sees a few large writes rather than one per match.
+// Returns the number of indices written, and the first error returned by w, after which the search stops.
+func (bm *BoyerMoore) WriteMatches(txt string, w io.Writer, sep string) (int, error) {
+	return bm._writeMatches([]byte(txt), w, sep)
+}
+
+// WriteMatchesBytes writes the starting index of every non-overlapping match of the pattern
+// in the byte slice to w as decimal text separated by sep.
+// Returns the number of indices written and the first error returned by w.
+func (bm *BoyerMoore) WriteMatchesBytes(data []byte, w io.Writer, sep string) (int, error) {
+	return bm._writeMatches(data, w, sep)
+}
+
+func (bm *BoyerMoore) _writeMatches(data []byte, w io.Writer, sep string) (int, error) {
+	var (
+		buf      = make([]byte, 0, writeBufSize)
+		written  int // indices already accepted by w
+		buffered int // indices in buf
+		err      error
+	)
+	flush := func() {
+		if _, err = w.Write(buf); err == nil {
+			written += buffered
+		}
+		buf, buffered = buf[:0], 0
+	}
+
+	h := bm.prepare(data)
+	bm.searchEach(h.data, 0, false, func(s int) bool {
+		if written+buffered > 0 {
+			buf = append(buf, sep...)
+		}
+		buf = strconv.AppendInt(buf, int64(h.orig(s)), 10)
+		buffered++
+		if len(buf) >= writeBufSize {
+			flush()
+		}
+		return err == nil
+	})
+	if err == nil && len(buf) > 0 {
+		flush()
+	}
+	return written, err
+}
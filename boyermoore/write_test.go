@@ -0,0 +1,107 @@
+package boyermoore
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestWriteMatches(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		text    string
+		opts    Options
+		sep     string
+		want    string
+	}{
+		{"Comma separated", "AB", "ABZABZAB", Options{}, ",", "0,3,6"},
+		{"Newline separated", "aa", "aaaaa", Options{}, "\n", "0\n2"},
+		{"Empty separator", "a", "abca", Options{}, "", "03"},
+		{"Single match", "ABC", "ZZZABC", Options{}, ",", "3"},
+		{"Ignore case", "AbC", "zzabcZZABC", Options{IgnoreCase: true}, " ", "2 7"},
+		{"Unicode fold", "ⱥb", "xȺBxⱥb", Options{IgnoreCase: true, UnicodeFold: true}, ",", "1,5"},
+		{"No match", "ABC", "ZZZABZ", Options{}, ",", ""},
+		{"Empty pattern", "", "ABC", Options{}, ",", ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bm := NewWithOptions(tc.pattern, tc.opts)
+			wantCount := len(bm.FindAll(tc.text))
+
+			var sb strings.Builder
+			n, err := bm.WriteMatches(tc.text, &sb, tc.sep)
+			if err != nil || n != wantCount || sb.String() != tc.want {
+				t.Errorf("WriteMatches(%q, %q) wrote %q, returned (%d, %v); want %q, (%d, nil)",
+					tc.text, tc.sep, sb.String(), n, err, tc.want, wantCount)
+			}
+
+			sb.Reset()
+			n, err = bm.WriteMatchesBytes([]byte(tc.text), &sb, tc.sep)
+			if err != nil || n != wantCount || sb.String() != tc.want {
+				t.Errorf("WriteMatchesBytes(%q, %q) wrote %q, returned (%d, %v); want %q, (%d, nil)",
+					tc.text, tc.sep, sb.String(), n, err, tc.want, wantCount)
+			}
+		})
+	}
+}
+
+func TestWriteMatchesLarge(t *testing.T) {
+	// Enough matches to need several writes
+	text := strings.Repeat("ab", 10000)
+	var want strings.Builder
+	for i := 0; i < len(text); i += 2 {
+		if i > 0 {
+			want.WriteString(", ")
+		}
+		want.WriteString(strconv.Itoa(i))
+	}
+
+	w := &countingWriter{}
+	n, err := New("ab", false).WriteMatches(text, w, ", ")
+	if err != nil || n != 10000 {
+		t.Fatalf("WriteMatches returned (%d, %v); want (10000, nil)", n, err)
+	}
+	if w.sb.String() != want.String() {
+		t.Errorf("WriteMatches wrote a different result than expected")
+	}
+	if w.writes < 2 || w.writes > 1+w.sb.Len()/writeBufSize {
+		t.Errorf("WriteMatches made %d writes for %d bytes", w.writes, w.sb.Len())
+	}
+}
+
+func TestWriteMatchesError(t *testing.T) {
+	errFull := errors.New("disk full")
+	text := strings.Repeat("ab", 10000)
+
+	w := &countingWriter{failAfter: 1, err: errFull}
+	n, err := New("ab", false).WriteMatches(text, w, ",")
+	if !errors.Is(err, errFull) {
+		t.Fatalf("WriteMatches error = %v; want %v", err, errFull)
+	}
+	// Only the indices in the first, successful write are counted
+	if got := strings.Count(w.sb.String(), ",") + 1; n != got {
+		t.Errorf("WriteMatches returned %d; want the %d indices actually written", n, got)
+	}
+	if w.writes != 2 {
+		t.Errorf("WriteMatches made %d writes; want it to stop after the failing one", w.writes)
+	}
+}
+
+// countingWriter records what is written to it and fails every write after the first failAfter if err is set
+type countingWriter struct {
+	sb        strings.Builder
+	writes    int
+	failAfter int
+	err       error
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	if w.err != nil && w.writes > w.failAfter {
+		return 0, w.err
+	}
+	return w.sb.Write(p)
+}
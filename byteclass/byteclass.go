@@ -0,0 +1,117 @@
+package byteclass
+
+// ByteClass represents a pattern matcher whose pattern is a sequence of byte sets:
+// the text matches at s when data[s+i] is in pattern[i] for every i.
+// The search is Boyer-Moore-Horspool with a bad character table built over the
+// sets, so a text byte that belongs to no set lets the window jump the full length
+// of the pattern. Large sets late in the pattern shorten every shift.
+type ByteClass struct {
+	pat   []Set    // one set of acceptable bytes per pattern position
+	shift [256]int // Horspool shift for the text byte under the last pattern position
+}
+
+// New creates a new ByteClass matcher for the given pattern of byte sets.
+// Case-insensitive matching is expressed in the sets themselves, e.g. SetOf("aA").
+// An empty pattern never matches, and neither does one containing an empty set.
+func New(pattern []Set) *ByteClass {
+	bc := &ByteClass{pat: append([]Set(nil), pattern...)}
+	bc.buildShift()
+	return bc
+}
+
+// FindAll returns the starting indices of all non-overlapping matches of the pattern in the text.
+// Returns an empty slice if no matches are found.
+func (bc *ByteClass) FindAll(txt string) []int {
+	return bc._findAll([]byte(txt), 0)
+}
+
+// FindAllBytes returns the starting indices of all non-overlapping matches of the pattern in the byte slice.
+// Returns an empty slice if no matches are found.
+func (bc *ByteClass) FindAllBytes(data []byte) []int {
+	return bc._findAll(data, 0)
+}
+
+// FindFirst returns the index of the first occurrence of the pattern in the text.
+// Returns -1 if the pattern is not found.
+func (bc *ByteClass) FindFirst(txt string) int {
+	if ms := bc._findAll([]byte(txt), 1); len(ms) > 0 {
+		return ms[0]
+	}
+	return -1
+}
+
+// FindFirstBytes returns the index of the first occurrence of the pattern in the byte slice.
+// Returns -1 if the pattern is not found.
+func (bc *ByteClass) FindFirstBytes(data []byte) int {
+	if ms := bc._findAll(data, 1); len(ms) > 0 {
+		return ms[0]
+	}
+	return -1
+}
+
+// Contains reports whether the pattern appears in the text.
+func (bc *ByteClass) Contains(txt string) bool {
+	return len(bc._findAll([]byte(txt), 1)) > 0
+}
+
+// ContainsBytes reports whether the pattern appears in the byte slice.
+func (bc *ByteClass) ContainsBytes(data []byte) bool {
+	return len(bc._findAll(data, 1)) > 0
+}
+
+// Count returns the number of non-overlapping occurrences of the pattern in the text.
+func (bc *ByteClass) Count(txt string) int {
+	return len(bc._findAll([]byte(txt), 0))
+}
+
+// CountBytes returns the number of non-overlapping occurrences of the pattern in the byte slice.
+func (bc *ByteClass) CountBytes(data []byte) int {
+	return len(bc._findAll(data, 0))
+}
+
+// _findAll returns the indices of the non-overlapping matches in data.
+// If limit is positive, the search stops once limit matches have been found.
+func (bc *ByteClass) _findAll(data []byte, limit int) []int {
+	results := []int{}
+	m := len(bc.pat)
+	if m == 0 {
+		return results
+	}
+
+	s := 0 // current text position
+	for s <= len(data)-m {
+		j := m - 1
+		// Check pattern match from right to left
+		for j >= 0 && bc.pat[j].Has(data[s+j]) {
+			j--
+		}
+		if j < 0 {
+			results = append(results, s)
+			if len(results) == limit {
+				break
+			}
+			// Skip past the match
+			s += m
+			continue
+		}
+		s += bc.shift[data[s+m-1]]
+	}
+	return results
+}
+
+// buildShift constructs the Horspool shift table over the sets.
+// The shift for byte c is the distance from the last position before the final one
+// whose set contains c to the end of the pattern, or the pattern length if there is none.
+func (bc *ByteClass) buildShift() {
+	m := len(bc.pat)
+	for c := range bc.shift {
+		bc.shift[c] = m
+	}
+	for i := 0; i < m-1; i++ {
+		for c := 0; c < 256; c++ {
+			if bc.pat[i].Has(byte(c)) {
+				bc.shift[c] = m - 1 - i
+			}
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package byteclass
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestFindAll(t *testing.T) {
+	vowel := SetOf("aeiouAEIOU")
+	digit := Range('0', '9')
+
+	tests := []struct {
+		name      string
+		pattern   []Set
+		text      string
+		wantAll   []int
+		wantFirst int
+	}{
+		{
+			name:      "Vowel class",
+			pattern:   []Set{SetOf("b"), vowel, SetOf("t")},
+			text:      "bat bet bit btt bOt",
+			wantAll:   []int{0, 4, 8, 16},
+			wantFirst: 0,
+		},
+		{
+			name:      "Digits",
+			pattern:   []Set{digit, digit, SetOf("-"), digit},
+			text:      "call 12-3 or 4-56 or 78-9",
+			wantAll:   []int{5, 21},
+			wantFirst: 5,
+		},
+		{
+			name:      "Case-insensitive by sets",
+			pattern:   []Set{SetOf("hH"), SetOf("iI")},
+			text:      "hi HI hI xy",
+			wantAll:   []int{0, 3, 6},
+			wantFirst: 0,
+		},
+		{
+			name:      "Non-overlapping",
+			pattern:   []Set{SetOf("a"), Any()},
+			text:      "aaaaa",
+			wantAll:   []int{0, 2},
+			wantFirst: 0,
+		},
+		{
+			name:      "Empty set never matches",
+			pattern:   []Set{SetOf("a"), {}},
+			text:      "aaaa",
+			wantAll:   []int{},
+			wantFirst: -1,
+		},
+		{
+			name:      "Empty pattern",
+			pattern:   nil,
+			text:      "abc",
+			wantAll:   []int{},
+			wantFirst: -1,
+		},
+		{
+			name:      "Pattern longer than text",
+			pattern:   []Set{Any(), Any(), Any()},
+			text:      "ab",
+			wantAll:   []int{},
+			wantFirst: -1,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bc := New(tc.pattern)
+			if got := bc.FindAll(tc.text); !slices.Equal(got, tc.wantAll) {
+				t.Errorf("FindAll(%q) = %v; want %v", tc.text, got, tc.wantAll)
+			}
+			if got := bc.FindAllBytes([]byte(tc.text)); !slices.Equal(got, tc.wantAll) {
+				t.Errorf("FindAllBytes(%q) = %v; want %v", tc.text, got, tc.wantAll)
+			}
+			if got := bc.FindFirst(tc.text); got != tc.wantFirst {
+				t.Errorf("FindFirst(%q) = %d; want %d", tc.text, got, tc.wantFirst)
+			}
+			if got := bc.FindFirstBytes([]byte(tc.text)); got != tc.wantFirst {
+				t.Errorf("FindFirstBytes(%q) = %d; want %d", tc.text, got, tc.wantFirst)
+			}
+			if got := bc.Contains(tc.text); got != (tc.wantFirst >= 0) {
+				t.Errorf("Contains(%q) = %v; want %v", tc.text, got, tc.wantFirst >= 0)
+			}
+			if got := bc.Count(tc.text); got != len(tc.wantAll) {
+				t.Errorf("Count(%q) = %d; want %d", tc.text, got, len(tc.wantAll))
+			}
+			if got := bc.ContainsBytes([]byte(tc.text)); got != (tc.wantFirst >= 0) {
+				t.Errorf("ContainsBytes(%q) = %v; want %v", tc.text, got, tc.wantFirst >= 0)
+			}
+			if got := bc.CountBytes([]byte(tc.text)); got != len(tc.wantAll) {
+				t.Errorf("CountBytes(%q) = %d; want %d", tc.text, got, len(tc.wantAll))
+			}
+		})
+	}
+}
+
+func TestFindAllRandom(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	classes := []Set{SetOf("a"), SetOf("b"), SetOf("ab"), SetOf("bc"), Any()}
+
+	for i := 0; i < 2000; i++ {
+		pattern := make([]Set, 1+rng.IntN(4))
+		for j := range pattern {
+			pattern[j] = classes[rng.IntN(len(classes))]
+		}
+		b := make([]byte, rng.IntN(30))
+		for j := range b {
+			b[j] = "abcd"[rng.IntN(4)]
+		}
+
+		// Brute force: leftmost match, then resume after it
+		want := []int{}
+		for s := 0; s+len(pattern) <= len(b); {
+			ok := true
+			for j, set := range pattern {
+				if !set.Has(b[s+j]) {
+					ok = false
+					break
+				}
+			}
+			if ok {
+				want = append(want, s)
+				s += len(pattern)
+			} else {
+				s++
+			}
+		}
+
+		if got := New(pattern).FindAllBytes(b); !slices.Equal(got, want) {
+			t.Fatalf("FindAllBytes(%q) = %v; want %v", b, got, want)
+		}
+	}
+}
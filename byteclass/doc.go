@@ -0,0 +1,3 @@
+// Package byteclass implements single-pattern search where every pattern
+// position is a set of acceptable bytes rather than a single byte.
+package byteclass
@@ -0,0 +1,49 @@
+package byteclass
+
+// Set is a set of bytes stored as a 256-bit mask.
+// The zero value is the empty set.
+type Set [4]uint64
+
+// SetOf returns the set of the bytes in chars.
+func SetOf(chars string) Set {
+	var s Set
+	for i := 0; i < len(chars); i++ {
+		s = s.Add(chars[i])
+	}
+	return s
+}
+
+// Range returns the set of the bytes from lo to hi inclusive.
+// It is empty if lo > hi.
+func Range(lo, hi byte) Set {
+	var s Set
+	for c := int(lo); c <= int(hi); c++ {
+		s = s.Add(byte(c))
+	}
+	return s
+}
+
+// Any returns the set of all 256 bytes.
+func Any() Set {
+	return Set{^uint64(0), ^uint64(0), ^uint64(0), ^uint64(0)}
+}
+
+// Add returns the set of the bytes in s and c. Like Union it leaves s unchanged,
+// so add to a variable with s = s.Add(c).
+func (s Set) Add(c byte) Set {
+	s[c>>6] |= 1 << (c & 63)
+	return s
+}
+
+// Has reports whether c is in the set.
+func (s Set) Has(c byte) bool {
+	return s[c>>6]&(1<<(c&63)) != 0
+}
+
+// Union returns the set of the bytes in s or t.
+func (s Set) Union(t Set) Set {
+	for i := range s {
+		s[i] |= t[i]
+	}
+	return s
+}
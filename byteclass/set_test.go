@@ -0,0 +1,44 @@
+package byteclass
+
+import "testing"
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		name string
+		set  Set
+		in   string
+		out  string
+	}{
+		{name: "SetOf", set: SetOf("aeiou"), in: "aeiou", out: "bxyAE"},
+		{name: "Range", set: Range('0', '9'), in: "0123456789", out: "/:a"},
+		{name: "Empty range", set: Range('9', '0'), out: "09a"},
+		{name: "Any", set: Any(), in: "\x00a\x7f\x80\xff"},
+		{name: "Union", set: SetOf("ab").Union(Range('x', 'z')), in: "abxyz", out: "cw"},
+		{name: "Add", set: SetOf("a").Add('z').Add('\xff'), in: "az\xff", out: "by"},
+		{name: "High bytes", set: SetOf("\x80\xff"), in: "\x80\xff", out: "\x7f\xfe"},
+		{name: "Zero value", set: Set{}, out: "\x00a\xff"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for i := 0; i < len(tc.in); i++ {
+				if !tc.set.Has(tc.in[i]) {
+					t.Errorf("Has(%q) = false; want true", tc.in[i])
+				}
+			}
+			for i := 0; i < len(tc.out); i++ {
+				if tc.set.Has(tc.out[i]) {
+					t.Errorf("Has(%q) = true; want false", tc.out[i])
+				}
+			}
+		})
+	}
+
+	// Add and Union return a new set, leaving the receiver unchanged
+	s := SetOf("a")
+	_ = s.Add('b')
+	_ = s.Union(SetOf("c"))
+	if s != SetOf("a") {
+		t.Errorf("Add and Union modified the receiver: %v", s)
+	}
+}
@@ -0,0 +1,207 @@
+package commentzwalter
+
+// Match represents pattern matching information found in text
+type Match struct {
+	PatternIndex int // which pattern in patterns
+	Start        int // start index of the match
+	End          int // end index of the match (inclusive)
+}
+
+// CommentzWalter is a multi-pattern matcher that, like Boyer-Moore, compares text
+// right to left and skips ahead on mismatches. The patterns are stored reversed in a
+// trie; the window's right end is moved through the text and, at each stop, the trie
+// is walked backwards from there, reporting every pattern that ends at that position.
+// How far the window then moves is limited by the shortest pattern, so it pays off
+// for sets of long patterns, where it can skip most of the text that Aho-Corasick
+// has to read byte by byte. With short patterns in the set, Aho-Corasick is faster.
+type CommentzWalter struct {
+	keywords   [][]byte   // patterns (converted to lowercase if ignoreCase is true)
+	ignoreCase bool       // case insensitivity flag
+	next       [][256]int // reversed-pattern trie; 0 means no edge since the root is never a child
+	out        [][]int    // patterns whose reversal ends at each node, in index order
+	minLen     int        // length of the shortest non-empty pattern, 0 if there is none
+
+	// endShift[c] is the Horspool shift for the byte c under the window's right end:
+	// the smallest distance from the end of a pattern to an earlier occurrence of c,
+	// capped at minLen.
+	endShift [256]int
+	// dist[c] is the smallest distance from the end of a pattern to any occurrence of c,
+	// capped at minLen. A pattern can only have c at a text position that lies dist[c]
+	// or more bytes before its end.
+	dist [256]int
+}
+
+// New creates a CommentzWalter matcher for the given patterns.
+// If ignoreCase is true, ASCII letters are compared case-insensitively.
+// Empty patterns never match.
+func New(patterns []string, ignoreCase bool) *CommentzWalter {
+	cw := &CommentzWalter{
+		ignoreCase: ignoreCase,
+		next:       make([][256]int, 1),
+		out:        make([][]int, 1),
+	}
+
+	for _, p := range patterns {
+		k := []byte(p)
+		for i := range k {
+			k[i] = cw.normChar(k[i])
+		}
+		cw.keywords = append(cw.keywords, k)
+		if len(k) > 0 && (cw.minLen == 0 || len(k) < cw.minLen) {
+			cw.minLen = len(k)
+		}
+	}
+
+	for idx, k := range cw.keywords {
+		if len(k) > 0 {
+			cw.insert(idx)
+		}
+	}
+	cw.buildShifts()
+	return cw
+}
+
+// FindAll finds every match of every pattern in text, including overlapping ones.
+// Matches are ordered by End, then from the longest to the shortest pattern, then by
+// PatternIndex, which is the order Aho-Corasick's Standard search reports them in.
+func (cw *CommentzWalter) FindAll(text string) []Match {
+	return cw._findAll([]byte(text), 0)
+}
+
+// FindAllBytes finds every match of every pattern in the byte slice, including overlapping ones
+func (cw *CommentzWalter) FindAllBytes(data []byte) []Match {
+	return cw._findAll(data, 0)
+}
+
+// Contains returns whether any pattern matches in the text
+func (cw *CommentzWalter) Contains(text string) bool {
+	return len(cw._findAll([]byte(text), 1)) > 0
+}
+
+// ContainsBytes returns whether any pattern matches in the byte slice
+func (cw *CommentzWalter) ContainsBytes(data []byte) bool {
+	return len(cw._findAll(data, 1)) > 0
+}
+
+// Count returns the number of all matches found in the text
+func (cw *CommentzWalter) Count(text string) int {
+	return len(cw._findAll([]byte(text), 0))
+}
+
+// CountBytes returns the number of all matches found in the byte slice
+func (cw *CommentzWalter) CountBytes(data []byte) int {
+	return len(cw._findAll(data, 0))
+}
+
+// _findAll moves the window's right end i through data. At each stop the reversed trie
+// is walked from data[i] backwards, collecting the patterns that end at i. The shift is
+// the larger of two safe lower bounds on the next position a pattern can end at:
+//   - the Horspool shift for data[i], and
+//   - the bad character shift for the byte c the walk stopped at, j bytes before i:
+//     a pattern ending at i+s either covers that position, which needs dist[c] <= s+j,
+//     or starts after it, which needs minLen <= s+j.
+//
+// If limit is positive, the search stops once limit matches have been found.
+func (cw *CommentzWalter) _findAll(data []byte, limit int) []Match {
+	var matches []Match
+	if cw.minLen == 0 {
+		return matches
+	}
+
+	for i := cw.minLen - 1; i < len(data); {
+		node, j := 0, 0
+		batch := len(matches)
+		for j <= i {
+			nx := cw.next[node][cw.normChar(data[i-j])]
+			if nx == 0 {
+				break
+			}
+			node = nx
+			j++
+			for _, patIdx := range cw.out[node] {
+				matches = append(matches, Match{
+					PatternIndex: patIdx,
+					Start:        i - j + 1,
+					End:          i,
+				})
+			}
+		}
+		// The walk finds shorter patterns first; report the longest first instead
+		reverseByLength(matches[batch:])
+		if limit > 0 && len(matches) >= limit {
+			return matches[:limit]
+		}
+
+		shift := cw.endShift[cw.normChar(data[i])]
+		if j <= i {
+			if bc := cw.dist[cw.normChar(data[i-j])] - j; bc > shift {
+				shift = bc
+			}
+		}
+		i += shift
+	}
+	return matches
+}
+
+// reverseByLength reverses the groups of equal-length matches in ms, which are sorted by
+// increasing length, while keeping the PatternIndex order within each group.
+func reverseByLength(ms []Match) {
+	for l, r := 0, len(ms)-1; l < r; l, r = l+1, r-1 {
+		ms[l], ms[r] = ms[r], ms[l]
+	}
+	for g := 0; g < len(ms); {
+		h := g
+		for h < len(ms) && ms[h].Start == ms[g].Start {
+			h++
+		}
+		for l, r := g, h-1; l < r; l, r = l+1, r-1 {
+			ms[l], ms[r] = ms[r], ms[l]
+		}
+		g = h
+	}
+}
+
+// insert adds the reversal of keyword idx to the trie, creating nodes as needed
+func (cw *CommentzWalter) insert(idx int) {
+	k := cw.keywords[idx]
+	node := 0
+	for i := len(k) - 1; i >= 0; i-- {
+		nx := cw.next[node][k[i]]
+		if nx == 0 {
+			cw.next = append(cw.next, [256]int{})
+			cw.out = append(cw.out, nil)
+			nx = len(cw.next) - 1
+			cw.next[node][k[i]] = nx
+		}
+		node = nx
+	}
+	cw.out[node] = append(cw.out[node], idx)
+}
+
+// buildShifts fills endShift and dist from the distances of every pattern byte
+// to the end of its pattern
+func (cw *CommentzWalter) buildShifts() {
+	for c := range cw.dist {
+		cw.dist[c] = cw.minLen
+		cw.endShift[c] = cw.minLen
+	}
+	for _, k := range cw.keywords {
+		for i, c := range k {
+			d := len(k) - 1 - i
+			if d < cw.dist[c] {
+				cw.dist[c] = d
+			}
+			if d > 0 && d < cw.endShift[c] {
+				cw.endShift[c] = d
+			}
+		}
+	}
+}
+
+// normChar normalizes a byte for case-insensitive comparison
+func (cw *CommentzWalter) normChar(c byte) byte {
+	if cw.ignoreCase && c >= 'A' && c <= 'Z' {
+		return c + ('a' - 'A')
+	}
+	return c
+}
@@ -0,0 +1,57 @@
+package commentzwalter
+
+import (
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"github.com/notJoon/searcher/ahocorasick"
+)
+
+// generateWords returns n random lowercase words whose lengths lie in [minLen, maxLen]
+func generateWords(rng *rand.Rand, n, minLen, maxLen int) []string {
+	words := make([]string, n)
+	for i := range words {
+		b := make([]byte, minLen+rng.IntN(maxLen-minLen+1))
+		for j := range b {
+			b[j] = byte('a' + rng.IntN(26))
+		}
+		words[i] = string(b)
+	}
+	return words
+}
+
+// BenchmarkFindAll compares Commentz-Walter with Aho-Corasick on sets of short and of
+// long patterns. Commentz-Walter's shifts grow with the shortest pattern and shrink as
+// the set covers more of the alphabet, so it wins on few, long patterns.
+func BenchmarkFindAll(b *testing.B) {
+	rng := rand.New(rand.NewPCG(42, 42))
+	text := strings.Join(generateWords(rng, 20000, 3, 12), " ")
+	sets := []struct {
+		name     string
+		patterns []string
+	}{
+		{"Short", generateWords(rng, 100, 3, 6)},
+		{"Long", generateWords(rng, 100, 16, 32)},
+		{"FewLong", generateWords(rng, 5, 16, 32)},
+	}
+
+	for _, set := range sets {
+		b.Run(set.name+"/CommentzWalter", func(b *testing.B) {
+			cw := New(set.patterns, false)
+			b.SetBytes(int64(len(text)))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				cw.FindAll(text)
+			}
+		})
+		b.Run(set.name+"/AhoCorasick", func(b *testing.B) {
+			ac := ahocorasick.New(set.patterns, false)
+			b.SetBytes(int64(len(text)))
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				ac.FindAll(text)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package commentzwalter
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"testing"
+
+	"github.com/notJoon/searcher/ahocorasick"
+)
+
+func TestCommentzWalterFindAll(t *testing.T) {
+	tests := []struct {
+		name       string
+		patterns   []string
+		text       string
+		ignoreCase bool
+		want       []Match
+	}{
+		{
+			name:     "classic he she his hers",
+			patterns: []string{"he", "she", "his", "hers"},
+			text:     "ushers",
+			want: []Match{
+				{PatternIndex: 1, Start: 1, End: 3},
+				{PatternIndex: 0, Start: 2, End: 3},
+				{PatternIndex: 3, Start: 2, End: 5},
+			},
+		},
+		{
+			name:     "overlapping matches of one pattern",
+			patterns: []string{"aa"},
+			text:     "aaaa",
+			want: []Match{
+				{PatternIndex: 0, Start: 0, End: 1},
+				{PatternIndex: 0, Start: 1, End: 2},
+				{PatternIndex: 0, Start: 2, End: 3},
+			},
+		},
+		{
+			name:     "duplicate patterns in index order",
+			patterns: []string{"ab", "b", "ab"},
+			text:     "ab",
+			want: []Match{
+				{PatternIndex: 0, Start: 0, End: 1},
+				{PatternIndex: 2, Start: 0, End: 1},
+				{PatternIndex: 1, Start: 1, End: 1},
+			},
+		},
+		{
+			name:       "ignore case",
+			patterns:   []string{"World"},
+			text:       "hello WORLD world",
+			ignoreCase: true,
+			want: []Match{
+				{PatternIndex: 0, Start: 6, End: 10},
+				{PatternIndex: 0, Start: 12, End: 16},
+			},
+		},
+		{
+			name:     "pattern longer than text",
+			patterns: []string{"longpattern"},
+			text:     "long",
+			want:     nil,
+		},
+		{
+			name:     "empty patterns never match",
+			patterns: []string{"", "b"},
+			text:     "abc",
+			want:     []Match{{PatternIndex: 1, Start: 1, End: 1}},
+		},
+		{
+			name:     "no patterns",
+			patterns: nil,
+			text:     "abc",
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cw := New(tt.patterns, tt.ignoreCase)
+			got := cw.FindAll(tt.text)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindAll() = %v, want %v", got, tt.want)
+			}
+			if gotBytes := cw.FindAllBytes([]byte(tt.text)); !reflect.DeepEqual(gotBytes, tt.want) {
+				t.Errorf("FindAllBytes() = %v, want %v", gotBytes, tt.want)
+			}
+			if c := cw.Count(tt.text); c != len(tt.want) {
+				t.Errorf("Count() = %d, want %d", c, len(tt.want))
+			}
+			if c := cw.CountBytes([]byte(tt.text)); c != len(tt.want) {
+				t.Errorf("CountBytes() = %d, want %d", c, len(tt.want))
+			}
+			if ok := cw.Contains(tt.text); ok != (len(tt.want) > 0) {
+				t.Errorf("Contains() = %v, want %v", ok, len(tt.want) > 0)
+			}
+			if ok := cw.ContainsBytes([]byte(tt.text)); ok != (len(tt.want) > 0) {
+				t.Errorf("ContainsBytes() = %v, want %v", ok, len(tt.want) > 0)
+			}
+		})
+	}
+}
+
+// TestCommentzWalterMatchesAhoCorasick checks that the shifts never skip a match by
+// comparing with Aho-Corasick's Standard search on random patterns and texts.
+func TestCommentzWalterMatchesAhoCorasick(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	randString := func(alphabet string, n int) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = alphabet[rng.IntN(len(alphabet))]
+		}
+		return string(b)
+	}
+
+	for iter := 0; iter < 2000; iter++ {
+		alphabet := "abcAB"[:2+rng.IntN(4)]
+		patterns := make([]string, 1+rng.IntN(5))
+		for i := range patterns {
+			patterns[i] = randString(alphabet, 1+rng.IntN(6))
+		}
+		text := randString(alphabet, rng.IntN(60))
+		ignoreCase := rng.IntN(2) == 0
+
+		var want []Match
+		for _, m := range ahocorasick.New(patterns, ignoreCase).FindAll(text) {
+			want = append(want, Match{PatternIndex: m.PatternIndex, Start: m.Start, End: m.End})
+		}
+		got := New(patterns, ignoreCase).FindAll(text)
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("FindAll(%q) with patterns %q, ignoreCase=%v = %v, want %v",
+				text, patterns, ignoreCase, got, want)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+// Package commentzwalter implements the Commentz-Walter multi-pattern string
+// search algorithm, a generalization of Boyer-Moore to many patterns.
+package commentzwalter
@@ -0,0 +1,3 @@
+// Package fuzzy implements approximate string search, finding occurrences of a
+// pattern within a maximum Levenshtein edit distance.
+package fuzzy
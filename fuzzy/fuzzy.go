@@ -0,0 +1,159 @@
+package fuzzy
+
+// Fuzzy represents an approximate pattern matcher.
+// A match is a span of text that can be turned into the pattern with at most
+// maxDist single-byte insertions, deletions or substitutions, which makes it
+// suitable for text with occasional wrong characters such as OCR output.
+// The search is Sellers' dynamic programming algorithm, taking O(len(pattern))
+// time per text byte.
+type Fuzzy struct {
+	pat        []byte // pattern (converted to lowercase if ignoreCase is true)
+	ignoreCase bool   // case insensitivity flag
+}
+
+// Match represents an approximate match found in text.
+type Match struct {
+	Start    int // start index of the match
+	End      int // end index of the match (inclusive)
+	Distance int // edit distance between the matched text and the pattern
+}
+
+// New creates a new Fuzzy matcher for the given pattern.
+// If ignoreCase is true, ASCII letters are compared case-insensitively.
+// An empty pattern gives a matcher that never matches, whatever maxDist.
+func New(pattern string, ignoreCase bool) *Fuzzy {
+	p := []byte(pattern)
+
+	// Convert pattern to lowercase if case-insensitive search is requested
+	if ignoreCase {
+		for i := 0; i < len(p); i++ {
+			c := p[i]
+			// Consider only ASCII range ('A'~'Z')
+			if c >= 'A' && c <= 'Z' {
+				p[i] = c + ('a' - 'A')
+			}
+		}
+	}
+
+	return &Fuzzy{
+		pat:        p,
+		ignoreCase: ignoreCase,
+	}
+}
+
+// FindAll returns the non-overlapping approximate matches of the pattern in the text
+// whose edit distance is at most maxDist, in order of position.
+// A match is extended for as long as doing so lowers its distance, so "hello" with
+// maxDist 1 matches all of "hello" rather than stopping at "hell".
+// A negative maxDist is treated as 0, which finds exact matches only.
+// Returns an empty slice if no matches are found or the pattern is empty.
+func (f *Fuzzy) FindAll(txt string, maxDist int) []Match {
+	return f._findAll([]byte(txt), maxDist, 0)
+}
+
+// FindAllBytes returns the non-overlapping approximate matches of the pattern in the byte slice
+// whose edit distance is at most maxDist.
+// Returns an empty slice if no matches are found or the pattern is empty.
+func (f *Fuzzy) FindAllBytes(data []byte, maxDist int) []Match {
+	return f._findAll(data, maxDist, 0)
+}
+
+// Contains reports whether the pattern appears in the text within edit distance maxDist.
+func (f *Fuzzy) Contains(txt string, maxDist int) bool {
+	return len(f._findAll([]byte(txt), maxDist, 1)) > 0
+}
+
+// ContainsBytes reports whether the pattern appears in the byte slice within edit distance maxDist.
+func (f *Fuzzy) ContainsBytes(data []byte, maxDist int) bool {
+	return len(f._findAll(data, maxDist, 1)) > 0
+}
+
+// _findAll runs the search over data. If limit is positive, the search stops once
+// limit matches have been found.
+//
+// After each text byte, prev[i] is the smallest edit distance between pat[:i] and any
+// text span ending at that byte, and prevStart[i] is where the best such span begins.
+// Whenever prev[m] drops to maxDist or below a candidate is recorded. It is replaced
+// as long as the next position ends an overlapping span with a strictly smaller
+// distance; otherwise it is reported and the search restarts right after it, so
+// matches never overlap.
+func (f *Fuzzy) _findAll(data []byte, maxDist, limit int) []Match {
+	results := []Match{}
+	m := len(f.pat)
+	if m == 0 {
+		return results
+	}
+	maxDist = max(maxDist, 0)
+
+	prev := make([]int, m+1)
+	prevStart := make([]int, m+1)
+	cur := make([]int, m+1)
+	curStart := make([]int, m+1)
+	reset := func(from int) {
+		for i := range prev {
+			prev[i] = i
+			prevStart[i] = from
+		}
+	}
+	reset(0)
+
+	var cand Match
+	found := false
+	for j := 0; j < len(data); j++ {
+		c := f.normChar(data[j])
+		cur[0] = 0
+		curStart[0] = j + 1
+		for i := 1; i <= m; i++ {
+			// Substitution or match
+			d, s := prev[i-1], prevStart[i-1]
+			if f.pat[i-1] != c {
+				d++
+			}
+			// Pattern byte missing from the text
+			if cur[i-1]+1 < d {
+				d, s = cur[i-1]+1, curStart[i-1]
+			}
+			// Extra byte in the text
+			if prev[i]+1 < d {
+				d, s = prev[i]+1, prevStart[i]
+			}
+			cur[i], curStart[i] = d, s
+		}
+		prev, cur = cur, prev
+		prevStart, curStart = curStart, prevStart
+
+		d, s := prev[m], prevStart[m]
+		if found && d < cand.Distance && s <= cand.End {
+			// A better match overlapping the candidate replaces it
+			cand = Match{Start: s, End: j, Distance: d}
+			continue
+		}
+		if found {
+			results = append(results, cand)
+			if len(results) == limit {
+				return results
+			}
+			found = false
+			j = cand.End
+			reset(j + 1)
+			continue
+		}
+		if d <= maxDist && s <= j {
+			cand = Match{Start: s, End: j, Distance: d}
+			found = true
+		}
+	}
+	if found {
+		results = append(results, cand)
+	}
+	return results
+}
+
+// normChar normalizes a byte for case-insensitive comparison.
+// If ignoreCase is true, converts ASCII uppercase letters to lowercase.
+func (f *Fuzzy) normChar(c byte) byte {
+	if f.ignoreCase && c >= 'A' && c <= 'Z' {
+		return c + ('a' - 'A')
+	}
+	return c
+}
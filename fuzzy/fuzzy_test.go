@@ -0,0 +1,185 @@
+package fuzzy
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"testing"
+)
+
+func TestFindAll(t *testing.T) {
+	tests := []struct {
+		name       string
+		pattern    string
+		text       string
+		maxDist    int
+		ignoreCase bool
+		want       []Match
+	}{
+		{
+			name:    "Exact match",
+			pattern: "hello",
+			text:    "say hello",
+			maxDist: 0,
+			want:    []Match{{Start: 4, End: 8, Distance: 0}},
+		},
+		{
+			name:    "Substitution",
+			pattern: "hello",
+			text:    "say hallo!",
+			maxDist: 1,
+			want:    []Match{{Start: 4, End: 8, Distance: 1}},
+		},
+		{
+			name:    "Deletion",
+			pattern: "hello",
+			text:    "helo world",
+			maxDist: 1,
+			want:    []Match{{Start: 0, End: 3, Distance: 1}},
+		},
+		{
+			name:    "Insertion",
+			pattern: "hello",
+			text:    "hexllo",
+			maxDist: 1,
+			want:    []Match{{Start: 0, End: 5, Distance: 1}},
+		},
+		{
+			name:    "Extends to the exact match",
+			pattern: "hello",
+			text:    "hello",
+			maxDist: 1,
+			want:    []Match{{Start: 0, End: 4, Distance: 0}},
+		},
+		{
+			name:    "Too many errors",
+			pattern: "hello",
+			text:    "hxllx",
+			maxDist: 1,
+			want:    []Match{},
+		},
+		{
+			name:    "Several OCR errors",
+			pattern: "invoice",
+			text:    "lnvoice 1nvo1ce invoice",
+			maxDist: 2,
+			want: []Match{
+				{Start: 0, End: 6, Distance: 1},
+				{Start: 8, End: 14, Distance: 2},
+				{Start: 16, End: 22, Distance: 0},
+			},
+		},
+		{
+			name:       "Ignore case",
+			pattern:    "Hello",
+			text:       "HELLO hELo",
+			maxDist:    1,
+			ignoreCase: true,
+			want: []Match{
+				{Start: 0, End: 4, Distance: 0},
+				{Start: 6, End: 9, Distance: 1},
+			},
+		},
+		{
+			name:    "Negative distance is exact",
+			pattern: "ab",
+			text:    "ab ac",
+			maxDist: -1,
+			want:    []Match{{Start: 0, End: 1, Distance: 0}},
+		},
+		{
+			name:    "Empty pattern",
+			pattern: "",
+			text:    "abc",
+			maxDist: 1,
+			want:    []Match{},
+		},
+		{
+			name:    "Empty text",
+			pattern: "abc",
+			text:    "",
+			maxDist: 1,
+			want:    []Match{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := New(tc.pattern, tc.ignoreCase)
+			if got := f.FindAll(tc.text, tc.maxDist); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("FindAll(%q, %d) = %v; want %v", tc.text, tc.maxDist, got, tc.want)
+			}
+			if got := f.FindAllBytes([]byte(tc.text), tc.maxDist); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("FindAllBytes(%q, %d) = %v; want %v", tc.text, tc.maxDist, got, tc.want)
+			}
+			if got, want := f.Contains(tc.text, tc.maxDist), len(tc.want) > 0; got != want {
+				t.Errorf("Contains(%q, %d) = %v; want %v", tc.text, tc.maxDist, got, want)
+			}
+		})
+	}
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j-1]+cost, prev[j]+1, cur[j-1]+1)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func TestFindAllRandom(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	randString := func(n int) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = "abc"[rng.IntN(3)]
+		}
+		return string(b)
+	}
+
+	for i := 0; i < 1000; i++ {
+		pattern := randString(1 + rng.IntN(5))
+		text := randString(rng.IntN(30))
+		maxDist := rng.IntN(len(pattern))
+
+		prevEnd := -1
+		for _, m := range New(pattern, false).FindAll(text, maxDist) {
+			if m.Start <= prevEnd || m.End < m.Start {
+				t.Fatalf("FindAll(%q, %d) for %q: match %v overlaps or is empty", text, maxDist, pattern, m)
+			}
+			prevEnd = m.End
+			if d := levenshtein(pattern, text[m.Start:m.End+1]); d != m.Distance || d > maxDist {
+				t.Fatalf("FindAll(%q, %d) for %q: match %v has true distance %d", text, maxDist, pattern, m, d)
+			}
+		}
+
+		// Every exact occurrence lies within some reported match
+		matches := New(pattern, false).FindAll(text, maxDist)
+		for s := 0; s+len(pattern) <= len(text); s++ {
+			if text[s:s+len(pattern)] != pattern {
+				continue
+			}
+			covered := false
+			for _, m := range matches {
+				if m.Start <= s+len(pattern)-1 && s <= m.End {
+					covered = true
+				}
+			}
+			if !covered {
+				t.Fatalf("FindAll(%q, %d) for %q = %v misses exact match at %d", text, maxDist, pattern, matches, s)
+			}
+		}
+	}
+}
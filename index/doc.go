@@ -0,0 +1,3 @@
+// Package index implements a suffix array index over a fixed text, for answering many
+// different pattern queries against the same text.
+package index
@@ -0,0 +1,218 @@
+package index
+
+import (
+	"bytes"
+	"slices"
+)
+
+// Index is a suffix array with its LCP array over a fixed text.
+// The suffix array lists the starting positions of all suffixes of the text in
+// lexicographic order, so the occurrences of any pattern are the suffixes it prefixes,
+// which form one contiguous run found by binary search.
+//
+// The finished index keeps a copy of the text and two ints per byte, about 17n bytes for a
+// text of n bytes on 64-bit platforms, and building it takes O(n log n) time and briefly
+// about 32n more bytes. A query costs O(m log n) byte comparisons in the worst case for a
+// pattern of m bytes, however often the pattern occurs. The binary search skips the bytes
+// both of its current bounds share with the pattern, a heuristic without the LCP-LR arrays
+// that would guarantee O(m + log n): on typical text it avoids most repeated comparisons, but
+// when the two bounds share little with each other it saves nothing. On 1 MiB of English-like text, building takes about as long as a
+// hundred Boyer-Moore scans of it while a query takes well under a microsecond (see
+// BenchmarkNew and BenchmarkCount), so the index pays off for texts queried many times.
+//
+// An Index is immutable once built and safe for concurrent use.
+type Index struct {
+	text []byte
+	sa   []int // sa[i] is the starting position of the i-th smallest suffix
+	lcp  []int // lcp[i] is the length of the common prefix of the suffixes at sa[i-1] and sa[i]; lcp[0] is 0
+}
+
+// New builds the index of the given text.
+func New(text string) *Index {
+	x := &Index{text: []byte(text)}
+	x.sa = buildSuffixArray(x.text)
+	x.lcp = buildLCP(x.text, x.sa)
+	return x
+}
+
+// Len returns the length of the indexed text in bytes.
+func (x *Index) Len() int {
+	return len(x.text)
+}
+
+// Count returns the number of occurrences of the pattern in the text, counting occurrences
+// that overlap (e.g. "ana" occurs twice in "banana"). An empty pattern never matches.
+// It costs two binary searches, O(m log n) byte comparisons at worst (see Index).
+func (x *Index) Count(pattern string) int {
+	return x.CountBytes([]byte(pattern))
+}
+
+// CountBytes returns the number of occurrences of the pattern in the text, counting overlapping ones.
+func (x *Index) CountBytes(pattern []byte) int {
+	if len(pattern) == 0 {
+		return 0
+	}
+	return x.search(pattern, true) - x.search(pattern, false)
+}
+
+// Locate returns the starting positions of all occurrences of the pattern in the text, in
+// ascending order, including occurrences that overlap a previous one. Returns nil if the
+// pattern does not occur or is empty. For k occurrences, reading them off the index costs
+// O(k) and sorting them O(k log k) on top of the query.
+func (x *Index) Locate(pattern string) []int {
+	return x.LocateBytes([]byte(pattern))
+}
+
+// LocateBytes returns the starting positions of all occurrences of the pattern in the text,
+// in ascending order. Returns nil if the pattern does not occur or is empty.
+func (x *Index) LocateBytes(pattern []byte) []int {
+	if len(pattern) == 0 {
+		return nil
+	}
+	lo := x.search(pattern, false)
+	if lo == len(x.sa) || !bytes.HasPrefix(x.text[x.sa[lo]:], pattern) {
+		return nil
+	}
+	// The run of suffixes starting with the pattern ends at the first one sharing fewer
+	// than len(pattern) bytes with its predecessor, which the LCP array tells without
+	// comparing any more bytes
+	hi := lo + 1
+	for hi < len(x.sa) && x.lcp[hi] >= len(pattern) {
+		hi++
+	}
+	positions := slices.Clone(x.sa[lo:hi])
+	slices.Sort(positions)
+	return positions
+}
+
+// search returns the position in the suffix array of the first suffix not smaller than p, or
+// with upper, of the first suffix greater than p, comparing suffixes by their first len(p)
+// bytes. The suffixes starting with p lie between the two. Every suffix between the current
+// bounds shares at least the shorter of the bounds' common prefixes with p, so each
+// comparison resumes after those bytes rather than at the start of the pattern. This is only
+// a heuristic: the shorter prefix may stay short while the longer grows, so a comparison can
+// still reread up to len(p) bytes, and the search is O(len(p) log n) at worst.
+func (x *Index) search(p []byte, upper bool) int {
+	lo, hi := 0, len(x.sa)
+	loLCP, hiLCP := 0, 0 // common prefix lengths of p with the suffixes just below lo and at hi
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+		suffix := x.text[x.sa[mid]:]
+		l := min(loLCP, hiLCP)
+		for l < len(p) && l < len(suffix) && p[l] == suffix[l] {
+			l++
+		}
+		greater := l < len(p) && l < len(suffix) && suffix[l] > p[l]
+		if greater || (!upper && l == len(p)) {
+			hi, hiLCP = mid, l
+		} else {
+			lo, loLCP = mid+1, l
+		}
+	}
+	return lo
+}
+
+// buildSuffixArray sorts the suffixes of text by prefix doubling: once the suffixes are ranked
+// by their first k bytes, the rank pair of positions i and i+k orders them by their first 2k
+// bytes. Each round sorts the pairs with two counting sort passes, so it takes linear time,
+// and at most log2(n) rounds are needed.
+func buildSuffixArray(text []byte) []int {
+	n := len(text)
+	sa := make([]int, n)
+	rank := make([]int, n)
+	tmp := make([]int, n)
+	count := make([]int, max(n, 256))
+
+	// Round 0: rank the suffixes by their first byte
+	for _, c := range text {
+		count[c]++
+	}
+	for c, sum := 0, 0; c < 256; c++ {
+		count[c], sum = sum, sum+count[c]
+	}
+	for i, c := range text {
+		sa[count[c]] = i
+		count[c]++
+		rank[i] = int(c)
+	}
+	classes := 256
+
+	for k := 1; k < n; k *= 2 {
+		// Order by the second key: suffixes too short to have one sort first, then the rest
+		// follow the current order of the positions k before them
+		p := 0
+		for i := n - k; i < n; i++ {
+			tmp[p] = i
+			p++
+		}
+		for _, s := range sa {
+			if s >= k {
+				tmp[p] = s - k
+				p++
+			}
+		}
+
+		// Stable counting sort by the first key
+		clear(count[:classes])
+		for _, r := range rank {
+			count[r]++
+		}
+		for r, sum := 0, 0; r < classes; r++ {
+			count[r], sum = sum, sum+count[r]
+		}
+		for _, s := range tmp {
+			sa[count[rank[s]]] = s
+			count[rank[s]]++
+		}
+
+		// Re-rank: equal pairs share a rank
+		second := func(i int) int {
+			if i+k < n {
+				return rank[i+k]
+			}
+			return -1
+		}
+		tmp[sa[0]] = 0
+		classes = 1
+		for i := 1; i < n; i++ {
+			a, b := sa[i-1], sa[i]
+			if rank[a] != rank[b] || second(a) != second(b) {
+				classes++
+			}
+			tmp[b] = classes - 1
+		}
+		rank, tmp = tmp, rank
+		if classes == n {
+			break
+		}
+	}
+	return sa
+}
+
+// buildLCP computes the LCP array of the suffix array with Kasai's algorithm. Visiting the
+// suffixes in text order, the common prefix with the preceding suffix shrinks by at most one
+// byte from one position to the next, so the total work is linear.
+func buildLCP(text []byte, sa []int) []int {
+	n := len(text)
+	lcp := make([]int, n)
+	pos := make([]int, n) // pos[i] is the position of suffix i in sa
+	for i, s := range sa {
+		pos[s] = i
+	}
+	h := 0
+	for i := 0; i < n; i++ {
+		if pos[i] == 0 {
+			h = 0
+			continue
+		}
+		j := sa[pos[i]-1]
+		for i+h < n && j+h < n && text[i+h] == text[j+h] {
+			h++
+		}
+		lcp[pos[i]] = h
+		if h > 0 {
+			h--
+		}
+	}
+	return lcp
+}
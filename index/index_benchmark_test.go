@@ -0,0 +1,64 @@
+package index
+
+import (
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"github.com/notJoon/searcher/boyermoore"
+)
+
+// benchmarkText returns pseudo-English text of about n bytes: words of 3 to 9 letters drawn
+// with rough English letter frequencies, separated by spaces
+func benchmarkText(n int) string {
+	const letters = "eeeeeeeeeeeetttttttttaaaaaaaaooooooooiiiiiiinnnnnnnsssssshhhhhhrrrrrrddddllllcccuuummwwffggyyppbbvkjxqz"
+	rng := rand.New(rand.NewPCG(7, 7))
+	var sb strings.Builder
+	for sb.Len() < n {
+		for j := 3 + rng.IntN(7); j > 0; j-- {
+			sb.WriteByte(letters[rng.IntN(len(letters))])
+		}
+		sb.WriteByte(' ')
+	}
+	return sb.String()
+}
+
+func BenchmarkNew(b *testing.B) {
+	for _, size := range []struct {
+		name string
+		n    int
+	}{
+		{"64KiB", 64 << 10},
+		{"1MiB", 1 << 20},
+	} {
+		text := benchmarkText(size.n)
+		b.Run(size.name, func(b *testing.B) {
+			b.SetBytes(int64(len(text)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				New(text)
+			}
+		})
+	}
+}
+
+// BenchmarkCount answers one query against a 1 MiB text with the index and with a
+// Boyer-Moore scan, which is what the index build time has to be weighed against
+func BenchmarkCount(b *testing.B) {
+	text := benchmarkText(1 << 20)
+	x := New(text)
+	patterns := []string{"the", "tion", "eeeeeeeee"}
+	for _, p := range patterns {
+		b.Run("Index/"+p, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				x.Count(p)
+			}
+		})
+		bm := boyermoore.New(p, false)
+		b.Run("BoyerMoore/"+p, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				bm.CountOverlapping(text)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package index
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestIndex(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		pattern string
+		want    []int
+	}{
+		{"Overlapping matches", "banana", "ana", []int{1, 3}},
+		{"Single byte", "banana", "a", []int{1, 3, 5}},
+		{"Whole text", "banana", "banana", []int{0}},
+		{"Suffix of the text", "banana", "na", []int{2, 4}},
+		{"Longer than the text", "banana", "bananas", nil},
+		{"Prefix of a suffix only", "abc", "bcd", nil},
+		{"No match", "banana", "x", nil},
+		{"Repeated byte", "aaaaa", "aa", []int{0, 1, 2, 3}},
+		{"High bytes", "\xff\x00\xff\x00", "\x00\xff", []int{1}},
+		{"Empty pattern", "banana", "", nil},
+		{"Empty text", "", "a", nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			x := New(tc.text)
+			if got := x.Locate(tc.pattern); !slices.Equal(got, tc.want) {
+				t.Errorf("Locate(%q) = %v; want %v", tc.pattern, got, tc.want)
+			}
+			if got := x.LocateBytes([]byte(tc.pattern)); !slices.Equal(got, tc.want) {
+				t.Errorf("LocateBytes(%q) = %v; want %v", tc.pattern, got, tc.want)
+			}
+			if got := x.Count(tc.pattern); got != len(tc.want) {
+				t.Errorf("Count(%q) = %d; want %d", tc.pattern, got, len(tc.want))
+			}
+			if got := x.CountBytes([]byte(tc.pattern)); got != len(tc.want) {
+				t.Errorf("CountBytes(%q) = %d; want %d", tc.pattern, got, len(tc.want))
+			}
+		})
+	}
+}
+
+// naiveLocate returns every position where pattern occurs in text, overlapping ones included
+func naiveLocate(text, pattern string) []int {
+	var positions []int
+	for i := 0; pattern != "" && i+len(pattern) <= len(text); i++ {
+		if text[i:i+len(pattern)] == pattern {
+			positions = append(positions, i)
+		}
+	}
+	return positions
+}
+
+// TestIndexRandom checks the suffix and LCP arrays and the queries on random texts over
+// small alphabets, where suffixes share long prefixes
+func TestIndexRandom(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	randString := func(alphabet string, n int) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = alphabet[rng.IntN(len(alphabet))]
+		}
+		return string(b)
+	}
+
+	for iter := 0; iter < 300; iter++ {
+		alphabet := []string{"a", "ab", "abc", "\x00\xffa"}[rng.IntN(4)]
+		text := randString(alphabet, rng.IntN(200))
+		x := New(text)
+
+		for i := 1; i < len(x.sa); i++ {
+			a, b := text[x.sa[i-1]:], text[x.sa[i]:]
+			if a >= b {
+				t.Fatalf("text %q: suffixes %q and %q out of order", text, a, b)
+			}
+			l := 0
+			for l < len(a) && l < len(b) && a[l] == b[l] {
+				l++
+			}
+			if x.lcp[i] != l {
+				t.Fatalf("text %q: lcp[%d] = %d; want %d", text, i, x.lcp[i], l)
+			}
+		}
+
+		for q := 0; q < 20; q++ {
+			var pattern string
+			if q%2 == 0 && len(text) > 0 {
+				start := rng.IntN(len(text))
+				pattern = text[start : start+1+rng.IntN(min(8, len(text)-start))]
+			} else {
+				pattern = randString(alphabet, 1+rng.IntN(6))
+			}
+			want := naiveLocate(text, pattern)
+			if got := x.Locate(pattern); !slices.Equal(got, want) {
+				t.Fatalf("text %q: Locate(%q) = %v; want %v", text, pattern, got, want)
+			}
+			if got := x.Count(pattern); got != len(want) {
+				t.Fatalf("text %q: Count(%q) = %d; want %d", text, pattern, got, len(want))
+			}
+		}
+	}
+}
+
+func TestIndexKeepsOwnCopy(t *testing.T) {
+	text := []byte("abcabc")
+	x := New(string(text))
+	copy(text, "xxxxxx")
+	if got := x.Count("abc"); got != 2 {
+		t.Errorf("Count(%q) = %d after changing the source; want 2", "abc", got)
+	}
+	if x.Len() != 6 || !bytes.Equal(x.text, []byte("abcabc")) {
+		t.Errorf("indexed text = %q; want %q", x.text, "abcabc")
+	}
+}
@@ -0,0 +1,2 @@
+// Package kmp implements the Knuth-Morris-Pratt string search algorithm.
+package kmp
@@ -0,0 +1,148 @@
+package kmp
+
+// KMP represents a pattern matcher using the Knuth-Morris-Pratt algorithm.
+// It contains the pattern, case sensitivity option, and the precomputed
+// failure function. The search never moves backwards in the text, which
+// gives a linear worst case regardless of the input.
+type KMP struct {
+	pat        []byte // pattern (converted to lowercase if ignoreCase is true)
+	ignoreCase bool   // case insensitivity flag
+	fail       []int  // fail[i] is the length of the longest proper border of pat[:i+1]
+}
+
+// New creates a new KMP matcher for the given pattern.
+// If ignoreCase is true, the search will be case-insensitive.
+// An empty pattern gives a matcher that never matches.
+func New(pattern string, ignoreCase bool) *KMP {
+	p := []byte(pattern)
+
+	// Convert pattern to lowercase if case-insensitive search is requested
+	if ignoreCase {
+		for i := 0; i < len(p); i++ {
+			c := p[i]
+			// Consider only ASCII range ('A'~'Z')
+			if c >= 'A' && c <= 'Z' {
+				p[i] = c + ('a' - 'A')
+			}
+		}
+	}
+
+	k := &KMP{
+		pat:        p,
+		ignoreCase: ignoreCase,
+	}
+	k.buildFailure()
+
+	return k
+}
+
+// FindAll returns the starting indices of all non-overlapping matches of the pattern in the text.
+// Returns an empty slice if no matches are found.
+func (k *KMP) FindAll(txt string) []int {
+	return k._findAll([]byte(txt), 0)
+}
+
+// FindAllBytes returns the starting indices of all non-overlapping matches of the pattern in the byte slice.
+// Returns an empty slice if no matches are found.
+func (k *KMP) FindAllBytes(data []byte) []int {
+	return k._findAll(data, 0)
+}
+
+// FindFirst returns the index of the first occurrence of the pattern in the text.
+// Returns -1 if the pattern is not found.
+func (k *KMP) FindFirst(txt string) int {
+	return k._findFirst([]byte(txt))
+}
+
+// FindFirstBytes returns the index of the first occurrence of the pattern in the byte slice.
+// Returns -1 if the pattern is not found.
+func (k *KMP) FindFirstBytes(data []byte) int {
+	return k._findFirst(data)
+}
+
+// Contains reports whether the pattern appears in the text.
+func (k *KMP) Contains(txt string) bool {
+	return k.FindFirst(txt) != -1
+}
+
+// ContainsBytes reports whether the pattern appears in the byte slice.
+func (k *KMP) ContainsBytes(data []byte) bool {
+	return k.FindFirstBytes(data) != -1
+}
+
+// Count returns the number of non-overlapping occurrences of the pattern in the text.
+func (k *KMP) Count(txt string) int {
+	return len(k.FindAll(txt))
+}
+
+// CountBytes returns the number of non-overlapping occurrences of the pattern in the byte slice.
+func (k *KMP) CountBytes(data []byte) int {
+	return len(k.FindAllBytes(data))
+}
+
+// _findFirst returns the index of the first match, or -1 if there is none.
+func (k *KMP) _findFirst(data []byte) int {
+	res := k._findAll(data, 1)
+	if len(res) > 0 {
+		return res[0]
+	}
+	return -1
+}
+
+// _findAll is an internal method that implements the Knuth-Morris-Pratt search algorithm.
+// It returns the indices of the non-overlapping matches in the given byte slice.
+// If limit is positive, the search stops once limit matches have been found.
+func (k *KMP) _findAll(data []byte, limit int) []int {
+	var results []int
+	m := len(k.pat)
+	if m == 0 || len(data) < m {
+		return results
+	}
+
+	q := 0 // number of pattern bytes currently matched
+	for i := 0; i < len(data); i++ {
+		c := k.normChar(data[i])
+		// Fall back along the failure function until c extends the match
+		for q > 0 && k.pat[q] != c {
+			q = k.fail[q-1]
+		}
+		if k.pat[q] == c {
+			q++
+		}
+		if q == m {
+			// Pattern fully matched
+			results = append(results, i-m+1)
+			if len(results) == limit {
+				break
+			}
+			// Restart so the next match does not overlap this one
+			q = 0
+		}
+	}
+	return results
+}
+
+// normChar normalizes a byte for case-insensitive comparison.
+// If ignoreCase is true, converts ASCII uppercase letters to lowercase.
+func (k *KMP) normChar(c byte) byte {
+	if k.ignoreCase && c >= 'A' && c <= 'Z' {
+		return c + ('a' - 'A')
+	}
+	return c
+}
+
+// buildFailure constructs the failure function (prefix function) of the pattern.
+func (k *KMP) buildFailure() {
+	m := len(k.pat)
+	k.fail = make([]int, m)
+	q := 0
+	for i := 1; i < m; i++ {
+		for q > 0 && k.pat[i] != k.pat[q] {
+			q = k.fail[q-1]
+		}
+		if k.pat[i] == k.pat[q] {
+			q++
+		}
+		k.fail[i] = q
+	}
+}
@@ -0,0 +1,201 @@
+package kmp
+
+import (
+	"testing"
+)
+
+func TestStringSearch(t *testing.T) {
+	tests := []struct {
+		name         string
+		pattern      string
+		text         string
+		ignoreCase   bool
+		wantAll      []int
+		wantFirst    int
+		wantContains bool
+		wantCount    int
+	}{
+		{
+			name:         "Basic match",
+			pattern:      "ABC",
+			text:         "ZZZABCZZZ",
+			ignoreCase:   false,
+			wantAll:      []int{3},
+			wantFirst:    3,
+			wantContains: true,
+			wantCount:    1,
+		},
+		{
+			name:         "No match",
+			pattern:      "ABC",
+			text:         "ZZZABZ",
+			ignoreCase:   false,
+			wantAll:      []int{},
+			wantFirst:    -1,
+			wantContains: false,
+			wantCount:    0,
+		},
+		{
+			name:         "Multiple matches",
+			pattern:      "AB",
+			text:         "ABABAB",
+			ignoreCase:   false,
+			wantAll:      []int{0, 2, 4},
+			wantFirst:    0,
+			wantContains: true,
+			wantCount:    3,
+		},
+		{
+			name:         "Ignore case",
+			pattern:      "AbC",
+			text:         "zzZabcZZZAbCZZabcdZZ",
+			ignoreCase:   true,
+			wantAll:      []int{3, 9, 14}, // "abc" -> index 3, "AbC" -> index 9, "abcd" -> index 14 match "AbC" prefix
+			wantFirst:    3,
+			wantContains: true,
+			wantCount:    3,
+		},
+		{
+			name:         "Empty pattern",
+			pattern:      "",
+			text:         "ABC",
+			ignoreCase:   false,
+			wantAll:      []int{},
+			wantFirst:    -1,
+			wantContains: false,
+			wantCount:    0,
+		},
+		{
+			name:         "Pattern longer than text",
+			pattern:      "ABCDEFG",
+			text:         "ABC",
+			ignoreCase:   false,
+			wantAll:      []int{},
+			wantFirst:    -1,
+			wantContains: false,
+			wantCount:    0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			k := New(tc.pattern, tc.ignoreCase)
+
+			gotAll := k.FindAll(tc.text)
+			if !equalIntSlices(gotAll, tc.wantAll) {
+				t.Errorf("FindAll(%q) = %v; want %v", tc.text, gotAll, tc.wantAll)
+			}
+
+			gotFirst := k.FindFirst(tc.text)
+			if gotFirst != tc.wantFirst {
+				t.Errorf("FindFirst(%q) = %d; want %d", tc.text, gotFirst, tc.wantFirst)
+			}
+
+			gotContains := k.Contains(tc.text)
+			if gotContains != tc.wantContains {
+				t.Errorf("Contains(%q) = %v; want %v", tc.text, gotContains, tc.wantContains)
+			}
+
+			gotCount := k.Count(tc.text)
+			if gotCount != tc.wantCount {
+				t.Errorf("Count(%q) = %d; want %d", tc.text, gotCount, tc.wantCount)
+			}
+		})
+	}
+}
+
+func TestByteSearch(t *testing.T) {
+	tests := []struct {
+		name         string
+		pattern      string
+		data         []byte
+		ignoreCase   bool
+		wantAll      []int
+		wantFirst    int
+		wantContains bool
+		wantCount    int
+	}{
+		{
+			name:         "Basic match (bytes)",
+			pattern:      "ABC",
+			data:         []byte("ZZZABCZZZ"),
+			ignoreCase:   false,
+			wantAll:      []int{3},
+			wantFirst:    3,
+			wantContains: true,
+			wantCount:    1,
+		},
+		{
+			name:         "Ignore case (bytes)",
+			pattern:      "AbC",
+			data:         []byte("ZZabcZZABCZZAbcdZZ"),
+			ignoreCase:   true,
+			wantAll:      []int{2, 7, 12},
+			wantFirst:    2,
+			wantContains: true,
+			wantCount:    3,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			k := New(tc.pattern, tc.ignoreCase)
+
+			gotAll := k.FindAllBytes(tc.data)
+			if !equalIntSlices(gotAll, tc.wantAll) {
+				t.Errorf("FindAllBytes(%q) = %v; want %v", string(tc.data), gotAll, tc.wantAll)
+			}
+
+			gotFirst := k.FindFirstBytes(tc.data)
+			if gotFirst != tc.wantFirst {
+				t.Errorf("FindFirstBytes(%q) = %d; want %d", string(tc.data), gotFirst, tc.wantFirst)
+			}
+
+			gotContains := k.ContainsBytes(tc.data)
+			if gotContains != tc.wantContains {
+				t.Errorf("ContainsBytes(%q) = %v; want %v", string(tc.data), gotContains, tc.wantContains)
+			}
+
+			gotCount := k.CountBytes(tc.data)
+			if gotCount != tc.wantCount {
+				t.Errorf("CountBytes(%q) = %d; want %d", string(tc.data), gotCount, tc.wantCount)
+			}
+		})
+	}
+}
+
+func TestNonOverlapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		text    string
+		want    []int
+	}{
+		{"Repeated single character", "aa", "aaaa", []int{0, 2}},
+		{"Self-overlapping pattern", "ana", "banana", []int{1}},
+		{"Periodic pattern", "abab", "abababababab", []int{0, 4, 8}},
+		{"Border shorter than half", "abcab", "abcabcab", []int{0}},
+		{"Mismatch after partial match", "aab", "aaab", []int{1}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := New(tc.pattern, false).FindAll(tc.text)
+			if !equalIntSlices(got, tc.want) {
+				t.Errorf("FindAll(%q) = %v; want %v", tc.text, got, tc.want)
+			}
+		})
+	}
+}
+
+func equalIntSlices(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,3 @@
+// Package rabinkarp implements the Rabin-Karp string search algorithm
+// for many patterns of the same length.
+package rabinkarp
@@ -0,0 +1,176 @@
+package rabinkarp
+
+import (
+	"bytes"
+	"errors"
+	"math/bits"
+	"math/rand/v2"
+)
+
+// Errors returned by New.
+var (
+	ErrNoPatterns     = errors.New("rabinkarp: no patterns")
+	ErrEmptyPattern   = errors.New("rabinkarp: empty pattern")
+	ErrLengthMismatch = errors.New("rabinkarp: patterns must all have the same length")
+)
+
+// modulus is the Mersenne prime 2^61-1. Hashes are polynomials in a random
+// base evaluated modulo this prime, so two different windows of length m
+// collide with probability at most m/2^61 for any fixed input. Choosing the
+// base at random in New keeps an adversary from crafting colliding inputs
+// without knowing it.
+const modulus = 1<<61 - 1
+
+// Match represents pattern matching information found in text
+type Match struct {
+	PatternIndex int // which pattern in patterns
+	Start        int // start index of the match
+	End          int // end index of the match (inclusive)
+}
+
+// RabinKarp is a multi-pattern matcher for patterns that all have the same length.
+// It compares a rolling hash of every text window against the pattern hashes and
+// verifies candidates byte by byte, so hash collisions never produce false matches.
+type RabinKarp struct {
+	patterns [][]byte
+	m        int              // common pattern length
+	base     uint64           // hash base in [256, modulus)
+	pow      uint64           // base^(m-1) mod modulus, used to drop the leading byte
+	byHash   map[uint64][]int // pattern indices by hash
+}
+
+// New creates a RabinKarp matcher for the given patterns.
+// All patterns must be non-empty and have the same length in bytes.
+func New(patterns []string) (*RabinKarp, error) {
+	if len(patterns) == 0 {
+		return nil, ErrNoPatterns
+	}
+	m := len(patterns[0])
+	if m == 0 {
+		return nil, ErrEmptyPattern
+	}
+
+	rk := &RabinKarp{
+		m:      m,
+		base:   256 + rand.Uint64N(modulus-256),
+		byHash: make(map[uint64][]int, len(patterns)),
+	}
+	rk.pow = 1
+	for i := 1; i < m; i++ {
+		rk.pow = mulMod(rk.pow, rk.base)
+	}
+
+	for idx, p := range patterns {
+		if len(p) != m {
+			return nil, ErrLengthMismatch
+		}
+		b := []byte(p)
+		rk.patterns = append(rk.patterns, b)
+		h := rk.hash(b)
+		rk.byHash[h] = append(rk.byHash[h], idx)
+	}
+	return rk, nil
+}
+
+// FindAll returns every match of every pattern in the text, ordered by position,
+// then by pattern index
+func (rk *RabinKarp) FindAll(text string) []Match {
+	return rk._findAll([]byte(text), false)
+}
+
+// FindAllBytes returns every match of every pattern in the byte slice, ordered by position,
+// then by pattern index
+func (rk *RabinKarp) FindAllBytes(data []byte) []Match {
+	return rk._findAll(data, false)
+}
+
+// Contains returns whether any pattern matches in the text
+func (rk *RabinKarp) Contains(text string) bool {
+	return len(rk._findAll([]byte(text), true)) > 0
+}
+
+// ContainsBytes returns whether any pattern matches in the byte slice
+func (rk *RabinKarp) ContainsBytes(data []byte) bool {
+	return len(rk._findAll(data, true)) > 0
+}
+
+// Count returns the number of all matches found in the text
+func (rk *RabinKarp) Count(text string) int {
+	return len(rk.FindAll(text))
+}
+
+// CountBytes returns the number of all matches found in the byte slice
+func (rk *RabinKarp) CountBytes(data []byte) int {
+	return len(rk.FindAllBytes(data))
+}
+
+// _findAll slides a window of the pattern length over data, updating its hash in
+// constant time, and verifies every window whose hash equals a pattern hash.
+// If first is true, it returns as soon as one match is found.
+func (rk *RabinKarp) _findAll(data []byte, first bool) []Match {
+	var matches []Match
+	m := rk.m
+	if len(data) < m {
+		return matches
+	}
+
+	h := rk.hash(data[:m])
+	for s := 0; ; s++ {
+		for _, idx := range rk.byHash[h] {
+			// Verify to rule out hash collisions
+			if bytes.Equal(rk.patterns[idx], data[s:s+m]) {
+				matches = append(matches, Match{
+					PatternIndex: idx,
+					Start:        s,
+					End:          s + m - 1,
+				})
+				if first {
+					return matches
+				}
+			}
+		}
+		if s+m == len(data) {
+			break
+		}
+		// Roll the hash: drop data[s], append data[s+m]
+		h = subMod(h, mulMod(uint64(data[s]), rk.pow))
+		h = addMod(mulMod(h, rk.base), uint64(data[s+m]))
+	}
+	return matches
+}
+
+// hash returns the polynomial hash of b.
+func (rk *RabinKarp) hash(b []byte) uint64 {
+	var h uint64
+	for _, c := range b {
+		h = addMod(mulMod(h, rk.base), uint64(c))
+	}
+	return h
+}
+
+// mulMod returns a*b mod modulus for a, b < modulus.
+func mulMod(a, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, b)
+	// a*b = hi*2^64 + lo; 2^64 = 8 * 2^61 ≡ 8 (mod 2^61-1)
+	r := (lo & modulus) + (lo >> 61) + (hi << 3)
+	return reduce(r)
+}
+
+// addMod returns a+b mod modulus for a, b < modulus.
+func addMod(a, b uint64) uint64 {
+	return reduce(a + b)
+}
+
+// subMod returns a-b mod modulus for a, b < modulus.
+func subMod(a, b uint64) uint64 {
+	return reduce(a + modulus - b)
+}
+
+// reduce folds r < 2^64 into [0, modulus).
+func reduce(r uint64) uint64 {
+	r = (r & modulus) + (r >> 61)
+	if r >= modulus {
+		r -= modulus
+	}
+	return r
+}
@@ -0,0 +1,143 @@
+package rabinkarp
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRabinKarpSearch(t *testing.T) {
+	type testCase struct {
+		name         string
+		patterns     []string
+		text         string
+		wantMatches  []Match
+		wantContains bool
+		wantCount    int
+	}
+	tests := []testCase{
+		{
+			name:     "Multiple patterns",
+			patterns: []string{"abc", "bcd", "zzz"},
+			text:     "xabcdzzz",
+			wantMatches: []Match{
+				{PatternIndex: 0, Start: 1, End: 3},
+				{PatternIndex: 1, Start: 2, End: 4},
+				{PatternIndex: 2, Start: 5, End: 7},
+			},
+			wantContains: true,
+			wantCount:    3,
+		},
+		{
+			name:     "Overlapping occurrences",
+			patterns: []string{"aa"},
+			text:     "aaaa",
+			wantMatches: []Match{
+				{PatternIndex: 0, Start: 0, End: 1},
+				{PatternIndex: 0, Start: 1, End: 2},
+				{PatternIndex: 0, Start: 2, End: 3},
+			},
+			wantContains: true,
+			wantCount:    3,
+		},
+		{
+			name:     "Duplicate patterns",
+			patterns: []string{"ab", "ab"},
+			text:     "ab",
+			wantMatches: []Match{
+				{PatternIndex: 0, Start: 0, End: 1},
+				{PatternIndex: 1, Start: 0, End: 1},
+			},
+			wantContains: true,
+			wantCount:    2,
+		},
+		{
+			name:         "No match",
+			patterns:     []string{"cat", "dog"},
+			text:         "mouse",
+			wantMatches:  nil,
+			wantContains: false,
+			wantCount:    0,
+		},
+		{
+			name:         "Text shorter than patterns",
+			patterns:     []string{"abcd"},
+			text:         "abc",
+			wantMatches:  nil,
+			wantContains: false,
+			wantCount:    0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rk, err := New(tc.patterns)
+			if err != nil {
+				t.Fatalf("New(%q) returned error: %v", tc.patterns, err)
+			}
+
+			gotMatches := rk.FindAll(tc.text)
+			if !reflect.DeepEqual(gotMatches, tc.wantMatches) {
+				t.Errorf("FindAll(%q) got %v, want %v", tc.text, gotMatches, tc.wantMatches)
+			}
+
+			gotBytes := rk.FindAllBytes([]byte(tc.text))
+			if !reflect.DeepEqual(gotBytes, tc.wantMatches) {
+				t.Errorf("FindAllBytes(%q) got %v, want %v", tc.text, gotBytes, tc.wantMatches)
+			}
+
+			if got := rk.Contains(tc.text); got != tc.wantContains {
+				t.Errorf("Contains(%q) got %v, want %v", tc.text, got, tc.wantContains)
+			}
+			if got := rk.ContainsBytes([]byte(tc.text)); got != tc.wantContains {
+				t.Errorf("ContainsBytes(%q) got %v, want %v", tc.text, got, tc.wantContains)
+			}
+
+			if got := rk.Count(tc.text); got != tc.wantCount {
+				t.Errorf("Count(%q) got %d, want %d", tc.text, got, tc.wantCount)
+			}
+			if got := rk.CountBytes([]byte(tc.text)); got != tc.wantCount {
+				t.Errorf("CountBytes(%q) got %d, want %d", tc.text, got, tc.wantCount)
+			}
+		})
+	}
+}
+
+func TestRabinKarpNewErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		wantErr  error
+	}{
+		{"No patterns", nil, ErrNoPatterns},
+		{"Empty pattern", []string{"", ""}, ErrEmptyPattern},
+		{"Length mismatch", []string{"abc", "ab"}, ErrLengthMismatch},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rk, err := New(tc.patterns)
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("New(%q) error = %v, want %v", tc.patterns, err, tc.wantErr)
+			}
+			if rk != nil {
+				t.Errorf("New(%q) returned a matcher along with an error", tc.patterns)
+			}
+		})
+	}
+}
+
+func TestRabinKarpCollisionIsVerified(t *testing.T) {
+	rk, err := New([]string{"abc", "xyz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Simulate a collision: make "xyz" share the hash bucket of "abc"
+	h := rk.hash([]byte("abc"))
+	rk.byHash[h] = append(rk.byHash[h], 1)
+
+	want := []Match{{PatternIndex: 0, Start: 0, End: 2}}
+	if got := rk.FindAll("abc"); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindAll got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,134 @@
+// Package searcher defines the interface shared by the string search algorithms in this module,
+// so code can be written generically over the algorithm and the matchers swapped freely.
+//
+// Every package follows the same policy for empty patterns: an empty pattern never matches,
+// in any text, rather than matching at every position. Single-pattern matchers built from an
+// empty pattern find nothing; multi-pattern matchers accept empty patterns but never report
+// them; rabinkarp, whose patterns must share one length, rejects them with an error. Packages
+// offering NewWithError report ErrEmptyPattern instead for callers who prefer to catch them.
+//
+// The ignoreCase flag of every package folds the bytes 'A'..'Z' to lowercase one byte at a time.
+// This is safe on UTF-8 text: every byte of a multi-byte sequence is at least 0x80, so only real
+// ASCII letters are ever folded and multi-byte characters are always compared exactly. It is not
+// safe on encodings such as Shift_JIS or GBK, whose multi-byte sequences may contain bytes in the
+// ASCII range; convert such text to UTF-8 first. Folding beyond ASCII is offered where it is
+// supported, e.g. boyermoore's UnicodeFold option and ahocorasick's Runes option.
+package searcher
+
+import (
+	"slices"
+
+	"github.com/notJoon/searcher/ahocorasick"
+	"github.com/notJoon/searcher/boyermoore"
+	"github.com/notJoon/searcher/byteclass"
+	"github.com/notJoon/searcher/commentzwalter"
+	"github.com/notJoon/searcher/kmp"
+	"github.com/notJoon/searcher/rabinkarp"
+	"github.com/notJoon/searcher/shiftor"
+	"github.com/notJoon/searcher/wildcard"
+	"github.com/notJoon/searcher/zalgo"
+)
+
+// Searcher is implemented by every matcher in this module, either directly
+// (single-pattern matchers) or through an adapter (multi-pattern matchers).
+type Searcher interface {
+	// FindAll returns the starting indices of the matches in the text, in ascending order.
+	FindAll(text string) []int
+	// Contains reports whether there is any match in the text.
+	Contains(text string) bool
+	// Count returns the number of matches in the text, i.e. len(FindAll(text)).
+	Count(text string) int
+}
+
+var (
+	_ Searcher = (*boyermoore.BoyerMoore)(nil)
+	_ Searcher = (*byteclass.ByteClass)(nil)
+	_ Searcher = (*kmp.KMP)(nil)
+	_ Searcher = (*shiftor.ShiftOr)(nil)
+	_ Searcher = (*wildcard.Wildcard)(nil)
+	_ Searcher = (*zalgo.ZAlgo)(nil)
+
+	_ Searcher = acSearcher{}
+	_ Searcher = cwSearcher{}
+	_ Searcher = rkSearcher{}
+)
+
+// FromAhoCorasick adapts an AhoCorasick automaton to the Searcher interface.
+// FindAll reports the starting index of every match of every pattern, so a position
+// where several patterns match appears once per pattern.
+func FromAhoCorasick(ac *ahocorasick.AhoCorasick) Searcher {
+	return acSearcher{ac: ac}
+}
+
+// acSearcher wraps an AhoCorasick automaton as a Searcher.
+type acSearcher struct {
+	ac *ahocorasick.AhoCorasick
+}
+
+func (s acSearcher) FindAll(text string) []int {
+	return sortedStarts(s.ac.FindAll(text), func(m ahocorasick.ACMatch) int { return m.Start })
+}
+
+func (s acSearcher) Contains(text string) bool {
+	return s.ac.Contains(text)
+}
+
+func (s acSearcher) Count(text string) int {
+	return s.ac.Count(text)
+}
+
+// FromCommentzWalter adapts a CommentzWalter matcher to the Searcher interface.
+// Like FromAhoCorasick, a position where several patterns match appears once per pattern.
+func FromCommentzWalter(cw *commentzwalter.CommentzWalter) Searcher {
+	return cwSearcher{cw: cw}
+}
+
+// cwSearcher wraps a CommentzWalter matcher as a Searcher.
+type cwSearcher struct {
+	cw *commentzwalter.CommentzWalter
+}
+
+func (s cwSearcher) FindAll(text string) []int {
+	return sortedStarts(s.cw.FindAll(text), func(m commentzwalter.Match) int { return m.Start })
+}
+
+func (s cwSearcher) Contains(text string) bool {
+	return s.cw.Contains(text)
+}
+
+func (s cwSearcher) Count(text string) int {
+	return s.cw.Count(text)
+}
+
+// FromRabinKarp adapts a RabinKarp matcher to the Searcher interface.
+// Like FromAhoCorasick, a position where several patterns match appears once per pattern.
+func FromRabinKarp(rk *rabinkarp.RabinKarp) Searcher {
+	return rkSearcher{rk: rk}
+}
+
+// rkSearcher wraps a RabinKarp matcher as a Searcher.
+type rkSearcher struct {
+	rk *rabinkarp.RabinKarp
+}
+
+func (s rkSearcher) FindAll(text string) []int {
+	return sortedStarts(s.rk.FindAll(text), func(m rabinkarp.Match) int { return m.Start })
+}
+
+func (s rkSearcher) Contains(text string) bool {
+	return s.rk.Contains(text)
+}
+
+func (s rkSearcher) Count(text string) int {
+	return s.rk.Count(text)
+}
+
+// sortedStarts returns the start of every match in ms, in ascending order.
+func sortedStarts[M any](ms []M, start func(M) int) []int {
+	starts := make([]int, len(ms))
+	for i, m := range ms {
+		starts[i] = start(m)
+	}
+	slices.Sort(starts)
+	return starts
+}
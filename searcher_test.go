@@ -0,0 +1,286 @@
+package searcher
+
+import (
+	"slices"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/notJoon/searcher/ahocorasick"
+	"github.com/notJoon/searcher/boyermoore"
+	"github.com/notJoon/searcher/byteclass"
+	"github.com/notJoon/searcher/commentzwalter"
+	"github.com/notJoon/searcher/fuzzy"
+	"github.com/notJoon/searcher/kmp"
+	"github.com/notJoon/searcher/rabinkarp"
+	"github.com/notJoon/searcher/shiftor"
+	"github.com/notJoon/searcher/wildcard"
+	"github.com/notJoon/searcher/zalgo"
+)
+
+func TestSearchers(t *testing.T) {
+	tests := []struct {
+		name         string
+		searcher     Searcher
+		text         string
+		wantAll      []int
+		wantContains bool
+		wantCount    int
+	}{
+		{
+			name:         "BoyerMoore",
+			searcher:     boyermoore.New("he", false),
+			text:         "ushers and hers",
+			wantAll:      []int{2, 11},
+			wantContains: true,
+			wantCount:    2,
+		},
+		{
+			name:         "KMP",
+			searcher:     kmp.New("he", false),
+			text:         "ushers and hers",
+			wantAll:      []int{2, 11},
+			wantContains: true,
+			wantCount:    2,
+		},
+		{
+			name:         "ShiftOr",
+			searcher:     mustShiftOr(t, "he", false),
+			text:         "ushers and hers",
+			wantAll:      []int{2, 11},
+			wantContains: true,
+			wantCount:    2,
+		},
+		{
+			name:         "ZAlgo",
+			searcher:     zalgo.New("he", false),
+			text:         "ushers and hers",
+			wantAll:      []int{2, 11},
+			wantContains: true,
+			wantCount:    2,
+		},
+		{
+			name:         "AhoCorasick",
+			searcher:     FromAhoCorasick(ahocorasick.New([]string{"he", "she", "hers"}, false)),
+			text:         "ushers",
+			wantAll:      []int{1, 2, 2},
+			wantContains: true,
+			wantCount:    3,
+		},
+		{
+			name:         "CommentzWalter",
+			searcher:     FromCommentzWalter(commentzwalter.New([]string{"he", "she", "hers"}, false)),
+			text:         "ushers",
+			wantAll:      []int{1, 2, 2},
+			wantContains: true,
+			wantCount:    3,
+		},
+		{
+			name:         "RabinKarp",
+			searcher:     mustRabinKarp(t, "she", "her"),
+			text:         "ushers",
+			wantAll:      []int{1, 2},
+			wantContains: true,
+			wantCount:    2,
+		},
+		{
+			name:         "Wildcard",
+			searcher:     wildcard.New("h?r*s", false),
+			text:         "ushers and hers",
+			wantAll:      []int{2, 11},
+			wantContains: true,
+			wantCount:    2,
+		},
+		{
+			name:         "BoyerMoore no match",
+			searcher:     boyermoore.New("cat", false),
+			text:         "mouse",
+			wantAll:      []int{},
+			wantContains: false,
+			wantCount:    0,
+		},
+		{
+			name:         "CommentzWalter no match",
+			searcher:     FromCommentzWalter(commentzwalter.New([]string{"cat", "dog"}, false)),
+			text:         "mouse",
+			wantAll:      []int{},
+			wantContains: false,
+			wantCount:    0,
+		},
+		{
+			name:         "RabinKarp no match",
+			searcher:     mustRabinKarp(t, "cat", "dog"),
+			text:         "mouse",
+			wantAll:      []int{},
+			wantContains: false,
+			wantCount:    0,
+		},
+		{
+			name:         "AhoCorasick no match",
+			searcher:     FromAhoCorasick(ahocorasick.New([]string{"cat", "dog"}, false)),
+			text:         "mouse",
+			wantAll:      []int{},
+			wantContains: false,
+			wantCount:    0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			gotAll := tc.searcher.FindAll(tc.text)
+			if !slices.Equal(gotAll, tc.wantAll) {
+				t.Errorf("FindAll(%q) = %v; want %v", tc.text, gotAll, tc.wantAll)
+			}
+
+			gotContains := tc.searcher.Contains(tc.text)
+			if gotContains != tc.wantContains {
+				t.Errorf("Contains(%q) = %v; want %v", tc.text, gotContains, tc.wantContains)
+			}
+
+			gotCount := tc.searcher.Count(tc.text)
+			if gotCount != tc.wantCount {
+				t.Errorf("Count(%q) = %d; want %d", tc.text, gotCount, tc.wantCount)
+			}
+		})
+	}
+}
+
+func TestEmptyPatternNeverMatches(t *testing.T) {
+	searchers := []struct {
+		name     string
+		searcher Searcher
+	}{
+		{"BoyerMoore", boyermoore.New("", false)},
+		{"BoyerMoore ignore case", boyermoore.New("", true)},
+		{"ByteClass", byteclass.New(nil)},
+		{"KMP", kmp.New("", false)},
+		{"ShiftOr", mustShiftOr(t, "", false)},
+		{"ZAlgo", zalgo.New("", false)},
+		{"Wildcard", wildcard.New("", false)},
+		{"Wildcard star", wildcard.New("*", false)},
+		{"AhoCorasick", FromAhoCorasick(ahocorasick.New([]string{""}, false))},
+		{"AhoCorasick leftmost", FromAhoCorasick(ahocorasick.NewWithOptions([]string{""}, ahocorasick.Options{MatchKind: ahocorasick.LeftmostFirst}))},
+		{"CommentzWalter", FromCommentzWalter(commentzwalter.New([]string{""}, false))},
+	}
+	texts := []string{"", "a", "abc"}
+
+	for _, tc := range searchers {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, text := range texts {
+				if got := tc.searcher.FindAll(text); len(got) != 0 {
+					t.Errorf("FindAll(%q) = %v; want no match", text, got)
+				}
+				if tc.searcher.Contains(text) {
+					t.Errorf("Contains(%q) = true; want false", text)
+				}
+				if got := tc.searcher.Count(text); got != 0 {
+					t.Errorf("Count(%q) = %d; want 0", text, got)
+				}
+			}
+		})
+	}
+
+	// An empty pattern among others is skipped, and matchers outside the Searcher
+	// interface follow the same policy
+	t.Run("CommentzWalter", func(t *testing.T) {
+		cw := commentzwalter.New([]string{"", "b"}, false)
+		for _, text := range texts {
+			for _, m := range cw.FindAll(text) {
+				if m.PatternIndex == 0 {
+					t.Errorf("FindAll(%q) reported the empty pattern: %v", text, m)
+				}
+			}
+		}
+	})
+	t.Run("Fuzzy", func(t *testing.T) {
+		f := fuzzy.New("", false)
+		for _, text := range texts {
+			if got := f.FindAll(text, 2); len(got) != 0 {
+				t.Errorf("FindAll(%q, 2) = %v; want no match", text, got)
+			}
+		}
+	})
+	t.Run("RabinKarp", func(t *testing.T) {
+		if _, err := rabinkarp.New([]string{""}); err == nil {
+			t.Error("New with an empty pattern succeeded; want an error")
+		}
+	})
+}
+
+// TestIgnoreCaseMultiByte checks that ASCII case folding leaves multi-byte UTF-8
+// characters alone: "Á" (C3 81) and "á" (C3 A1) differ by 0x20 in their last byte,
+// yet neither byte is an ASCII letter, so they must stay distinct.
+func TestIgnoreCaseMultiByte(t *testing.T) {
+	const pattern, text = "ÁB", "áb ÁB Áb ÁBá"
+	want := []int{4, 8, 12}
+
+	searchers := []struct {
+		name     string
+		searcher Searcher
+	}{
+		{"BoyerMoore", boyermoore.New(pattern, true)},
+		{"KMP", kmp.New(pattern, true)},
+		{"ShiftOr", mustShiftOr(t, pattern, true)},
+		{"ZAlgo", zalgo.New(pattern, true)},
+		{"Wildcard", wildcard.New(pattern, true)},
+		{"AhoCorasick", FromAhoCorasick(ahocorasick.New([]string{pattern}, true))},
+	}
+	for _, tc := range searchers {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.searcher.FindAll(text); !slices.Equal(got, want) {
+				t.Errorf("FindAll(%q) = %v; want %v", text, got, want)
+			}
+		})
+	}
+	t.Run("CommentzWalter", func(t *testing.T) {
+		var got []int
+		for _, m := range commentzwalter.New([]string{pattern}, true).FindAll(text) {
+			got = append(got, m.Start)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("FindAll(%q) = %v; want %v", text, got, want)
+		}
+	})
+	t.Run("Fuzzy", func(t *testing.T) {
+		var got []int
+		for _, m := range fuzzy.New(pattern, true).FindAll(text, 0) {
+			got = append(got, m.Start)
+		}
+		if !slices.Equal(got, want) {
+			t.Errorf("FindAll(%q, 0) = %v; want %v", text, got, want)
+		}
+	})
+
+	// The property the ASCII folding relies on: every byte of a multi-byte
+	// UTF-8 sequence is at least 0x80, so none of them is in 'A'..'Z'
+	var buf [utf8.UTFMax]byte
+	for r := rune(utf8.RuneSelf); r <= unicode.MaxRune; r++ {
+		if !utf8.ValidRune(r) {
+			continue
+		}
+		for _, c := range buf[:utf8.EncodeRune(buf[:], r)] {
+			if c < utf8.RuneSelf {
+				t.Fatalf("encoding of %U contains byte %#x", r, c)
+			}
+		}
+	}
+}
+
+// mustShiftOr returns a ShiftOr matcher for pattern, failing the test on error
+func mustShiftOr(t *testing.T, pattern string, ignoreCase bool) *shiftor.ShiftOr {
+	t.Helper()
+	so, err := shiftor.New(pattern, ignoreCase)
+	if err != nil {
+		t.Fatalf("shiftor.New(%q) error: %v", pattern, err)
+	}
+	return so
+}
+
+func mustRabinKarp(t *testing.T, patterns ...string) Searcher {
+	t.Helper()
+	rk, err := rabinkarp.New(patterns)
+	if err != nil {
+		t.Fatalf("rabinkarp.New(%q) error: %v", patterns, err)
+	}
+	return FromRabinKarp(rk)
+}
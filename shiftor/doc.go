@@ -0,0 +1,3 @@
+// Package shiftor implements the bit-parallel Shift-Or (bitap) string search
+// algorithm for patterns of up to 64 bytes.
+package shiftor
@@ -0,0 +1,146 @@
+package shiftor
+
+import "errors"
+
+// MaxPatternLen is the longest pattern a ShiftOr matcher accepts: one state bit per
+// pattern byte, held in a single machine word.
+const MaxPatternLen = 64
+
+// ErrPatternTooLong is returned by New for a pattern longer than MaxPatternLen bytes.
+var ErrPatternTooLong = errors.New("shiftor: pattern longer than 64 bytes")
+
+// ShiftOr represents a pattern matcher using the Shift-Or algorithm.
+// The state is a bit vector whose bit i is 0 when the last i+1 text bytes equal the
+// first i+1 pattern bytes. Each text byte shifts the state left by one and ORs in the
+// byte's mask, which is 0 exactly at the pattern positions holding that byte, so a match
+// ends wherever bit len(pattern)-1 is 0. The scan costs two word operations and a test
+// per text byte, with no branches depending on partial matches.
+type ShiftOr struct {
+	pat        []byte      // pattern (converted to lowercase if ignoreCase is true)
+	ignoreCase bool        // case insensitivity flag
+	masks      [256]uint64 // masks[c] has bit i cleared iff byte c matches pat[i]
+}
+
+// New creates a new ShiftOr matcher for the given pattern.
+// If ignoreCase is true, the search will be case-insensitive (ASCII letters only).
+// An empty pattern gives a matcher that never matches.
+// Returns ErrPatternTooLong if the pattern is longer than MaxPatternLen bytes;
+// boyermoore handles longer patterns.
+func New(pattern string, ignoreCase bool) (*ShiftOr, error) {
+	if len(pattern) > MaxPatternLen {
+		return nil, ErrPatternTooLong
+	}
+	p := []byte(pattern)
+
+	// Convert pattern to lowercase if case-insensitive search is requested
+	if ignoreCase {
+		for i := 0; i < len(p); i++ {
+			c := p[i]
+			// Consider only ASCII range ('A'~'Z')
+			if c >= 'A' && c <= 'Z' {
+				p[i] = c + ('a' - 'A')
+			}
+		}
+	}
+
+	so := &ShiftOr{
+		pat:        p,
+		ignoreCase: ignoreCase,
+	}
+	so.buildMasks()
+
+	return so, nil
+}
+
+// FindAll returns the starting indices of all non-overlapping matches of the pattern in the text.
+// Returns an empty slice if no matches are found.
+func (so *ShiftOr) FindAll(txt string) []int {
+	return so._findAll([]byte(txt), 0)
+}
+
+// FindAllBytes returns the starting indices of all non-overlapping matches of the pattern in the byte slice.
+// Returns an empty slice if no matches are found.
+func (so *ShiftOr) FindAllBytes(data []byte) []int {
+	return so._findAll(data, 0)
+}
+
+// FindFirst returns the index of the first occurrence of the pattern in the text.
+// Returns -1 if the pattern is not found.
+func (so *ShiftOr) FindFirst(txt string) int {
+	return so._findFirst([]byte(txt))
+}
+
+// FindFirstBytes returns the index of the first occurrence of the pattern in the byte slice.
+// Returns -1 if the pattern is not found.
+func (so *ShiftOr) FindFirstBytes(data []byte) int {
+	return so._findFirst(data)
+}
+
+// Contains reports whether the pattern appears in the text.
+func (so *ShiftOr) Contains(txt string) bool {
+	return so.FindFirst(txt) != -1
+}
+
+// ContainsBytes reports whether the pattern appears in the byte slice.
+func (so *ShiftOr) ContainsBytes(data []byte) bool {
+	return so.FindFirstBytes(data) != -1
+}
+
+// Count returns the number of non-overlapping occurrences of the pattern in the text.
+func (so *ShiftOr) Count(txt string) int {
+	return len(so.FindAll(txt))
+}
+
+// CountBytes returns the number of non-overlapping occurrences of the pattern in the byte slice.
+func (so *ShiftOr) CountBytes(data []byte) int {
+	return len(so.FindAllBytes(data))
+}
+
+// _findFirst returns the index of the first match, or -1 if there is none.
+func (so *ShiftOr) _findFirst(data []byte) int {
+	res := so._findAll(data, 1)
+	if len(res) > 0 {
+		return res[0]
+	}
+	return -1
+}
+
+// _findAll runs the Shift-Or state over the text and records a match wherever the
+// pattern's last bit is 0. Resetting the state to all ones after a match forgets every
+// partial match that began inside it, which keeps the matches non-overlapping.
+// If limit is positive, the search stops once limit matches have been found.
+func (so *ShiftOr) _findAll(data []byte, limit int) []int {
+	var results []int
+	m := len(so.pat)
+	if m == 0 || len(data) < m {
+		return results
+	}
+
+	hit := uint64(1) << (m - 1)
+	state := ^uint64(0)
+	for i, c := range data {
+		state = state<<1 | so.masks[c]
+		if state&hit == 0 {
+			results = append(results, i-m+1)
+			if len(results) == limit {
+				break
+			}
+			state = ^uint64(0)
+		}
+	}
+	return results
+}
+
+// buildMasks computes the byte masks of the pattern. Under ignoreCase both
+// cases of a letter share the lowercase pattern's bits.
+func (so *ShiftOr) buildMasks() {
+	for c := range so.masks {
+		so.masks[c] = ^uint64(0)
+	}
+	for i, c := range so.pat {
+		so.masks[c] &^= 1 << i
+		if so.ignoreCase && c >= 'a' && c <= 'z' {
+			so.masks[c-('a'-'A')] &^= 1 << i
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package shiftor
+
+import (
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/notJoon/searcher/boyermoore"
+)
+
+func TestStringSearch(t *testing.T) {
+	tests := []struct {
+		name         string
+		pattern      string
+		text         string
+		ignoreCase   bool
+		wantAll      []int
+		wantFirst    int
+		wantContains bool
+		wantCount    int
+	}{
+		{"Basic match", "ABC", "ZZZABCZZZ", false, []int{3}, 3, true, 1},
+		{"No match", "ABC", "ZZZABZ", false, nil, -1, false, 0},
+		{"Multiple matches", "AB", "ABABAB", false, []int{0, 2, 4}, 0, true, 3},
+		{"Non-overlapping", "aa", "aaaaa", false, []int{0, 2}, 0, true, 2},
+		{"Single byte", "a", "banana", false, []int{1, 3, 5}, 1, true, 3},
+		{"Ignore case", "AbC", "zzZabcZZZAbCZZabcdZZ", true, []int{3, 9, 14}, 3, true, 3},
+		{"Ignore case leaves other bytes exact", "a-1", "A-1 a_1", true, []int{0}, 0, true, 1},
+		{"Match at the end", "xyz", "abcxyz", false, []int{3}, 3, true, 1},
+		{"Longest pattern", strings.Repeat("ab", 32), "x" + strings.Repeat("ab", 33), false, []int{1}, 1, true, 1},
+		{"Empty pattern", "", "ABC", false, nil, -1, false, 0},
+		{"Pattern longer than text", "ABCDEFG", "ABC", false, nil, -1, false, 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			so, err := New(tc.pattern, tc.ignoreCase)
+			if err != nil {
+				t.Fatalf("New(%q) error: %v", tc.pattern, err)
+			}
+
+			if got := so.FindAll(tc.text); !slices.Equal(got, tc.wantAll) {
+				t.Errorf("FindAll(%q) = %v; want %v", tc.text, got, tc.wantAll)
+			}
+			if got := so.FindAllBytes([]byte(tc.text)); !slices.Equal(got, tc.wantAll) {
+				t.Errorf("FindAllBytes(%q) = %v; want %v", tc.text, got, tc.wantAll)
+			}
+			if got := so.FindFirst(tc.text); got != tc.wantFirst {
+				t.Errorf("FindFirst(%q) = %d; want %d", tc.text, got, tc.wantFirst)
+			}
+			if got := so.FindFirstBytes([]byte(tc.text)); got != tc.wantFirst {
+				t.Errorf("FindFirstBytes(%q) = %d; want %d", tc.text, got, tc.wantFirst)
+			}
+			if got := so.Contains(tc.text); got != tc.wantContains {
+				t.Errorf("Contains(%q) = %v; want %v", tc.text, got, tc.wantContains)
+			}
+			if got := so.ContainsBytes([]byte(tc.text)); got != tc.wantContains {
+				t.Errorf("ContainsBytes(%q) = %v; want %v", tc.text, got, tc.wantContains)
+			}
+			if got := so.Count(tc.text); got != tc.wantCount {
+				t.Errorf("Count(%q) = %d; want %d", tc.text, got, tc.wantCount)
+			}
+			if got := so.CountBytes([]byte(tc.text)); got != tc.wantCount {
+				t.Errorf("CountBytes(%q) = %d; want %d", tc.text, got, tc.wantCount)
+			}
+		})
+	}
+}
+
+func TestPatternTooLong(t *testing.T) {
+	so, err := New(strings.Repeat("a", MaxPatternLen+1), false)
+	if !errors.Is(err, ErrPatternTooLong) {
+		t.Errorf("New error = %v; want %v", err, ErrPatternTooLong)
+	}
+	if so != nil {
+		t.Errorf("New returned a matcher along with the error")
+	}
+}
+
+// TestDifferential checks Shift-Or against Boyer-Moore on random inputs.
+// Small alphabets make repeated and overlapping matches common.
+func TestDifferential(t *testing.T) {
+	rng := rand.New(rand.NewPCG(9, 10))
+	randString := func(alphabet string, n int) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = alphabet[rng.IntN(len(alphabet))]
+		}
+		return string(b)
+	}
+
+	for iter := 0; iter < 5000; iter++ {
+		alphabet := "abAB"[:1+rng.IntN(4)]
+		pattern := randString(alphabet, rng.IntN(MaxPatternLen/4))
+		text := randString(alphabet, rng.IntN(200))
+		ignoreCase := rng.IntN(2) == 0
+
+		so, err := New(pattern, ignoreCase)
+		if err != nil {
+			t.Fatalf("New(%q) error: %v", pattern, err)
+		}
+		want := boyermoore.New(pattern, ignoreCase).FindAll(text)
+		if got := so.FindAll(text); !slices.Equal(got, want) {
+			t.Fatalf("FindAll(%q) with pattern %q, ignoreCase=%v = %v; Boyer-Moore gives %v",
+				text, pattern, ignoreCase, got, want)
+		}
+	}
+}
@@ -0,0 +1,3 @@
+// Package wildcard implements glob-like pattern search, where '?' matches any
+// single byte and '*' matches any run of bytes.
+package wildcard
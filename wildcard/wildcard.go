@@ -0,0 +1,203 @@
+package wildcard
+
+// Wildcard represents a glob-like pattern matcher.
+// In the pattern '?' matches any single byte and '*' matches any run of bytes,
+// including an empty one. A backslash escapes the next byte, so `\*`, `\?` and `\\`
+// match a literal '*', '?' and '\'; a trailing lone backslash matches itself.
+//
+// The pattern is split at each '*' into segments of literal bytes and '?'.
+// Each segment is located with a Horspool skip loop that treats '?' as matching
+// every byte, and the segments are placed left to right at their earliest
+// possible positions, which finds the shortest match for a given start without
+// backtracking.
+type Wildcard struct {
+	segs       []segment // pattern pieces between '*', empty pieces dropped
+	ignoreCase bool      // case insensitivity flag
+}
+
+// segment is a run of pattern bytes without '*'.
+type segment struct {
+	pat   []byte   // segment bytes (converted to lowercase if ignoreCase is true)
+	any   []bool   // any[i] is true if pat[i] is an unescaped '?'
+	shift [256]int // Horspool shift for the text byte aligned with the last segment byte
+}
+
+// Match represents the span of a pattern match found in text.
+type Match struct {
+	Start int // start index of the match
+	End   int // end index of the match (inclusive)
+}
+
+// New creates a new Wildcard matcher for the given pattern.
+// If ignoreCase is true, ASCII letters are compared case-insensitively.
+// A pattern without any literal byte or '?' (e.g. "" or "*") never matches.
+func New(pattern string, ignoreCase bool) *Wildcard {
+	w := &Wildcard{ignoreCase: ignoreCase}
+
+	var seg segment
+	flush := func() {
+		if len(seg.pat) > 0 {
+			seg.buildShift()
+			w.segs = append(w.segs, seg)
+		}
+		seg = segment{}
+	}
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		switch {
+		case c == '\\' && i+1 < len(pattern):
+			i++
+			seg.pat = append(seg.pat, w.normChar(pattern[i]))
+			seg.any = append(seg.any, false)
+		case c == '*':
+			flush()
+		case c == '?':
+			seg.pat = append(seg.pat, '?')
+			seg.any = append(seg.any, true)
+		default:
+			seg.pat = append(seg.pat, w.normChar(c))
+			seg.any = append(seg.any, false)
+		}
+	}
+	flush()
+
+	return w
+}
+
+// FindAll returns the starting indices of all non-overlapping matches of the pattern in the text.
+// Matches are leftmost and as short as possible.
+// Returns an empty slice if no matches are found.
+func (w *Wildcard) FindAll(txt string) []int {
+	ms := w._findAll([]byte(txt), 0)
+	starts := make([]int, len(ms))
+	for i, m := range ms {
+		starts[i] = m.Start
+	}
+	return starts
+}
+
+// FindAllBytes returns the starting indices of all non-overlapping matches of the pattern in the byte slice.
+// Returns an empty slice if no matches are found.
+func (w *Wildcard) FindAllBytes(data []byte) []int {
+	ms := w._findAll(data, 0)
+	starts := make([]int, len(ms))
+	for i, m := range ms {
+		starts[i] = m.Start
+	}
+	return starts
+}
+
+// FindAllMatches returns the span of every non-overlapping match of the pattern in the text.
+// Matches are leftmost and as short as possible, so a leading or trailing '*' adds nothing to them.
+// Returns an empty slice if no matches are found.
+func (w *Wildcard) FindAllMatches(txt string) []Match {
+	return w._findAll([]byte(txt), 0)
+}
+
+// FindAllMatchesBytes returns the span of every non-overlapping match of the pattern in the byte slice.
+// Returns an empty slice if no matches are found.
+func (w *Wildcard) FindAllMatchesBytes(data []byte) []Match {
+	return w._findAll(data, 0)
+}
+
+// Contains reports whether the pattern matches anywhere in the text.
+func (w *Wildcard) Contains(txt string) bool {
+	return len(w._findAll([]byte(txt), 1)) > 0
+}
+
+// ContainsBytes reports whether the pattern matches anywhere in the byte slice.
+func (w *Wildcard) ContainsBytes(data []byte) bool {
+	return len(w._findAll(data, 1)) > 0
+}
+
+// Count returns the number of non-overlapping matches of the pattern in the text.
+func (w *Wildcard) Count(txt string) int {
+	return len(w._findAll([]byte(txt), 0))
+}
+
+// CountBytes returns the number of non-overlapping matches of the pattern in the byte slice.
+func (w *Wildcard) CountBytes(data []byte) int {
+	return len(w._findAll(data, 0))
+}
+
+// _findAll returns the non-overlapping leftmost-shortest matches in data.
+// If limit is positive, the search stops once limit matches have been found.
+//
+// For a start where the first segment matches, placing every later segment at its
+// earliest occurrence after the previous one is the shortest completion. If that
+// fails, no later start can succeed either, since it only leaves less text for the
+// same segments, so the search ends there.
+func (w *Wildcard) _findAll(data []byte, limit int) []Match {
+	results := []Match{}
+	if len(w.segs) == 0 {
+		return results
+	}
+
+	pos := 0
+	for {
+		s := w.segs[0].find(data, pos, w)
+		if s < 0 {
+			return results
+		}
+		end := s + len(w.segs[0].pat)
+		for i := 1; i < len(w.segs) && end >= 0; i++ {
+			if p := w.segs[i].find(data, end, w); p >= 0 {
+				end = p + len(w.segs[i].pat)
+			} else {
+				end = -1
+			}
+		}
+		if end < 0 {
+			return results
+		}
+		results = append(results, Match{Start: s, End: end - 1})
+		if len(results) == limit {
+			return results
+		}
+		pos = end
+	}
+}
+
+// find returns the first index at or after from where the segment matches data, or -1.
+func (seg *segment) find(data []byte, from int, w *Wildcard) int {
+	m := len(seg.pat)
+	for s := from; s <= len(data)-m; s += seg.shift[w.normChar(data[s+m-1])] {
+		j := m - 1
+		for j >= 0 && (seg.any[j] || seg.pat[j] == w.normChar(data[s+j])) {
+			j--
+		}
+		if j < 0 {
+			return s
+		}
+	}
+	return -1
+}
+
+// buildShift constructs the Horspool shift table of the segment.
+// A '?' matches every byte, so no shift may move past the last '?' before the final position.
+func (seg *segment) buildShift() {
+	m := len(seg.pat)
+	def := m
+	for i := 0; i < m-1; i++ {
+		if seg.any[i] {
+			def = m - 1 - i
+		}
+	}
+	for c := range seg.shift {
+		seg.shift[c] = def
+	}
+	for i := 0; i < m-1; i++ {
+		if !seg.any[i] && m-1-i < seg.shift[seg.pat[i]] {
+			seg.shift[seg.pat[i]] = m - 1 - i
+		}
+	}
+}
+
+// normChar normalizes a byte for case-insensitive comparison.
+// If ignoreCase is true, converts ASCII uppercase letters to lowercase.
+func (w *Wildcard) normChar(c byte) byte {
+	if w.ignoreCase && c >= 'A' && c <= 'Z' {
+		return c + ('a' - 'A')
+	}
+	return c
+}
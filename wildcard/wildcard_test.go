@@ -0,0 +1,147 @@
+package wildcard
+
+import (
+	"math/rand/v2"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestFindAll(t *testing.T) {
+	tests := []struct {
+		name        string
+		pattern     string
+		text        string
+		ignoreCase  bool
+		wantMatches []Match
+	}{
+		{
+			name:        "Literal",
+			pattern:     "abc",
+			text:        "zabczabc",
+			wantMatches: []Match{{Start: 1, End: 3}, {Start: 5, End: 7}},
+		},
+		{
+			name:        "Question mark",
+			pattern:     "a?c",
+			text:        "abc axc ac",
+			wantMatches: []Match{{Start: 0, End: 2}, {Start: 4, End: 6}},
+		},
+		{
+			name:        "Star is shortest",
+			pattern:     "a*c",
+			text:        "abcbc ac",
+			wantMatches: []Match{{Start: 0, End: 2}, {Start: 6, End: 7}},
+		},
+		{
+			name:        "Star matches empty",
+			pattern:     "ab*cd",
+			text:        "abcd",
+			wantMatches: []Match{{Start: 0, End: 3}},
+		},
+		{
+			name:        "Leading and trailing stars",
+			pattern:     "*b?*",
+			text:        "abcabd",
+			wantMatches: []Match{{Start: 1, End: 2}, {Start: 4, End: 5}},
+		},
+		{
+			name:        "Several stars",
+			pattern:     "GET */*?HTTP",
+			text:        "GET /index.html HTTP/1.1",
+			wantMatches: []Match{{Start: 0, End: 19}},
+		},
+		{
+			name:        "Escaped wildcards",
+			pattern:     `a\*b\?`,
+			text:        "axb? a*b? a*bx",
+			wantMatches: []Match{{Start: 5, End: 8}},
+		},
+		{
+			name:        "Escaped backslash",
+			pattern:     `a\\b`,
+			text:        `a\b`,
+			wantMatches: []Match{{Start: 0, End: 2}},
+		},
+		{
+			name:        "Ignore case",
+			pattern:     "h?LLO",
+			text:        "HELLO hallo",
+			ignoreCase:  true,
+			wantMatches: []Match{{Start: 0, End: 4}, {Start: 6, End: 10}},
+		},
+		{
+			name:        "Missing later segment",
+			pattern:     "a*z",
+			text:        "abcabc",
+			wantMatches: []Match{},
+		},
+		{
+			name:        "Only stars",
+			pattern:     "**",
+			text:        "abc",
+			wantMatches: []Match{},
+		},
+		{
+			name:        "Empty pattern",
+			pattern:     "",
+			text:        "abc",
+			wantMatches: []Match{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := New(tc.pattern, tc.ignoreCase)
+			if got := w.FindAllMatches(tc.text); !reflect.DeepEqual(got, tc.wantMatches) {
+				t.Errorf("FindAllMatches(%q) = %v; want %v", tc.text, got, tc.wantMatches)
+			}
+			if got := w.FindAllMatchesBytes([]byte(tc.text)); !reflect.DeepEqual(got, tc.wantMatches) {
+				t.Errorf("FindAllMatchesBytes(%q) = %v; want %v", tc.text, got, tc.wantMatches)
+			}
+
+			wantAll := make([]int, len(tc.wantMatches))
+			for i, m := range tc.wantMatches {
+				wantAll[i] = m.Start
+			}
+			if got := w.FindAll(tc.text); !reflect.DeepEqual(got, wantAll) {
+				t.Errorf("FindAll(%q) = %v; want %v", tc.text, got, wantAll)
+			}
+			if got := w.Count(tc.text); got != len(wantAll) {
+				t.Errorf("Count(%q) = %d; want %d", tc.text, got, len(wantAll))
+			}
+			if got := w.Contains(tc.text); got != (len(wantAll) > 0) {
+				t.Errorf("Contains(%q) = %v; want %v", tc.text, got, len(wantAll) > 0)
+			}
+		})
+	}
+}
+
+func TestFindAllRandom(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	for i := 0; i < 2000; i++ {
+		var pattern strings.Builder
+		for j := 0; j < 1+rng.IntN(6); j++ {
+			pattern.WriteByte("ab?*"[rng.IntN(4)])
+		}
+		b := make([]byte, rng.IntN(20))
+		for j := range b {
+			b[j] = "abc"[rng.IntN(3)]
+		}
+		text := string(b)
+
+		// Leading and trailing stars match empty under shortest semantics
+		core := strings.Trim(pattern.String(), "*")
+		want := []Match{}
+		if core != "" {
+			expr := strings.NewReplacer("?", ".", "*", ".*?").Replace(core)
+			for _, loc := range regexp.MustCompile("(?s)"+expr).FindAllStringIndex(text, -1) {
+				want = append(want, Match{Start: loc[0], End: loc[1] - 1})
+			}
+		}
+		if got := New(pattern.String(), false).FindAllMatches(text); !reflect.DeepEqual(got, want) {
+			t.Fatalf("FindAllMatches(%q) for %q = %v; want %v", text, pattern.String(), got, want)
+		}
+	}
+}
@@ -0,0 +1,2 @@
+// Package zalgo implements string search with the Z-algorithm.
+package zalgo
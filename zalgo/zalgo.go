@@ -0,0 +1,165 @@
+package zalgo
+
+// ZAlgo represents a pattern matcher using the Z-algorithm.
+// The Z-function of a string gives, for every position, the length of the longest
+// substring starting there that is also a prefix of the string. Over
+// pattern + sep + text, where sep matches nothing, the entries for the text that
+// equal len(pattern) are exactly the matches. The concatenation is never built:
+// the Z-function of the pattern is computed once, and the text is scanned with
+// the same window reuse, in linear time overall.
+type ZAlgo struct {
+	pat        []byte // pattern (converted to lowercase if ignoreCase is true)
+	ignoreCase bool   // case insensitivity flag
+	z          []int  // z[i] is the length of the longest common prefix of pat and pat[i:]; z[0] is len(pat)
+}
+
+// New creates a new ZAlgo matcher for the given pattern.
+// If ignoreCase is true, the search will be case-insensitive.
+// An empty pattern gives a matcher that never matches.
+func New(pattern string, ignoreCase bool) *ZAlgo {
+	p := []byte(pattern)
+
+	// Convert pattern to lowercase if case-insensitive search is requested
+	if ignoreCase {
+		for i := 0; i < len(p); i++ {
+			c := p[i]
+			// Consider only ASCII range ('A'~'Z')
+			if c >= 'A' && c <= 'Z' {
+				p[i] = c + ('a' - 'A')
+			}
+		}
+	}
+
+	za := &ZAlgo{
+		pat:        p,
+		ignoreCase: ignoreCase,
+	}
+	za.buildZ()
+
+	return za
+}
+
+// FindAll returns the starting indices of all non-overlapping matches of the pattern in the text.
+// Returns an empty slice if no matches are found.
+func (za *ZAlgo) FindAll(txt string) []int {
+	return za._findAll([]byte(txt), 0)
+}
+
+// FindAllBytes returns the starting indices of all non-overlapping matches of the pattern in the byte slice.
+// Returns an empty slice if no matches are found.
+func (za *ZAlgo) FindAllBytes(data []byte) []int {
+	return za._findAll(data, 0)
+}
+
+// FindFirst returns the index of the first occurrence of the pattern in the text.
+// Returns -1 if the pattern is not found.
+func (za *ZAlgo) FindFirst(txt string) int {
+	return za._findFirst([]byte(txt))
+}
+
+// FindFirstBytes returns the index of the first occurrence of the pattern in the byte slice.
+// Returns -1 if the pattern is not found.
+func (za *ZAlgo) FindFirstBytes(data []byte) int {
+	return za._findFirst(data)
+}
+
+// Contains reports whether the pattern appears in the text.
+func (za *ZAlgo) Contains(txt string) bool {
+	return za.FindFirst(txt) != -1
+}
+
+// ContainsBytes reports whether the pattern appears in the byte slice.
+func (za *ZAlgo) ContainsBytes(data []byte) bool {
+	return za.FindFirstBytes(data) != -1
+}
+
+// Count returns the number of non-overlapping occurrences of the pattern in the text.
+func (za *ZAlgo) Count(txt string) int {
+	return len(za.FindAll(txt))
+}
+
+// CountBytes returns the number of non-overlapping occurrences of the pattern in the byte slice.
+func (za *ZAlgo) CountBytes(data []byte) int {
+	return len(za.FindAllBytes(data))
+}
+
+// _findFirst returns the index of the first match, or -1 if there is none.
+func (za *ZAlgo) _findFirst(data []byte) int {
+	res := za._findAll(data, 1)
+	if len(res) > 0 {
+		return res[0]
+	}
+	return -1
+}
+
+// _findAll computes the Z-values of the text against the pattern and keeps the positions
+// where the whole pattern matches, skipping those that overlap the previous kept match.
+// [l, r) is the rightmost window known to equal a prefix of the pattern; inside it a
+// Z-value is read off the pattern's own Z-function instead of being compared again.
+// If limit is positive, the search stops once limit matches have been found.
+func (za *ZAlgo) _findAll(data []byte, limit int) []int {
+	var results []int
+	m := len(za.pat)
+	if m == 0 || len(data) < m {
+		return results
+	}
+
+	l, r := 0, 0
+	next := 0 // first index a match may start at without overlapping the previous one
+	for i := 0; i <= len(data)-m; i++ {
+		k := 0 // Z-value at i
+		if i < r {
+			k = min(za.z[i-l], r-i)
+		}
+		if i+k >= r {
+			// The window gives no upper bound here: extend by direct comparison
+			for k < m && i+k < len(data) && za.pat[k] == za.normChar(data[i+k]) {
+				k++
+			}
+			if i+k > r {
+				l, r = i, i+k
+			}
+		}
+		if k == m && i >= next {
+			results = append(results, i)
+			if len(results) == limit {
+				break
+			}
+			next = i + m
+		}
+	}
+	return results
+}
+
+// normChar normalizes a byte for case-insensitive comparison.
+// If ignoreCase is true, converts ASCII uppercase letters to lowercase.
+func (za *ZAlgo) normChar(c byte) byte {
+	if za.ignoreCase && c >= 'A' && c <= 'Z' {
+		return c + ('a' - 'A')
+	}
+	return c
+}
+
+// buildZ constructs the Z-function of the pattern.
+func (za *ZAlgo) buildZ() {
+	m := len(za.pat)
+	za.z = make([]int, m)
+	if m == 0 {
+		return
+	}
+	za.z[0] = m
+	l, r := 0, 0
+	for i := 1; i < m; i++ {
+		k := 0
+		if i < r {
+			k = min(za.z[i-l], r-i)
+		}
+		for i+k < m && za.pat[k] == za.pat[i+k] {
+			k++
+		}
+		if i+k > r {
+			l, r = i, i+k
+		}
+		za.z[i] = k
+	}
+}
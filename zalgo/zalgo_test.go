@@ -0,0 +1,138 @@
+package zalgo
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/notJoon/searcher/boyermoore"
+	"github.com/notJoon/searcher/kmp"
+)
+
+func TestStringSearch(t *testing.T) {
+	tests := []struct {
+		name         string
+		pattern      string
+		text         string
+		ignoreCase   bool
+		wantAll      []int
+		wantFirst    int
+		wantContains bool
+		wantCount    int
+	}{
+		{"Basic match", "ABC", "ZZZABCZZZ", false, []int{3}, 3, true, 1},
+		{"No match", "ABC", "ZZZABZ", false, []int{}, -1, false, 0},
+		{"Multiple matches", "AB", "ABABAB", false, []int{0, 2, 4}, 0, true, 3},
+		{"Ignore case", "AbC", "zzZabcZZZAbCZZabcdZZ", true, []int{3, 9, 14}, 3, true, 3},
+		{"Match at the end", "xyz", "abcxyz", false, []int{3}, 3, true, 1},
+		{"Empty pattern", "", "ABC", false, []int{}, -1, false, 0},
+		{"Pattern longer than text", "ABCDEFG", "ABC", false, []int{}, -1, false, 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			za := New(tc.pattern, tc.ignoreCase)
+
+			gotAll := za.FindAll(tc.text)
+			if !equalIntSlices(gotAll, tc.wantAll) {
+				t.Errorf("FindAll(%q) = %v; want %v", tc.text, gotAll, tc.wantAll)
+			}
+			gotAllBytes := za.FindAllBytes([]byte(tc.text))
+			if !equalIntSlices(gotAllBytes, tc.wantAll) {
+				t.Errorf("FindAllBytes(%q) = %v; want %v", tc.text, gotAllBytes, tc.wantAll)
+			}
+
+			gotFirst := za.FindFirst(tc.text)
+			if gotFirst != tc.wantFirst {
+				t.Errorf("FindFirst(%q) = %d; want %d", tc.text, gotFirst, tc.wantFirst)
+			}
+			gotFirstBytes := za.FindFirstBytes([]byte(tc.text))
+			if gotFirstBytes != tc.wantFirst {
+				t.Errorf("FindFirstBytes(%q) = %d; want %d", tc.text, gotFirstBytes, tc.wantFirst)
+			}
+
+			gotContains := za.Contains(tc.text)
+			if gotContains != tc.wantContains {
+				t.Errorf("Contains(%q) = %v; want %v", tc.text, gotContains, tc.wantContains)
+			}
+			gotContainsBytes := za.ContainsBytes([]byte(tc.text))
+			if gotContainsBytes != tc.wantContains {
+				t.Errorf("ContainsBytes(%q) = %v; want %v", tc.text, gotContainsBytes, tc.wantContains)
+			}
+
+			gotCount := za.Count(tc.text)
+			if gotCount != tc.wantCount {
+				t.Errorf("Count(%q) = %d; want %d", tc.text, gotCount, tc.wantCount)
+			}
+			gotCountBytes := za.CountBytes([]byte(tc.text))
+			if gotCountBytes != tc.wantCount {
+				t.Errorf("CountBytes(%q) = %d; want %d", tc.text, gotCountBytes, tc.wantCount)
+			}
+		})
+	}
+}
+
+func TestNonOverlapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		text    string
+		want    []int
+	}{
+		{"Repeated single character", "aa", "aaaa", []int{0, 2}},
+		{"Self-overlapping pattern", "ana", "banana", []int{1}},
+		{"Periodic pattern", "abab", "abababababab", []int{0, 4, 8}},
+		{"Border shorter than half", "abcab", "abcabcab", []int{0}},
+		{"Mismatch after partial match", "aab", "aaab", []int{1}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := New(tc.pattern, false).FindAll(tc.text)
+			if !equalIntSlices(got, tc.want) {
+				t.Errorf("FindAll(%q) = %v; want %v", tc.text, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestDifferential checks that Boyer-Moore, KMP and the Z-algorithm report the same
+// matches on random inputs. Small alphabets make repeated and overlapping matches common.
+func TestDifferential(t *testing.T) {
+	rng := rand.New(rand.NewPCG(7, 8))
+	randString := func(alphabet string, n int) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = alphabet[rng.IntN(len(alphabet))]
+		}
+		return string(b)
+	}
+
+	for iter := 0; iter < 5000; iter++ {
+		alphabet := "abAB"[:1+rng.IntN(4)]
+		pattern := randString(alphabet, rng.IntN(8))
+		text := randString(alphabet, rng.IntN(80))
+		ignoreCase := rng.IntN(2) == 0
+
+		want := boyermoore.New(pattern, ignoreCase).FindAll(text)
+		if got := kmp.New(pattern, ignoreCase).FindAll(text); !equalIntSlices(got, want) {
+			t.Fatalf("KMP FindAll(%q) with pattern %q, ignoreCase=%v = %v; Boyer-Moore gives %v",
+				text, pattern, ignoreCase, got, want)
+		}
+		if got := New(pattern, ignoreCase).FindAll(text); !equalIntSlices(got, want) {
+			t.Fatalf("ZAlgo FindAll(%q) with pattern %q, ignoreCase=%v = %v; Boyer-Moore gives %v",
+				text, pattern, ignoreCase, got, want)
+		}
+	}
+}
+
+func equalIntSlices(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}